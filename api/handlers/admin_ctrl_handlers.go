@@ -218,6 +218,10 @@ func (service *AdminHandler) DeleteMovieAdmin(c *gin.Context) {
 			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("movie provided with %v ID not found", movie.MovieID))
 			return
 		}
+		if errors.Is(err, services.ErrMovieHasPaidBookings) {
+			helpers.ClientError(c, http.StatusConflict, err.Error())
+			return
+		}
 		helpers.ServerError(c, err)
 		return
 	}
@@ -339,8 +343,12 @@ func (service *AdminHandler) NewCinemaHallAdmin(c *gin.Context) {
 		return
 	}
 
-	err := service.adminCtrl.AddNewCinemaHall(newCinemaHall.HallName, newCinemaHall.HallName, 0)
+	err := service.adminCtrl.AddNewCinemaHall(newCinemaHall.HallName, newCinemaHall.HallName, newCinemaHall.Currency, 0)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidCurrency) {
+			helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+			return
+		}
 		if errors.Is(err, services.ErrDuplicatedCinemaHall) {
 			helpers.ClientError(c, http.StatusConflict, "Cinema hall with this name and type already exists")
 			return
@@ -464,6 +472,157 @@ func (service *AdminHandler) DeleteCinemaHallSeatAdmin(c *gin.Context) {
 	})
 }
 
+func (service *AdminHandler) GenerateHallSeatsAdmin(c *gin.Context) {
+	var generateForm GenerateHallSeatsForm
+
+	if err := c.ShouldBindJSON(&generateForm); err != nil {
+		helpers.RespondWithValidationErrors(c, err, generateForm)
+		return
+	}
+
+	created, err := service.adminCtrl.GenerateHallSeats(models.HallSeatSpec{
+		HallID:          generateForm.HallID,
+		RowFrom:         generateForm.RowFrom,
+		RowTo:           generateForm.RowTo,
+		SeatFrom:        generateForm.SeatFrom,
+		SeatTo:          generateForm.SeatTo,
+		VIPRows:         generateForm.VIPRows,
+		AccessibleSeats: generateForm.AccessibleSeats,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidHallSeatSpec) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrCinemaHallNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("invalid hall_id: %d. Hall not found", generateForm.HallID))
+			return
+		}
+		if errors.Is(err, services.ErrCinemaHallNotEmpty) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("cinema hall %d already has seats", generateForm.HallID))
+			return
+		}
+		if errors.Is(err, services.ErrCinemaHallCapacityExceeded) {
+			helpers.ClientError(c, http.StatusConflict, "the spec has more seats than the capacity of the hall")
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Cinema-Hall Seats generated successfully",
+		"seatsCreated": created,
+	})
+}
+
+func (service *AdminHandler) CloneHallSeatsAdmin(c *gin.Context) {
+	var cloneForm CloneHallSeatsForm
+
+	if err := c.ShouldBindJSON(&cloneForm); err != nil {
+		helpers.RespondWithValidationErrors(c, err, cloneForm)
+		return
+	}
+
+	created, err := service.adminCtrl.CloneHallSeats(cloneForm.FromHallID, cloneForm.ToHallID)
+	if err != nil {
+		if errors.Is(err, services.ErrCinemaHallNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("invalid hall IDs: %d, %d. Hall not found", cloneForm.FromHallID, cloneForm.ToHallID))
+			return
+		}
+		if errors.Is(err, services.ErrCinemaSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("cinema hall %d has no seats to copy", cloneForm.FromHallID))
+			return
+		}
+		if errors.Is(err, services.ErrCinemaHallNotEmpty) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("cinema hall %d already has seats", cloneForm.ToHallID))
+			return
+		}
+		if errors.Is(err, services.ErrCinemaHallCapacityExceeded) {
+			helpers.ClientError(c, http.StatusConflict, "the copied seats exceed the capacity of the hall")
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Cinema-Hall Seats copied successfully",
+		"seatsCreated": created,
+	})
+}
+
+func (service *AdminHandler) HallLayoutAdmin(c *gin.Context) {
+	cinemaHallID, err := helpers.GetParameterFromURL(c, "cinemaHallID", "invalid cinema hall ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	hallLayout, err := service.adminCtrl.FetchHallLayout(cinemaHallID)
+	if err != nil {
+		if errors.Is(err, services.ErrHallLayoutNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("no layout uploaded for cinema hall %v", cinemaHallID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"hallLayout": hallLayout,
+	})
+}
+
+func (service *AdminHandler) EditHallLayoutAdmin(c *gin.Context) {
+	var hallLayoutForm HallLayoutForm
+
+	if err := c.ShouldBindJSON(&hallLayoutForm); err != nil {
+		helpers.RespondWithValidationErrors(c, err, hallLayoutForm)
+		return
+	}
+
+	hallLayout := models.HallLayout{
+		HallID:         hallLayoutForm.HallID,
+		Columns:        hallLayoutForm.Columns,
+		Rows:           hallLayoutForm.Rows,
+		ScreenPosition: hallLayoutForm.ScreenPosition,
+	}
+	for _, seat := range hallLayoutForm.Seats {
+		hallLayout.Seats = append(hallLayout.Seats, models.HallLayoutSeat{SeatRow: seat.SeatRow, SeatNumber: seat.SeatNumber, SeatType: seat.SeatType, GridX: seat.X, GridY: seat.Y, Angle: seat.Angle})
+	}
+	for _, marker := range hallLayoutForm.Markers {
+		hallLayout.Markers = append(hallLayout.Markers, models.HallLayoutMarker{MarkerType: marker.Type, GridX: marker.X, GridY: marker.Y, Width: marker.Width, Height: marker.Height})
+	}
+
+	created, err := service.adminCtrl.UploadHallLayout(hallLayout)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidHallLayout) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrCinemaHallNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("invalid hall_id: %d. Hall not found", hallLayoutForm.HallID))
+			return
+		}
+		if errors.Is(err, services.ErrHallLayoutIncomplete) {
+			helpers.ClientError(c, http.StatusConflict, "the layout must place every existing seat of the hall; delete the seats that are gone first")
+			return
+		}
+		if errors.Is(err, services.ErrCinemaHallCapacityExceeded) {
+			helpers.ClientError(c, http.StatusConflict, "the layout has more seats than the capacity of the hall")
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Hall layout uploaded successfully",
+		"seatsCreated": created,
+	})
+}
+
 func (service *AdminHandler) AllShowsAdmin(c *gin.Context) {
 
 	allShows, err := service.adminCtrl.FetchAllShowsForAdmin()
@@ -524,6 +683,11 @@ func (service *AdminHandler) NewShowAdmin(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, services.ErrExchangeRateNotFound) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("%v: set an exchange rate before pricing shows in this hall", err))
+			return
+		}
+
 		if errors.Is(err, services.ErrShowAlreadyExists) {
 			formattedTime := newShow.StartTime.Format("15:04:05")
 			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("Show already exists with the given time: %v", formattedTime))
@@ -583,6 +747,10 @@ func (service *AdminHandler) DeleteShowAdmin(c *gin.Context) {
 			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show with ID %d not found", show.ShowID))
 			return
 		}
+		if errors.Is(err, services.ErrShowHasPaidBookings) {
+			helpers.ClientError(c, http.StatusConflict, err.Error())
+			return
+		}
 		helpers.ServerError(c, err)
 		return
 	}
@@ -624,6 +792,10 @@ func (service *AdminHandler) EditShowSeatPriceAdmin(c *gin.Context) {
 
 	err := service.adminCtrl.UpdateShowSeat(showSeatPrice.SeatPrice, showSeatPrice.ShowSeatID)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidSeatPrice) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if errors.Is(err, services.ErrShowSeatNotFound) {
 			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show seat with ID %d not found", showSeatPrice.ShowSeatID))
 			return
@@ -637,5 +809,592 @@ func (service *AdminHandler) EditShowSeatPriceAdmin(c *gin.Context) {
 	})
 }
 
+func (service *AdminHandler) BulkEditShowSeatPriceAdmin(c *gin.Context) {
+	var showSeatPrice BulkEditShowSeatPriceForm
+
+	if err := c.ShouldBindJSON(&showSeatPrice); err != nil {
+		helpers.RespondWithValidationErrors(c, err, showSeatPrice)
+		return
+	}
+
+	updated, err := service.adminCtrl.UpdateShowSeatPrices(showSeatPrice.ShowID, showSeatPrice.SeatPrice, showSeatPrice.SeatType, showSeatPrice.RowFrom, showSeatPrice.RowTo)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBulkSeatPrice) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrShowNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show with ID %d not found", showSeatPrice.ShowID))
+			return
+		}
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("no seat of show ID %d matches the given filters", showSeatPrice.ShowID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Seat prices updated successfully",
+		"updatedSeats": updated,
+	})
+}
+
+func (service *AdminHandler) CopyShowSeatPriceAdmin(c *gin.Context) {
+	var copyPrices CopyShowSeatPriceForm
+
+	if err := c.ShouldBindJSON(&copyPrices); err != nil {
+		helpers.RespondWithValidationErrors(c, err, copyPrices)
+		return
+	}
+
+	updated, err := service.adminCtrl.CopyShowSeatPrices(copyPrices.FromShowID, copyPrices.ToShowID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBulkSeatPrice) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrShowNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show with ID %d or %d not found", copyPrices.FromShowID, copyPrices.ToShowID))
+			return
+		}
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("no priced seat of show ID %d matches a seat of show ID %d", copyPrices.FromShowID, copyPrices.ToShowID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Seat prices copied successfully",
+		"updatedSeats": updated,
+	})
+}
+
+func (service *AdminHandler) EditShowDynamicPricingAdmin(c *gin.Context) {
+	var dynamicPricing EditShowDynamicPricingForm
+
+	if err := c.ShouldBindJSON(&dynamicPricing); err != nil {
+		helpers.RespondWithValidationErrors(c, err, dynamicPricing)
+		return
+	}
+
+	err := service.adminCtrl.ConfigureDynamicPricing(dynamicPricing.ShowID, dynamicPricing.Enabled, dynamicPricing.MinPricePercent, dynamicPricing.MaxPricePercent)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDynamicPricing) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrShowNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show with ID %d not found", dynamicPricing.ShowID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dynamic pricing updated successfully",
+	})
+}
+
+func (service *AdminHandler) ShowSeatPriceHistoryAdmin(c *gin.Context) {
+	showSeatID, err := helpers.GetParameterFromURL(c, "showSeatID", "invalid show seat ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	priceHistory, err := service.adminCtrl.FetchSeatPriceHistory(showSeatID)
+	if err != nil {
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("no price history for show seat with ID %d", showSeatID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"priceHistory": priceHistory,
+	})
+}
+
 // func(service *AdminHandler) AllShowSeatsAdmin(c *gin.Context){}
 // func(service *AdminHandler) AllShowSeatsAdmin(c *gin.Context){}
+
+func (service *AdminHandler) AllPromoCodesAdmin(c *gin.Context) {
+
+	allPromoCodes, err := service.adminCtrl.FetchAllPromoCodes()
+	if err != nil {
+		if errors.Is(err, services.ErrPromoCodeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"message": "There is no promo code yet!",
+			})
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"allPromoCodes": allPromoCodes,
+	})
+}
+
+func (service *AdminHandler) NewPromoCodeAdmin(c *gin.Context) {
+	var newPromoCode NewPromoCodeForm
+
+	if err := c.ShouldBindJSON(&newPromoCode); err != nil {
+		helpers.RespondWithValidationErrors(c, err, newPromoCode)
+		return
+	}
+
+	err := service.adminCtrl.AddNewPromoCode(newPromoCode.Code, newPromoCode.Description, newPromoCode.DiscountType, newPromoCode.DiscountValue.String(),
+		newPromoCode.ValidFrom, newPromoCode.ValidUntil, newPromoCode.MaxUses, newPromoCode.MaxUsesPerUser, newPromoCode.MovieID,
+		newPromoCode.HallType, newPromoCode.SeatType)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPromoCode) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrPromoCodeAlreadyExists) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("promo code %s already exists", services.NormalizePromoCode(newPromoCode.Code)))
+			return
+		}
+		if errors.Is(err, services.ErrMovieNotFoundByID) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("movie with ID %d not found", newPromoCode.MovieID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "New promo code added successfully",
+	})
+}
+
+func (service *AdminHandler) PromoCodeAdmin(c *gin.Context) {
+	promoCodeID, err := helpers.GetParameterFromURL(c, "promoCodeID", "invalid promo code ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	promoCode, err := service.adminCtrl.FetchPromoCode(promoCodeID)
+	if err != nil {
+		if errors.Is(err, services.ErrPromoCodeNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("promo code not found by provided ID %v", promoCodeID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"promoCode": promoCode,
+	})
+}
+
+func (service *AdminHandler) EditPromoCodeAdmin(c *gin.Context) {
+	var promoCode EditPromoCodeForm
+
+	if err := c.ShouldBindJSON(&promoCode); err != nil {
+		helpers.RespondWithValidationErrors(c, err, promoCode)
+		return
+	}
+
+	err := service.adminCtrl.UpdatePromoCode(promoCode.PromoCodeID, promoCode.Code, promoCode.Description, promoCode.DiscountType, promoCode.DiscountValue.String(),
+		promoCode.ValidFrom, promoCode.ValidUntil, promoCode.MaxUses, promoCode.MaxUsesPerUser, promoCode.MovieID,
+		promoCode.HallType, promoCode.SeatType)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPromoCode) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrPromoCodeNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("promo code with ID %d not found", promoCode.PromoCodeID))
+			return
+		}
+		if errors.Is(err, services.ErrPromoCodeAlreadyExists) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("promo code %s already exists", services.NormalizePromoCode(promoCode.Code)))
+			return
+		}
+		if errors.Is(err, services.ErrMovieNotFoundByID) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("movie with ID %d not found", promoCode.MovieID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Promo code updated successfully",
+	})
+}
+
+func (service *AdminHandler) DeletePromoCodeAdmin(c *gin.Context) {
+	var promoCode DeletePromoCodeForm
+
+	if err := c.ShouldBindJSON(&promoCode); err != nil {
+		helpers.RespondWithValidationErrors(c, err, promoCode)
+		return
+	}
+
+	err := service.adminCtrl.DeletePromoCode(promoCode.PromoCodeID)
+	if err != nil {
+		if errors.Is(err, services.ErrPromoCodeNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("promo code with ID %d not found", promoCode.PromoCodeID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Promo code deleted successfully",
+	})
+}
+
+func (service *AdminHandler) AllPriceRulesAdmin(c *gin.Context) {
+
+	allPriceRules, err := service.adminCtrl.FetchAllPriceRules()
+	if err != nil {
+		if errors.Is(err, services.ErrPriceRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"message": "There is no price rule yet!",
+			})
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"allPriceRules": allPriceRules,
+	})
+}
+
+func (service *AdminHandler) NewPriceRuleAdmin(c *gin.Context) {
+	var newPriceRule NewPriceRuleForm
+
+	if err := c.ShouldBindJSON(&newPriceRule); err != nil {
+		helpers.RespondWithValidationErrors(c, err, newPriceRule)
+		return
+	}
+
+	err := service.adminCtrl.AddNewPriceRule(newPriceRule.RuleName, newPriceRule.HallType, newPriceRule.SeatType, newPriceRule.DayOfWeek,
+		newPriceRule.StartTimeFrom, newPriceRule.StartTimeUntil, newPriceRule.Price)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPriceRule) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "New price rule added successfully",
+	})
+}
+
+func (service *AdminHandler) EditPriceRuleAdmin(c *gin.Context) {
+	var priceRule EditPriceRuleForm
+
+	if err := c.ShouldBindJSON(&priceRule); err != nil {
+		helpers.RespondWithValidationErrors(c, err, priceRule)
+		return
+	}
+
+	err := service.adminCtrl.UpdatePriceRule(priceRule.PriceRuleID, priceRule.RuleName, priceRule.HallType, priceRule.SeatType, priceRule.DayOfWeek,
+		priceRule.StartTimeFrom, priceRule.StartTimeUntil, priceRule.Price)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPriceRule) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrPriceRuleNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("price rule with ID %d not found", priceRule.PriceRuleID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Price rule updated successfully",
+	})
+}
+
+func (service *AdminHandler) DeletePriceRuleAdmin(c *gin.Context) {
+	var priceRule DeletePriceRuleForm
+
+	if err := c.ShouldBindJSON(&priceRule); err != nil {
+		helpers.RespondWithValidationErrors(c, err, priceRule)
+		return
+	}
+
+	err := service.adminCtrl.DeletePriceRule(priceRule.PriceRuleID)
+	if err != nil {
+		if errors.Is(err, services.ErrPriceRuleNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("price rule with ID %d not found", priceRule.PriceRuleID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Price rule deleted successfully",
+	})
+}
+
+func (service *AdminHandler) AllTicketCategoriesAdmin(c *gin.Context) {
+
+	allTicketCategories, err := service.adminCtrl.FetchAllTicketCategories()
+	if err != nil {
+		if errors.Is(err, services.ErrTicketCategoryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"message": "There is no ticket category yet!",
+			})
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"allTicketCategories": allTicketCategories,
+	})
+}
+
+func (service *AdminHandler) NewTicketCategoryAdmin(c *gin.Context) {
+	var newTicketCategory NewTicketCategoryForm
+
+	if err := c.ShouldBindJSON(&newTicketCategory); err != nil {
+		helpers.RespondWithValidationErrors(c, err, newTicketCategory)
+		return
+	}
+
+	err := service.adminCtrl.AddNewTicketCategory(newTicketCategory.Name, newTicketCategory.PricePercent, newTicketCategory.MinAge, newTicketCategory.MaxAge)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTicketCategoryDefinition) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrTicketCategoryAlreadyExists) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("ticket category %s already exists", newTicketCategory.Name))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "New ticket category added successfully",
+	})
+}
+
+func (service *AdminHandler) EditTicketCategoryAdmin(c *gin.Context) {
+	var ticketCategory EditTicketCategoryForm
+
+	if err := c.ShouldBindJSON(&ticketCategory); err != nil {
+		helpers.RespondWithValidationErrors(c, err, ticketCategory)
+		return
+	}
+
+	err := service.adminCtrl.UpdateTicketCategory(ticketCategory.TicketCategoryID, ticketCategory.Name, ticketCategory.PricePercent, ticketCategory.MinAge, ticketCategory.MaxAge)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTicketCategoryDefinition) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrTicketCategoryNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("ticket category with ID %d not found", ticketCategory.TicketCategoryID))
+			return
+		}
+		if errors.Is(err, services.ErrTicketCategoryAlreadyExists) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("ticket category %s already exists", ticketCategory.Name))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ticket category updated successfully",
+	})
+}
+
+func (service *AdminHandler) AllConcessionItemsAdmin(c *gin.Context) {
+
+	allConcessionItems, err := service.adminCtrl.FetchAllConcessionItems()
+	if err != nil {
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"allConcessionItems": allConcessionItems,
+	})
+}
+
+func (service *AdminHandler) NewConcessionItemAdmin(c *gin.Context) {
+	var newConcessionItem NewConcessionItemForm
+
+	if err := c.ShouldBindJSON(&newConcessionItem); err != nil {
+		helpers.RespondWithValidationErrors(c, err, newConcessionItem)
+		return
+	}
+
+	// New items are on sale unless stated otherwise.
+	active := newConcessionItem.Active == nil || *newConcessionItem.Active
+
+	err := service.adminCtrl.AddNewConcessionItem(newConcessionItem.Name, newConcessionItem.Description, newConcessionItem.Category,
+		newConcessionItem.Price, newConcessionItem.Stock, active, newConcessionItem.ComboItems)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidConcessionItem) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrConcessionItemAlreadyExists) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("concession item %s already exists", newConcessionItem.Name))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "New concession item added successfully",
+	})
+}
+
+func (service *AdminHandler) EditConcessionItemAdmin(c *gin.Context) {
+	var concessionItem EditConcessionItemForm
+
+	if err := c.ShouldBindJSON(&concessionItem); err != nil {
+		helpers.RespondWithValidationErrors(c, err, concessionItem)
+		return
+	}
+
+	active := concessionItem.Active == nil || *concessionItem.Active
+
+	err := service.adminCtrl.UpdateConcessionItem(concessionItem.ConcessionItemID, concessionItem.Name, concessionItem.Description, concessionItem.Category,
+		concessionItem.Price, concessionItem.Stock, active, concessionItem.ComboItems)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidConcessionItem) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrConcessionItemNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("concession item with ID %d not found", concessionItem.ConcessionItemID))
+			return
+		}
+		if errors.Is(err, services.ErrConcessionItemAlreadyExists) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("concession item %s already exists", concessionItem.Name))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Concession item updated successfully",
+	})
+}
+
+func (service *AdminHandler) DeleteConcessionItemAdmin(c *gin.Context) {
+	var concessionItem DeleteConcessionItemForm
+
+	if err := c.ShouldBindJSON(&concessionItem); err != nil {
+		helpers.RespondWithValidationErrors(c, err, concessionItem)
+		return
+	}
+
+	err := service.adminCtrl.DeleteConcessionItem(concessionItem.ConcessionItemID)
+	if err != nil {
+		if errors.Is(err, services.ErrConcessionItemNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("concession item with ID %d not found", concessionItem.ConcessionItemID))
+			return
+		}
+		if errors.Is(err, services.ErrConcessionItemInUse) {
+			helpers.ClientError(c, http.StatusConflict, err.Error())
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Concession item deleted successfully",
+	})
+}
+
+func (service *AdminHandler) AllExchangeRatesAdmin(c *gin.Context) {
+
+	allExchangeRates, err := service.adminCtrl.FetchExchangeRates()
+	if err != nil {
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"defaultCurrency":  models.DefaultCurrency,
+		"allExchangeRates": allExchangeRates,
+	})
+}
+
+func (service *AdminHandler) EditExchangeRateAdmin(c *gin.Context) {
+	var exchangeRate EditExchangeRateForm
+
+	if err := c.ShouldBindJSON(&exchangeRate); err != nil {
+		helpers.RespondWithValidationErrors(c, err, exchangeRate)
+		return
+	}
+
+	err := service.adminCtrl.SetExchangeRate(exchangeRate.Currency, exchangeRate.Rate.String())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCurrency) || errors.Is(err, services.ErrInvalidExchangeRate) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Exchange rate updated successfully",
+	})
+}
+
+func (service *AdminHandler) DeleteExchangeRateAdmin(c *gin.Context) {
+	var exchangeRate DeleteExchangeRateForm
+
+	if err := c.ShouldBindJSON(&exchangeRate); err != nil {
+		helpers.RespondWithValidationErrors(c, err, exchangeRate)
+		return
+	}
+
+	err := service.adminCtrl.DeleteExchangeRate(exchangeRate.Currency)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCurrency) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrExchangeRateNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("no exchange rate for %s", exchangeRate.Currency))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Exchange rate deleted successfully",
+	})
+}
@@ -5,7 +5,10 @@ import (
 	"cinemaGo/backend/internal/services"
 	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -58,12 +61,22 @@ func (service *BookingHandler) ShowSeats(c *gin.Context) {
 		return
 	}
 
-	showSeats, err := service.booking.FetchShowSeats(showID)
+	showSeats, seatsSummary, err := service.booking.FetchShowSeats(showID, c.Query("currency"))
 	if err != nil {
 		if errors.Is(err, services.ErrShowSeatNotFound) {
 			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show ID %v not found", showID))
 			return
 		}
+		if errors.Is(err, services.ErrInvalidCurrency) || errors.Is(err, services.ErrExchangeRateNotFound) {
+			helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	seatGrid, err := service.booking.FetchSeatGrid(showID, showSeats)
+	if err != nil {
 		helpers.ServerError(c, err)
 		return
 	}
@@ -79,11 +92,41 @@ func (service *BookingHandler) ShowSeats(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"showSeats": showSeats,
-		"showInfo":  showInfo,
+		"showSeats":    showSeats,
+		"seatsSummary": seatsSummary,
+		"showInfo":     showInfo,
+		"seatGrid":     seatGrid,
 	})
 }
 
+func (service *BookingHandler) SeatMapSVG(c *gin.Context) {
+	showID, err := helpers.GetParameterFromURL(c, "showID", "invalid show ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	// The seats of a booking are only marked for its owner or for whoever holds its ticket.
+	user_id := 0
+	if userID, ok := c.Get("userID"); ok {
+		user_id = int(userID.(float64))
+	}
+
+	svg, err := service.booking.FetchSeatMapSVG(showID, c.Query("highlight"), user_id, c.Query("ticket"))
+	if err != nil {
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show ID %v not found", showID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	// Seat statuses change all the time, so the map must not be served from a cache.
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/svg+xml", svg)
+}
+
 func (service *BookingHandler) BookSeats(c *gin.Context) {
 	var bookingForm BookingForm
 
@@ -100,17 +143,177 @@ func (service *BookingHandler) BookSeats(c *gin.Context) {
 	userID, _ := c.Get("userID")
 	user_id := int(userID.(float64))
 
-	err := service.booking.CreateNewBooking(bookingForm.ShowID, user_id, bookingForm.ShowSeatsID)
+	createdBooking, err := service.booking.CreateNewBooking(bookingForm.ShowID, user_id, bookingForm.ShowSeatsID, bookingForm.TicketCategories, bookingForm.Concessions, bookingForm.PromoCode)
+	if err != nil {
+		if errors.Is(err, services.ErrShowNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show ID %v not found", bookingForm.ShowID))
+			return
+		}
+
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("one or more of the selected seats do not belong to show ID %v", bookingForm.ShowID))
+			return
+		}
+
+		if errors.Is(err, services.ErrShowSeatHasSelected) {
+			helpers.ClientError(c, http.StatusConflict, "Sorry! These seats are no longer available. Please try again with other seats.")
+			return
+		}
+		if errors.Is(err, services.ErrSingleSeatGap) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if errors.Is(err, services.ErrTooManySeats) {
+			helpers.ClientError(c, http.StatusBadRequest, "You can select a maximum of 5 seats at a time.")
+			return
+		}
+
+		if errors.Is(err, services.ErrShowSeatNotPriced) {
+			helpers.ClientError(c, http.StatusConflict, "Sorry! Some of these seats are not on sale yet. Please try again with other seats.")
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidTicketCategory) || errors.Is(err, services.ErrTicketCategoryAgeRestricted) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidConcessionOrder) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if errors.Is(err, services.ErrConcessionOutOfStock) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("Sorry! %v.", err))
+			return
+		}
+
+		if errors.Is(err, services.ErrPromoCodeNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("promo code %s not found", services.NormalizePromoCode(bookingForm.PromoCode)))
+			return
+		}
+
+		if errors.Is(err, services.ErrPromoCodeNotActive) || errors.Is(err, services.ErrPromoCodeUsageLimitReached) || errors.Is(err, services.ErrPromoCodeNotApplicable) {
+			helpers.ClientError(c, http.StatusUnprocessableEntity, fmt.Sprintf("Sorry! This promo code cannot be used: %v.", err))
+			return
+		}
+
+		if errors.Is(err, services.ErrPaymentDeclined) {
+			helpers.ClientError(c, http.StatusPaymentRequired, "Sorry! Your payment was declined. Please try another payment method.")
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Booking %s received! Your seats are reserved while we confirm your payment.", createdBooking.BookingReference),
+		"booking": createdBooking,
+	})
+}
+
+func (service *BookingHandler) QuoteBooking(c *gin.Context) {
+	var bookingForm BookingForm
+
+	if err := c.ShouldBindJSON(&bookingForm); err != nil {
+		helpers.RespondWithValidationErrors(c, err, bookingForm)
+		return
+	}
+
+	if err := helpers.ValidateSeatsID(bookingForm.ShowSeatsID); err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	quote, err := service.booking.QuoteBooking(bookingForm.ShowID, bookingForm.ShowSeatsID, bookingForm.TicketCategories, bookingForm.Concessions, bookingForm.PromoCode)
 	if err != nil {
 		if errors.Is(err, services.ErrShowNotFound) {
 			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show ID %v not found", bookingForm.ShowID))
 			return
 		}
 
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("one or more of the selected seats do not belong to show ID %v", bookingForm.ShowID))
+			return
+		}
+
+		if errors.Is(err, services.ErrTooManySeats) {
+			helpers.ClientError(c, http.StatusBadRequest, "You can select a maximum of 5 seats at a time.")
+			return
+		}
+
+		if errors.Is(err, services.ErrShowSeatNotPriced) {
+			helpers.ClientError(c, http.StatusConflict, "Sorry! Some of these seats are not on sale yet. Please try again with other seats.")
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidTicketCategory) || errors.Is(err, services.ErrTicketCategoryAgeRestricted) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidConcessionOrder) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if errors.Is(err, services.ErrConcessionOutOfStock) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("Sorry! %v.", err))
+			return
+		}
+
+		if errors.Is(err, services.ErrPromoCodeNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("promo code %s not found", services.NormalizePromoCode(bookingForm.PromoCode)))
+			return
+		}
+
+		if errors.Is(err, services.ErrPromoCodeNotActive) || errors.Is(err, services.ErrPromoCodeUsageLimitReached) || errors.Is(err, services.ErrPromoCodeNotApplicable) {
+			helpers.ClientError(c, http.StatusUnprocessableEntity, fmt.Sprintf("Sorry! This promo code cannot be used: %v.", err))
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"quote": quote,
+	})
+}
+
+func (service *BookingHandler) HoldSeats(c *gin.Context) {
+	var holdForm SeatHoldForm
+
+	if err := c.ShouldBindJSON(&holdForm); err != nil {
+		helpers.RespondWithValidationErrors(c, err, holdForm)
+		return
+	}
+
+	if err := helpers.ValidateSeatsID(holdForm.ShowSeatsID); err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	seatHold, err := service.booking.HoldSeats(holdForm.ShowID, user_id, holdForm.ShowSeatsID)
+	if err != nil {
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("one or more of the selected seats do not belong to show ID %v", holdForm.ShowID))
+			return
+		}
+
 		if errors.Is(err, services.ErrShowSeatHasSelected) {
 			helpers.ClientError(c, http.StatusConflict, "Sorry! These seats are no longer available. Please try again with other seats.")
 			return
 		}
+		if errors.Is(err, services.ErrSingleSeatGap) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
 
 		if errors.Is(err, services.ErrTooManySeats) {
 			helpers.ClientError(c, http.StatusBadRequest, "You can select a maximum of 5 seats at a time.")
@@ -121,7 +324,784 @@ func (service *BookingHandler) BookSeats(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK,gin.H{
-		"message":"Booking successful! Your seats are reserved, and payment has been completed. Enjoy the show!",
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Your seats are on hold. Please complete the payment before the hold expires.",
+		"seatHold": seatHold,
+	})
+}
+
+func (service *BookingHandler) ReleaseSeatHold(c *gin.Context) {
+	holdID, err := helpers.GetParameterFromURL(c, "holdID", "invalid hold ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	err = service.booking.ReleaseSeatHold(holdID, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrSeatHoldNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("active seat hold with ID %d not found", holdID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Your seats have been released.",
+	})
+}
+
+func (service *BookingHandler) PaymentWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, "invalid webhook payload")
+		return
+	}
+
+	err = service.booking.HandlePaymentWebhook(payload, c.GetHeader("X-Payment-Signature"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhookSignature) {
+			helpers.ClientError(c, http.StatusUnauthorized, "invalid webhook signature")
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidWebhookPayload) {
+			helpers.ClientError(c, http.StatusBadRequest, "invalid webhook payload")
+			return
+		}
+
+		if errors.Is(err, services.ErrPaymentNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, "payment not found")
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook processed",
+	})
+}
+
+func (service *BookingHandler) CancelBooking(c *gin.Context) {
+	bookingID, err := helpers.GetParameterFromURL(c, "bookingID", "invalid booking ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	cancelledBooking, err := service.booking.CancelBooking(bookingID, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrBookingNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("booking with ID %d not found", bookingID))
+			return
+		}
+
+		if errors.Is(err, services.ErrBookingNotCancellable) {
+			helpers.ClientError(c, http.StatusConflict, "Only confirmed bookings can be cancelled.")
+			return
+		}
+
+		if errors.Is(err, services.ErrCancellationWindowClosed) {
+			helpers.ClientError(c, http.StatusConflict, "Sorry! This booking can no longer be cancelled.")
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Your booking has been cancelled.",
+		"cancelledBooking": cancelledBooking,
+	})
+}
+
+func (service *BookingHandler) RefundBookingAdmin(c *gin.Context) {
+	bookingID, err := helpers.GetParameterFromURL(c, "bookingID", "invalid booking ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	var refundForm RefundBookingForm
+
+	if err := c.ShouldBindJSON(&refundForm); err != nil {
+		helpers.RespondWithValidationErrors(c, err, refundForm)
+		return
+	}
+
+	// Bookings are refunded in full unless the admin says otherwise.
+	refundPercent := 100
+	if refundForm.RefundPercent != nil {
+		refundPercent = *refundForm.RefundPercent
+	}
+
+	cancelledBooking, err := service.booking.RefundBooking(bookingID, refundPercent, refundForm.Reason)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefundPercent) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if errors.Is(err, services.ErrBookingNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("booking with ID %d not found", bookingID))
+			return
+		}
+
+		if errors.Is(err, services.ErrBookingNotCancellable) {
+			helpers.ClientError(c, http.StatusConflict, "Only paid bookings that have not been checked in can be refunded.")
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Booking cancelled and refunded successfully",
+		"cancelledBooking": cancelledBooking,
+	})
+}
+
+func (service *BookingHandler) MyBookings(c *gin.Context) {
+	page, err := helpers.GetIntFromQuery(c, "page", 1, "invalid page provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	pageSize, err := helpers.GetIntFromQuery(c, "page_size", 10, "invalid page size provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	bookings, err := service.booking.FetchUserBookings(user_id, c.Query("when"), page, pageSize)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBookingFilter) {
+			helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bookings": bookings,
+	})
+}
+
+func (service *BookingHandler) MyBooking(c *gin.Context) {
+	bookingID, err := helpers.GetParameterFromURL(c, "bookingID", "invalid booking ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	booking, err := service.booking.FetchUserBooking(bookingID, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrBookingNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("booking with ID %d not found", bookingID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"booking": booking,
+	})
+}
+
+func (service *BookingHandler) BookingTicket(c *gin.Context) {
+	bookingID, err := helpers.GetParameterFromURL(c, "bookingID", "invalid booking ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	ticket, err := service.booking.IssueTicket(bookingID, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrBookingNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("booking with ID %d not found", bookingID))
+			return
+		}
+
+		if errors.Is(err, services.ErrTicketNotAvailable) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("%v", err))
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticket": ticket,
+	})
+}
+
+func (service *BookingHandler) BookingTicketQRCode(c *gin.Context) {
+	bookingID, err := helpers.GetParameterFromURL(c, "bookingID", "invalid booking ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	ticket, err := service.booking.IssueTicket(bookingID, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrBookingNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("booking with ID %d not found", bookingID))
+			return
+		}
+
+		if errors.Is(err, services.ErrTicketNotAvailable) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("%v", err))
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	png, err := services.TicketQRCode(ticket.Token)
+	if err != nil {
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+func (service *BookingHandler) BookingReceipt(c *gin.Context) {
+	bookingID, err := helpers.GetParameterFromURL(c, "bookingID", "invalid booking ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	receipt, err := service.booking.FetchReceipt(bookingID, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrBookingNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("booking with ID %d not found", bookingID))
+			return
+		}
+
+		if errors.Is(err, services.ErrReceiptNotAvailable) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("%v", err))
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"receipt": receipt,
+	})
+}
+
+func (service *BookingHandler) BookingReceiptPDF(c *gin.Context) {
+	bookingID, err := helpers.GetParameterFromURL(c, "bookingID", "invalid booking ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	receipt, err := service.booking.FetchReceipt(bookingID, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrBookingNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("booking with ID %d not found", bookingID))
+			return
+		}
+
+		if errors.Is(err, services.ErrReceiptNotAvailable) {
+			helpers.ClientError(c, http.StatusConflict, fmt.Sprintf("%v", err))
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", receipt.ReceiptNumber+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", services.RenderReceiptPDF(receipt))
+}
+
+func (service *BookingHandler) CheckIn(c *gin.Context) {
+	var checkIn CheckInForm
+
+	if err := c.ShouldBindJSON(&checkIn); err != nil {
+		helpers.RespondWithValidationErrors(c, err, checkIn)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	checkedInBooking, err := service.booking.CheckInTicket(checkIn.TicketToken, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTicket) {
+			helpers.ClientError(c, http.StatusBadRequest, "Invalid ticket. Please check it at the box office.")
+			return
+		}
+
+		if errors.Is(err, services.ErrTicketNotAvailable) {
+			helpers.ClientError(c, http.StatusConflict, "This ticket's booking is not confirmed.")
+			return
+		}
+
+		if errors.Is(err, services.ErrTicketAlreadyUsed) {
+			helpers.ClientError(c, http.StatusConflict, "This ticket has already been used.")
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ticket checked in. Enjoy the show!",
+		"booking": checkedInBooking,
+	})
+}
+
+func (service *BookingHandler) TicketCategories(c *gin.Context) {
+	ticketCategories, err := service.booking.FetchTicketCategories()
+	if err != nil {
+		if errors.Is(err, services.ErrTicketCategoryNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, "no ticket category is on sale")
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticketCategories": ticketCategories,
+	})
+}
+
+func (service *BookingHandler) Concessions(c *gin.Context) {
+	concessionItems, err := service.booking.FetchConcessionItems()
+	if err != nil {
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"concessionItems": concessionItems,
+	})
+}
+
+func (service *BookingHandler) BookingByReference(c *gin.Context) {
+	bookingReference := c.Param("bookingReference")
+
+	booking, err := service.booking.FetchBookingByReference(bookingReference)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBookingReference) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if errors.Is(err, services.ErrBookingNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("booking %s not found", bookingReference))
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"booking": booking,
+	})
+}
+
+func (service *BookingHandler) CollectConcessions(c *gin.Context) {
+	var collectForm CollectConcessionsForm
+
+	if err := c.ShouldBindJSON(&collectForm); err != nil {
+		helpers.RespondWithValidationErrors(c, err, collectForm)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	concessions, err := service.booking.CollectConcessions(collectForm.TicketToken, user_id, collectForm.BookingConcessionIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTicket) {
+			helpers.ClientError(c, http.StatusBadRequest, "Invalid ticket. Please check it at the box office.")
+			return
+		}
+
+		if errors.Is(err, services.ErrTicketNotAvailable) {
+			helpers.ClientError(c, http.StatusConflict, "This ticket's booking is not confirmed.")
+			return
+		}
+
+		if errors.Is(err, services.ErrConcessionsAlreadyCollected) {
+			helpers.ClientError(c, http.StatusConflict, "There is nothing left to collect for this ticket.")
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Order handed over. Enjoy the show!",
+		"concessions": concessions,
+	})
+}
+
+func (service *BookingHandler) BestSeats(c *gin.Context) {
+	showID, err := helpers.GetParameterFromURL(c, "showID", "invalid show ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	count, err := helpers.GetIntFromQuery(c, "count", 2, "invalid seat count provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	bestSeats, err := service.booking.SuggestBestSeats(showID, count, c.Query("type"), c.Query("currency"))
+	if err != nil {
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show ID %v not found", showID))
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidCurrency) || errors.Is(err, services.ErrExchangeRateNotFound) {
+			helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+			return
+		}
+
+		if errors.Is(err, services.ErrTooManySeats) {
+			helpers.ClientError(c, http.StatusBadRequest, "You can select a maximum of 5 seats at a time.")
+			return
+		}
+
+		if errors.Is(err, services.ErrNoAdjacentSeats) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("Sorry! There are no %d adjacent seats available for this show.", count))
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bestSeats": bestSeats,
+	})
+}
+
+func (service *BookingHandler) HoldBestSeats(c *gin.Context) {
+	showID, err := helpers.GetParameterFromURL(c, "showID", "invalid show ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	count, err := helpers.GetIntFromQuery(c, "count", 2, "invalid seat count provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	bestSeats, seatHold, err := service.booking.HoldBestSeats(showID, user_id, count, c.Query("type"))
+	if err != nil {
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show ID %v not found", showID))
+			return
+		}
+
+		if errors.Is(err, services.ErrTooManySeats) {
+			helpers.ClientError(c, http.StatusBadRequest, "You can select a maximum of 5 seats at a time.")
+			return
+		}
+
+		if errors.Is(err, services.ErrNoAdjacentSeats) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("Sorry! There are no %d adjacent seats available for this show.", count))
+			return
+		}
+
+		if errors.Is(err, services.ErrShowSeatHasSelected) {
+			helpers.ClientError(c, http.StatusConflict, "Sorry! These seats were just taken. Please try again.")
+			return
+		}
+		if errors.Is(err, services.ErrSingleSeatGap) {
+			helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Your seats are on hold. Please complete the payment before the hold expires.",
+		"bestSeats": bestSeats,
+		"seatHold":  seatHold,
+	})
+}
+
+func (service *BookingHandler) JoinWaitlist(c *gin.Context) {
+	showID, err := helpers.GetParameterFromURL(c, "showID", "invalid show ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	count, err := helpers.GetIntFromQuery(c, "count", 1, "invalid seat count provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	waitlistEntry, err := service.booking.JoinWaitlist(showID, user_id, count)
+	if err != nil {
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show ID %v not found", showID))
+			return
+		}
+
+		if errors.Is(err, services.ErrTooManySeats) {
+			helpers.ClientError(c, http.StatusBadRequest, "You can wait for a maximum of 5 seats at a time.")
+			return
+		}
+
+		if errors.Is(err, services.ErrShowNotSoldOut) {
+			helpers.ClientError(c, http.StatusConflict, "This show still has seats available. Please book them directly.")
+			return
+		}
+
+		if errors.Is(err, services.ErrAlreadyWaitlisted) {
+			helpers.ClientError(c, http.StatusConflict, "You are already on the waitlist of this show.")
+			return
+		}
+
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":       "You are on the waitlist. We will hold seats for you as soon as they free up.",
+		"waitlistEntry": waitlistEntry,
+	})
+}
+
+func (service *BookingHandler) WaitlistStatus(c *gin.Context) {
+	showID, err := helpers.GetParameterFromURL(c, "showID", "invalid show ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	waitlistEntry, err := service.booking.FetchWaitlistEntry(showID, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrWaitlistEntryNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("You are not on the waitlist of show ID %v.", showID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"waitlistEntry": waitlistEntry,
+	})
+}
+
+func (service *BookingHandler) WaitlistStream(c *gin.Context) {
+	showID, err := helpers.GetParameterFromURL(c, "showID", "invalid show ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	// Subscribe before reading the entry, so no offer between the two is missed.
+	offers, unsubscribe := service.booking.WatchWaitlistOffers(user_id)
+	defer unsubscribe()
+
+	waitlistEntry, err := service.booking.FetchWaitlistEntry(showID, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrWaitlistEntryNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("You are not on the waitlist of show ID %v.", showID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("waitlist", gin.H{
+		"waitlistEntry": waitlistEntry,
+	})
+
+	// Keep proxies from closing an idle stream.
+	keepAlive := time.NewTicker(25 * time.Second)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-keepAlive.C:
+			c.SSEvent("ping", gin.H{})
+			return true
+		case offer, ok := <-offers:
+			if !ok {
+				return false
+			}
+
+			// The customer may be waiting for several shows; only this one is streamed here.
+			if offer.SeatHold.ShowID != showID {
+				return true
+			}
+
+			c.SSEvent("offer", gin.H{
+				"waitlistEntryID": offer.WaitlistEntryID,
+				"holdID":          offer.SeatHold.HoldID,
+				"showSeatsID":     offer.SeatHold.ShowSeatsID,
+				"expiresAt":       offer.SeatHold.ExpiresAt,
+			})
+			return true
+		}
+	})
+}
+
+func (service *BookingHandler) LeaveWaitlist(c *gin.Context) {
+	showID, err := helpers.GetParameterFromURL(c, "showID", "invalid show ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	user_id := int(userID.(float64))
+
+	err = service.booking.LeaveWaitlist(showID, user_id)
+	if err != nil {
+		if errors.Is(err, services.ErrWaitlistEntryNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("You are not on the waitlist of show ID %v.", showID))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "You have left the waitlist.",
+	})
+}
+
+func (service *BookingHandler) ShowSeatsStream(c *gin.Context) {
+	showID, err := helpers.GetParameterFromURL(c, "showID", "invalid show ID provided.")
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return
+	}
+
+	// Subscribe before reading the seats, so no change between the two is missed.
+	changes, unsubscribe := service.booking.WatchShowSeats(showID)
+	defer unsubscribe()
+
+	showSeats, seatsSummary, err := service.booking.FetchShowSeats(showID, c.Query("currency"))
+	if err != nil {
+		if errors.Is(err, services.ErrShowSeatNotFound) {
+			helpers.ClientError(c, http.StatusNotFound, fmt.Sprintf("show ID %v not found", showID))
+			return
+		}
+		if errors.Is(err, services.ErrInvalidCurrency) || errors.Is(err, services.ErrExchangeRateNotFound) {
+			helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
+			return
+		}
+		helpers.ServerError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("seats", gin.H{
+		"showSeats":    showSeats,
+		"seatsSummary": seatsSummary,
+	})
+
+	// Keep proxies from closing an idle stream.
+	keepAlive := time.NewTicker(25 * time.Second)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-keepAlive.C:
+			c.SSEvent("ping", gin.H{})
+			return true
+		case change, ok := <-changes:
+			if !ok {
+				return false
+			}
+
+			showSeats, seatsSummary, err := service.booking.FetchShowSeats(showID, c.Query("currency"))
+			if err != nil {
+				log.Println(err)
+				return false
+			}
+
+			c.SSEvent("seats", gin.H{
+				"reason":       change.Reason,
+				"showSeats":    showSeats,
+				"seatsSummary": seatsSummary,
+			})
+			return true
+		}
 	})
 }
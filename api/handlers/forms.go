@@ -1,6 +1,10 @@
 package handlers
 
-import "time"
+import (
+	"cinemaGo/backend/internal/models"
+	"encoding/json"
+	"time"
+)
 
 type newUserForm struct {
 	Name            string `json:"name" binding:"required"`
@@ -9,6 +13,7 @@ type newUserForm struct {
 	PhoneNumber     string `json:"phone_number" binding:"required"`
 	Password        string `json:"password" binding:"required"`
 	ConfirmPassword string `json:"confirm_password" binding:"required"`
+	Country         string `json:"country"`
 }
 type userLoginForm struct {
 	Email    string `json:"email" binding:"required"`
@@ -19,9 +24,18 @@ type userInfoUpdateFrom struct {
 	Name        string `json:"name" binding:"required"`
 	Surname     string `json:"surname" binding:"required"`
 	PhoneNumber string `json:"phone_number" binding:"required"`
+	Country     string `json:"country"`
 }
 
 type BookingForm struct {
+	ShowID           int            `json:"show_id" binding:"required"`
+	ShowSeatsID      []int          `json:"show_seats_id" binding:"required"`
+	TicketCategories map[int]string `json:"ticket_categories"`
+	Concessions      map[int]int    `json:"concessions"`
+	PromoCode        string         `json:"promo_code"`
+}
+
+type SeatHoldForm struct {
 	ShowID      int   `json:"show_id" binding:"required"`
 	ShowSeatsID []int `json:"show_seats_id" binding:"required"`
 }
@@ -38,7 +52,7 @@ type EditCarouselImageForm struct {
 	ImageURL        string `json:"carousel_image_image_url" binding:"required"`
 	Title           string `json:"carousel_image_title" binding:"required"`
 	Description     string `json:"carousel_image_description" binding:"required"`
-	OrderPriority   string    `json:"carousel_image_order_priority" binding:"required"`
+	OrderPriority   string `json:"carousel_image_order_priority" binding:"required"`
 }
 
 type DeleteCarouselImageForm struct {
@@ -110,6 +124,7 @@ type NewCinemaHallForm struct {
 	HallName string `json:"hall_name" binding:"required"`
 	HallType string `json:"hall_type" binding:"required"`
 	Capacity int    `json:"capacity" binding:"required"`
+	Currency string `json:"currency"`
 }
 
 type EditCinemaHallForm struct {
@@ -134,6 +149,47 @@ type DeleteCinemaSeatForm struct {
 	CinemaSeatID int `json:"cinema_seat_id" binding:"required"`
 }
 
+type GenerateHallSeatsForm struct {
+	HallID          int      `json:"hall_id" binding:"required"`
+	RowFrom         string   `json:"row_from" binding:"required"`
+	RowTo           string   `json:"row_to" binding:"required"`
+	SeatFrom        int      `json:"seat_from" binding:"required"`
+	SeatTo          int      `json:"seat_to" binding:"required"`
+	VIPRows         []string `json:"vip_rows"`
+	AccessibleSeats []string `json:"accessible_seats"`
+}
+
+type CloneHallSeatsForm struct {
+	FromHallID int `json:"from_hall_id" binding:"required"`
+	ToHallID   int `json:"to_hall_id" binding:"required"`
+}
+
+type HallLayoutForm struct {
+	HallID         int                    `json:"hall_id" binding:"required"`
+	Columns        int                    `json:"columns" binding:"required"`
+	Rows           int                    `json:"rows" binding:"required"`
+	ScreenPosition string                 `json:"screen_position"`
+	Seats          []HallLayoutSeatForm   `json:"seats" binding:"required"`
+	Markers        []HallLayoutMarkerForm `json:"markers"`
+}
+
+type HallLayoutSeatForm struct {
+	SeatRow    string `json:"seat_row"`
+	SeatNumber int    `json:"seat_number"`
+	SeatType   string `json:"seat_type"`
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	Angle      int    `json:"angle"`
+}
+
+type HallLayoutMarkerForm struct {
+	Type   string `json:"type"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
 type NewShowForm struct {
 	ShowDate  time.Time `json:"show_date" binding:"required"`
 	StartTime time.Time `json:"start_time" binding:"required"`
@@ -154,6 +210,147 @@ type DeleteShowForm struct {
 }
 
 type EditShowSeatForm struct {
-	SeatPrice  float32 `json:"seat_price" binding:"required"`
-	ShowSeatID int     `json:"show_seat_id" binding:"required"`
+	SeatPrice  models.Money `json:"seat_price" binding:"required"`
+	ShowSeatID int          `json:"show_seat_id" binding:"required"`
+}
+
+type BulkEditShowSeatPriceForm struct {
+	ShowID    int          `json:"show_id" binding:"required"`
+	SeatPrice models.Money `json:"seat_price" binding:"required"`
+	SeatType  string       `json:"seat_type"`
+	RowFrom   string       `json:"row_from"`
+	RowTo     string       `json:"row_to"`
+}
+
+type CopyShowSeatPriceForm struct {
+	FromShowID int `json:"from_show_id" binding:"required"`
+	ToShowID   int `json:"to_show_id" binding:"required"`
+}
+
+type EditShowDynamicPricingForm struct {
+	ShowID          int  `json:"show_id" binding:"required"`
+	Enabled         bool `json:"enabled"`
+	MinPricePercent int  `json:"min_price_percent" binding:"required"`
+	MaxPricePercent int  `json:"max_price_percent" binding:"required"`
+}
+
+type NewPromoCodeForm struct {
+	Code           string      `json:"code" binding:"required"`
+	Description    string      `json:"description"`
+	DiscountType   string      `json:"discount_type" binding:"required"`
+	DiscountValue  json.Number `json:"discount_value" binding:"required"`
+	ValidFrom      time.Time   `json:"valid_from" binding:"required"`
+	ValidUntil     time.Time   `json:"valid_until" binding:"required"`
+	MaxUses        int         `json:"max_uses"`
+	MaxUsesPerUser int         `json:"max_uses_per_user"`
+	MovieID        int         `json:"movie_id"`
+	HallType       string      `json:"hall_type"`
+	SeatType       string      `json:"seat_type"`
+}
+
+type EditPromoCodeForm struct {
+	PromoCodeID    int         `json:"promo_code_id" binding:"required"`
+	Code           string      `json:"code" binding:"required"`
+	Description    string      `json:"description"`
+	DiscountType   string      `json:"discount_type" binding:"required"`
+	DiscountValue  json.Number `json:"discount_value" binding:"required"`
+	ValidFrom      time.Time   `json:"valid_from" binding:"required"`
+	ValidUntil     time.Time   `json:"valid_until" binding:"required"`
+	MaxUses        int         `json:"max_uses"`
+	MaxUsesPerUser int         `json:"max_uses_per_user"`
+	MovieID        int         `json:"movie_id"`
+	HallType       string      `json:"hall_type"`
+	SeatType       string      `json:"seat_type"`
+}
+
+type DeletePromoCodeForm struct {
+	PromoCodeID int `json:"promo_code_id" binding:"required"`
+}
+
+type NewPriceRuleForm struct {
+	RuleName       string       `json:"rule_name" binding:"required"`
+	HallType       string       `json:"hall_type"`
+	SeatType       string       `json:"seat_type"`
+	DayOfWeek      *int         `json:"day_of_week"`
+	StartTimeFrom  string       `json:"start_time_from"`
+	StartTimeUntil string       `json:"start_time_until"`
+	Price          models.Money `json:"price" binding:"required"`
+}
+
+type EditPriceRuleForm struct {
+	PriceRuleID    int          `json:"price_rule_id" binding:"required"`
+	RuleName       string       `json:"rule_name" binding:"required"`
+	HallType       string       `json:"hall_type"`
+	SeatType       string       `json:"seat_type"`
+	DayOfWeek      *int         `json:"day_of_week"`
+	StartTimeFrom  string       `json:"start_time_from"`
+	StartTimeUntil string       `json:"start_time_until"`
+	Price          models.Money `json:"price" binding:"required"`
+}
+
+type DeletePriceRuleForm struct {
+	PriceRuleID int `json:"price_rule_id" binding:"required"`
+}
+
+type NewTicketCategoryForm struct {
+	Name         string `json:"name" binding:"required"`
+	PricePercent int    `json:"price_percent" binding:"required"`
+	MinAge       int    `json:"min_age"`
+	MaxAge       int    `json:"max_age"`
+}
+
+type EditTicketCategoryForm struct {
+	TicketCategoryID int    `json:"ticket_category_id" binding:"required"`
+	Name             string `json:"name" binding:"required"`
+	PricePercent     int    `json:"price_percent" binding:"required"`
+	MinAge           int    `json:"min_age"`
+	MaxAge           int    `json:"max_age"`
+}
+
+type CheckInForm struct {
+	TicketToken string `json:"ticket_token" binding:"required"`
+}
+
+type RefundBookingForm struct {
+	RefundPercent *int   `json:"refund_percent"`
+	Reason        string `json:"reason"`
+}
+
+type NewConcessionItemForm struct {
+	Name        string       `json:"name" binding:"required"`
+	Description string       `json:"description"`
+	Category    string       `json:"category" binding:"required"`
+	Price       models.Money `json:"price" binding:"required"`
+	Stock       *int         `json:"stock"`
+	Active      *bool        `json:"active"`
+	ComboItems  map[int]int  `json:"combo_items"`
+}
+
+type EditConcessionItemForm struct {
+	ConcessionItemID int          `json:"concession_item_id" binding:"required"`
+	Name             string       `json:"name" binding:"required"`
+	Description      string       `json:"description"`
+	Category         string       `json:"category" binding:"required"`
+	Price            models.Money `json:"price" binding:"required"`
+	Stock            *int         `json:"stock"`
+	Active           *bool        `json:"active"`
+	ComboItems       map[int]int  `json:"combo_items"`
+}
+
+type DeleteConcessionItemForm struct {
+	ConcessionItemID int `json:"concession_item_id" binding:"required"`
+}
+
+type CollectConcessionsForm struct {
+	TicketToken          string `json:"ticket_token" binding:"required"`
+	BookingConcessionIDs []int  `json:"booking_concession_ids"`
+}
+
+type EditExchangeRateForm struct {
+	Currency string      `json:"currency" binding:"required"`
+	Rate     json.Number `json:"rate" binding:"required"`
+}
+
+type DeleteExchangeRateForm struct {
+	Currency string `json:"currency" binding:"required"`
 }
@@ -11,11 +11,12 @@ import (
 )
 
 type UsersHandler struct {
-	users services.UserServiceInterface
+	users       services.UserServiceInterface
+	phoneRegion string
 }
 
-func NewUsersHandler(service services.UserServiceInterface) *UsersHandler {
-	return &UsersHandler{users: service}
+func NewUsersHandler(service services.UserServiceInterface, phoneRegion string) *UsersHandler {
+	return &UsersHandler{users: service, phoneRegion: phoneRegion}
 }
 
 func (service *UsersHandler) SignUp(c *gin.Context) {
@@ -35,7 +36,13 @@ func (service *UsersHandler) SignUp(c *gin.Context) {
 		return
 	}
 
-	validPhoneNumber, err := helpers.ValidatePhoneNumber(newUser.PhoneNumber)
+	phoneRegion, err := service.phoneRegionFor(newUser.Country)
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	validPhoneNumber, err := helpers.ValidatePhoneNumber(newUser.PhoneNumber, phoneRegion)
 	if err != nil {
 		if errors.Is(err, helpers.ErrInvaliPhoneNumber) {
 			helpers.ClientError(c, http.StatusBadRequest, fmt.Sprintf("%v", err))
@@ -129,7 +136,13 @@ func (service *UsersHandler) UpdateUserProfile(c *gin.Context) {
 		return
 	}
 
-	validPhoneNumber, err := helpers.ValidatePhoneNumber(userInfoUpdate.PhoneNumber)
+	phoneRegion, err := service.phoneRegionFor(userInfoUpdate.Country)
+	if err != nil {
+		helpers.ClientError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	validPhoneNumber, err := helpers.ValidatePhoneNumber(userInfoUpdate.PhoneNumber, phoneRegion)
 	if err != nil {
 		if errors.Is(err, helpers.ErrInvaliPhoneNumber) {
 			helpers.ClientError(c, http.StatusBadRequest, err.Error())
@@ -160,3 +173,13 @@ func (service *UsersHandler) Logout(c *gin.Context) {
 		"message": "You are now logged out. Have a great day!",
 	})
 }
+
+// phoneRegionFor returns the region local phone numbers are read in: the country the user picked, or the
+// configured default region.
+func (service *UsersHandler) phoneRegionFor(country string) (string, error) {
+	if country == "" {
+		return service.phoneRegion, nil
+	}
+
+	return helpers.ParsePhoneRegion(country)
+}
@@ -4,4 +4,5 @@ import "errors"
 
 var ErrInvalidEmailAddress = errors.New("email address syntax is invalid")
 var ErrInvaliPhoneNumber = errors.New("invalid phone number")
+var ErrInvalidPhoneRegion = errors.New("unsupported phone number region, expected a two-letter country code")
 var ErrMismatchedPassword = errors.New("password and confirm password must match")
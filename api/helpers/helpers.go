@@ -73,4 +73,29 @@ func GetParameterFromURL(c *gin.Context, parameter, message string) (int, error)
 
 	// Return the valid parameter
 	return param, nil
-}
\ No newline at end of file
+}
+
+// GetIntFromQuery extracts an optional positive integer from the URL query string.
+//
+// Parameters:
+// - c: The gin context object used to retrieve query parameters.
+// - key: The name of the query parameter to extract.
+// - fallback: The value returned when the query parameter is absent.
+// - message: The error message to return if the parameter is invalid.
+//
+// Returns:
+// - The parsed integer value of the parameter, or the fallback if it is absent.
+// - An error if the parameter is present but is not a positive integer.
+func GetIntFromQuery(c *gin.Context, key string, fallback int, message string) (int, error) {
+	valueStr, ok := c.GetQuery(key)
+	if !ok || valueStr == "" {
+		return fallback, nil
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("%v", message)
+	}
+
+	return value, nil
+}
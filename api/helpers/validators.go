@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"reflect"
 	"regexp"
+	"strings"
 
 	emailverifier "github.com/AfterShip/email-verifier"
 	"github.com/gin-gonic/gin"
@@ -90,15 +91,33 @@ func ValidateEmail(email string) error {
 	return nil
 }
 
+// ParsePhoneRegion checks that a region is one phone numbers can be read in, ignoring case.
+//
+// Parameters:
+// - region: The ISO 3166-1 alpha-2 country code, e.g., "uz".
+//
+// Returns:
+// - The country code in upper case, e.g., "UZ".
+// - ErrInvalidPhoneRegion if the phonenumbers package does not know the region.
+func ParsePhoneRegion(region string) (string, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if !phonenumbers.GetSupportedRegions()[region] {
+		return "", ErrInvalidPhoneRegion
+	}
+
+	return region, nil
+}
+
 // ValidatePhoneNumber validates a phone number's format and checks if it's valid using the phonenumbers package.
 //
 // Parameters:
 // - phoneNum: The phone number to be validated (in any format).
+// - defaultRegion: The country numbers without an international prefix are read in, e.g., "UZ".
 //
 // Returns:
 // - The formatted phone number in E.164 format if the number is valid.
-// - An error if the phone number is invalid or could not be parsed.
-func ValidatePhoneNumber(phoneNum string) (string, error) {
+// - ErrInvaliPhoneNumber if the phone number is invalid or could not be parsed.
+func ValidatePhoneNumber(phoneNum, defaultRegion string) (string, error) {
 	// Match a basic phone number format (starts with + or digits only)
 	re := regexp.MustCompile(`^\+?[0-9]+$`)
 	if !re.MatchString(phoneNum) {
@@ -106,11 +125,11 @@ func ValidatePhoneNumber(phoneNum string) (string, error) {
 		return "", ErrInvaliPhoneNumber
 	}
 
-	// Parse the phone number using the phonenumbers library (assumes Uzbekistan as default country)
-	num, err := phonenumbers.Parse(phoneNum, "UZ")
+	// Parse the phone number using the phonenumbers library, reading local numbers in the default region
+	num, err := phonenumbers.Parse(phoneNum, defaultRegion)
 	if err != nil {
 		// Return error if the phone number cannot be parsed
-		return "", err
+		return "", ErrInvaliPhoneNumber
 	}
 
 	// Check if the phone number is valid
@@ -156,11 +175,27 @@ func ValidatePassword(password, confirmPassword string) error {
 	return nil
 }
 
-func ValidateSeatsID(seatsID []int)error{
-	for _,k := range seatsID{
-		if k <=0{
+// ValidateSeatsID validates the seat IDs selected for a booking.
+//
+// Parameters:
+// - seatsID: The show seat IDs selected by the user.
+//
+// Returns:
+// - An error if no seat is selected, if any ID is not positive or if the same seat is selected twice.
+func ValidateSeatsID(seatsID []int) error {
+	if len(seatsID) == 0 {
+		return fmt.Errorf("no seat selected")
+	}
+
+	seen := make(map[int]bool, len(seatsID))
+	for _, k := range seatsID {
+		if k <= 0 {
 			return fmt.Errorf("invalid seat ID")
 		}
+		if seen[k] {
+			return fmt.Errorf("seat ID %d selected more than once", k)
+		}
+		seen[k] = true
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,120 @@
+package middlewares
+
+import (
+	"bytes"
+	"cinemaGo/backend/api/helpers"
+	"cinemaGo/backend/internal/services"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseRecorder keeps a copy of everything written to the response, so it can be stored
+// once the handler has finished.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyKey makes a route safe to retry. When a request carries an "Idempotency-Key" header,
+// the first response for that user and key is stored, and retries with the same key and body get
+// that response replayed instead of running the handler again. Requests without the header are
+// passed through unchanged. It must run after UserAuthorizationJWT, which sets the userID.
+//
+// Returns:
+// - A gin.HandlerFunc which handles idempotent replays.
+func IdempotencyKey(service services.IdempotencyServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		userID, _ := c.Get("userID")
+		user_id := int(userID.(float64))
+
+		// Read the body to fingerprint it, then put it back for the handler.
+		requestBody, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			helpers.ClientError(c, http.StatusBadRequest, "could not read request body")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(requestBody))
+
+		stored, replay, err := service.BeginRequest(user_id, key, requestBody)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidIdempotencyKey):
+				helpers.ClientError(c, http.StatusBadRequest, err.Error())
+			case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+				helpers.ClientError(c, http.StatusConflict, err.Error())
+			case errors.Is(err, services.ErrIdempotencyKeyReused):
+				helpers.ClientError(c, http.StatusUnprocessableEntity, err.Error())
+			default:
+				helpers.ServerError(c, err)
+			}
+			c.Abort()
+			return
+		}
+
+		// A retry of a finished request gets the original response.
+		if replay {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.StatusCode, "application/json; charset=utf-8", stored.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// Only this request may store the response or release the key, even once a retry has taken it over.
+		claimToken := stored.ClaimToken
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// A handler that panics has no response to remember; release the key before the panic
+		// reaches the recovery middleware, so the client can retry.
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				abandonRequest(service, user_id, key, claimToken)
+				panic(recovered)
+			}
+		}()
+
+		c.Next()
+
+		// Unexpected failures are not remembered, so the client can simply retry.
+		if recorder.Status() >= http.StatusInternalServerError {
+			abandonRequest(service, user_id, key, claimToken)
+			return
+		}
+
+		// A response that cannot be stored cannot be replayed either; the retry runs the handler again,
+		// which the seat locks of a booking keep from booking the same seats twice.
+		if err := service.CompleteRequest(user_id, key, claimToken, recorder.Status(), recorder.body.Bytes()); err != nil {
+			log.Println(err)
+			abandonRequest(service, user_id, key, claimToken)
+		}
+	}
+}
+
+// abandonRequest releases an idempotency key, logging a failure; the key is freed anyway once its lease runs out.
+func abandonRequest(service services.IdempotencyServiceInterface, userID int, key, claimToken string) {
+	if err := service.AbandonRequest(userID, key, claimToken); err != nil {
+		log.Println(err)
+	}
+}
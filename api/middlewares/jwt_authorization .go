@@ -20,72 +20,94 @@ import (
 // - A gin.HandlerFunc which handles JWT verification and authorization.
 func UserAuthorizationJWT() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Retrieve the JWT token from the cookie "u_auth"
-		tokenStr, err := c.Cookie("u_auth")
-		if err != nil {
+		userID, userRole, ok := parseUserToken(c)
+		if !ok {
 			// If the token is missing or invalid, send an Unauthorized response
 			helpers.UnauthorizedResponse(c)
 			return
 		}
 
-		// Parse and validate the JWT token using the HMAC signing method
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			// Ensure the token uses the correct signing method (HMAC)
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				// If the signing method is not HMAC, return an error
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-
-			// Load the secret key for signing the JWT from environment variables
-			secretKey, err := configs.LoadEnvironmentVariable("JWT_SECRET_KEY")
-			if err != nil {
-				// If the secret key can't be loaded, return an error
-				return nil, fmt.Errorf("cannot get secret key while creating and signing JWT: %v", err)
-			}
-
-			// Return the secret key to verify the JWT signature
-			return []byte(secretKey), nil
-		})
-
-		// If token parsing or validation fails, send an Unauthorized response
-		if err != nil || !token.Valid {
-			helpers.UnauthorizedResponse(c)
-			return
-		}
+		// Set the "userID" and "userRole" in the context for further use by downstream handlers
+		c.Set("userID", userID)     // Store userID in context.
+		c.Set("userRole", userRole) // Store userRole in context.
 
-		// Extract the claims from the JWT token and check if they are valid
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			// If claims extraction fails, return an Unauthorized response
-			helpers.UnauthorizedResponse(c)
-			return
+		// Proceed to the next middleware or handler in the chain
+		c.Next()
+	}
+}
+
+// OptionalUserAuthorizationJWT is a Gin middleware function for public routes that behave differently
+// for a logged-in user. A valid JWT token in the "u_auth" cookie sets "userID" and "userRole" in the
+// context like UserAuthorizationJWT does; a missing or invalid one lets the request proceed anonymously.
+//
+// Returns:
+// - A gin.HandlerFunc which handles optional JWT verification.
+func OptionalUserAuthorizationJWT() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID, userRole, ok := parseUserToken(c); ok {
+			c.Set("userID", userID)
+			c.Set("userRole", userRole)
 		}
 
-		// Check if the token has expired based on the "ttl" claim (time-to-live)
-		if claims["ttl"].(float64) < float64(time.Now().Unix()) {
-			// If the token is expired, return an Unauthorized response
-			helpers.UnauthorizedResponse(c)
-			return
+		c.Next()
+	}
+}
+
+// parseUserToken reads the JWT token from the "u_auth" cookie and returns the user it was issued to.
+// It reports false if the token is missing, tampered with, expired or lacks a user.
+func parseUserToken(c *gin.Context) (float64, string, bool) {
+	// Retrieve the JWT token from the cookie "u_auth"
+	tokenStr, err := c.Cookie("u_auth")
+	if err != nil {
+		return 0, "", false
+	}
+
+	// Parse and validate the JWT token using the HMAC signing method
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		// Ensure the token uses the correct signing method (HMAC)
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			// If the signing method is not HMAC, return an error
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		// Ensure the "userID" claim is valid, otherwise return Unauthorized
-		userID := claims["userID"].(float64)
-		if userID == 0 {
-			// If userID is invalid, return an Unauthorized response
-			helpers.UnauthorizedResponse(c)
-			return
+		// Load the secret key for signing the JWT from environment variables
+		secretKey, err := configs.LoadEnvironmentVariable("JWT_SECRET_KEY")
+		if err != nil {
+			// If the secret key can't be loaded, return an error
+			return nil, fmt.Errorf("cannot get secret key while creating and signing JWT: %v", err)
 		}
 
-		// Extract the "userRole" claim from the JWT token
-		userRole := claims["userRole"].(string)
+		// Return the secret key to verify the JWT signature
+		return []byte(secretKey), nil
+	})
 
-		// Set the "userID" and "userRole" in the context for further use by downstream handlers
-		c.Set("userID", userID)     // Store userID in context.
-		c.Set("userRole", userRole) // Store userRole in context.
+	// If token parsing or validation fails, the token is rejected
+	if err != nil || !token.Valid {
+		return 0, "", false
+	}
 
-		// Proceed to the next middleware or handler in the chain
-		c.Next()
+	// Extract the claims from the JWT token and check if they are valid
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, "", false
+	}
+
+	// Check if the token has expired based on the "ttl" claim (time-to-live)
+	ttl, ok := claims["ttl"].(float64)
+	if !ok || ttl < float64(time.Now().Unix()) {
+		return 0, "", false
+	}
+
+	// Ensure the "userID" claim is valid
+	userID, ok := claims["userID"].(float64)
+	if !ok || userID == 0 {
+		return 0, "", false
 	}
+
+	// Extract the "userRole" claim from the JWT token
+	userRole, _ := claims["userRole"].(string)
+
+	return userID, userRole, true
 }
 
 // AdminRoleRequired checks if the user is an admin. If not, it responds with a 403 status code.
@@ -103,3 +125,19 @@ func AdminRoleRequired() gin.HandlerFunc {
 		c.Next() // Continue to the next handler if the user is an admin
 	}
 }
+
+// HelpdeskRoleRequired checks if the user is a helpdesk member. If not, it responds with a 403 status code.
+func HelpdeskRoleRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("userRole")
+
+		if !exists || role != "helpdesk" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"message": "Access forbidden: Helpdesk only",
+			})
+			c.Abort() // Stop further processing
+			return
+		}
+		c.Next() // Continue to the next handler if the user is a helpdesk member
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"cinemaGo/backend/api/handlers"
 	"cinemaGo/backend/api/middlewares"
+	"cinemaGo/backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,6 +13,9 @@ type ServeHandlersWrapper struct {
 	*handlers.UsersHandler
 	*handlers.BookingHandler
 	*handlers.AdminHandler
+
+	// Idempotency remembers the responses of retry-safe routes.
+	Idempotency services.IdempotencyServiceInterface
 }
 
 func Router(h *ServeHandlersWrapper) *gin.Engine {
@@ -31,11 +35,37 @@ func Router(h *ServeHandlersWrapper) *gin.Engine {
 		v1.GET("/my-profile", middlewares.UserAuthorizationJWT(), h.UserProfile)
 		v1.PUT("/my-profile/edit", middlewares.UserAuthorizationJWT(), h.UpdateUserProfile)
 		v1.POST("/my-profile/logout", middlewares.UserAuthorizationJWT(), h.Logout)
+		v1.GET("/my-profile/bookings", middlewares.UserAuthorizationJWT(), h.MyBookings)
+		v1.GET("/my-profile/bookings/:bookingID", middlewares.UserAuthorizationJWT(), h.MyBooking)
+		v1.GET("/my-profile/bookings/:bookingID/ticket", middlewares.UserAuthorizationJWT(), h.BookingTicket)
+		v1.GET("/my-profile/bookings/:bookingID/ticket/qr", middlewares.UserAuthorizationJWT(), h.BookingTicketQRCode)
+		v1.GET("/my-profile/bookings/:bookingID/receipt", middlewares.UserAuthorizationJWT(), h.BookingReceipt)
+		v1.GET("/my-profile/bookings/:bookingID/receipt/pdf", middlewares.UserAuthorizationJWT(), h.BookingReceiptPDF)
+
+		v1.POST("/my-bookings/:bookingID/cancel", middlewares.UserAuthorizationJWT(), h.CancelBooking)
 
 		v1.GET("/buytickets/movie/:showID/show-times", h.MovieShowTimes)
 		v1.GET("/buytickets/movie/:showID/available-seats", h.ShowSeats)
-
-		v1.POST("/buytickets/payment", middlewares.UserAuthorizationJWT(), h.BookSeats)
+		v1.GET("/buytickets/movie/:showID/seats/stream", h.ShowSeatsStream)
+		v1.GET("/buytickets/movie/:showID/seat-map.svg", middlewares.OptionalUserAuthorizationJWT(), h.SeatMapSVG)
+		v1.GET("/buytickets/movie/:showID/best-seats", h.BestSeats)
+		v1.POST("/buytickets/movie/:showID/best-seats/hold", middlewares.UserAuthorizationJWT(), h.HoldBestSeats)
+		v1.POST("/buytickets/movie/:showID/waitlist", middlewares.UserAuthorizationJWT(), h.JoinWaitlist)
+		v1.GET("/buytickets/movie/:showID/waitlist", middlewares.UserAuthorizationJWT(), h.WaitlistStatus)
+		v1.GET("/buytickets/movie/:showID/waitlist/stream", middlewares.UserAuthorizationJWT(), h.WaitlistStream)
+		v1.DELETE("/buytickets/movie/:showID/waitlist", middlewares.UserAuthorizationJWT(), h.LeaveWaitlist)
+
+		v1.GET("/buytickets/ticket-categories", h.TicketCategories)
+		v1.GET("/buytickets/concessions", h.Concessions)
+		v1.POST("/buytickets/quote", h.QuoteBooking)
+		v1.POST("/buytickets/hold", middlewares.UserAuthorizationJWT(), h.HoldSeats)
+		v1.DELETE("/buytickets/hold/:holdID", middlewares.UserAuthorizationJWT(), h.ReleaseSeatHold)
+		v1.POST("/buytickets/payment", middlewares.UserAuthorizationJWT(), middlewares.IdempotencyKey(h.Idempotency), h.BookSeats)
+		v1.POST("/payments/webhook", h.PaymentWebhook)
+
+		v1.GET("/helpdesk/booking/:bookingReference", middlewares.UserAuthorizationJWT(), middlewares.HelpdeskRoleRequired(), h.BookingByReference)
+		v1.POST("/helpdesk/check-in", middlewares.UserAuthorizationJWT(), middlewares.HelpdeskRoleRequired(), h.CheckIn)
+		v1.POST("/helpdesk/concessions/collect", middlewares.UserAuthorizationJWT(), middlewares.HelpdeskRoleRequired(), h.CollectConcessions)
 
 		v1.GET("/admin/carousel-image/all", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.CarouselImagesAdmin)
 		v1.POST("/admin/carousel-image/new", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.NewCarouselImageAdmin)
@@ -60,14 +90,49 @@ func Router(h *ServeHandlersWrapper) *gin.Engine {
 
 		v1.POST("/admin/cinema-hall-seat/new", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.NewCinemaHallSeatAdmin)
 		v1.DELETE("/admin/cinema-hall-seat/delete", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.DeleteCinemaHallSeatAdmin)
+		v1.POST("/admin/cinema-hall-seat/generate", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.GenerateHallSeatsAdmin)
+		v1.POST("/admin/cinema-hall-seat/clone", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.CloneHallSeatsAdmin)
+
+		v1.GET("/admin/cinema-hall-layout/:cinemaHallID", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.HallLayoutAdmin)
+		v1.PUT("/admin/cinema-hall-layout/edit", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.EditHallLayoutAdmin)
 
 		v1.GET("/admin/show/all", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.AllShowsAdmin)
 		v1.POST("/admin/show/new", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.NewShowAdmin)
 		v1.PUT("/admin/show/edit", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.EditShowAdmin)
 		v1.DELETE("/admin/show/delete", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.DeleteShowAdmin)
+		v1.PUT("/admin/show/dynamic-pricing/edit", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.EditShowDynamicPricingAdmin)
 
 		v1.GET("/admin/show-seats/:showID", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.AllShowSeatsAdmin)
 		v1.PUT("/admin/show-seat-price/edit", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.EditShowSeatPriceAdmin)
+		v1.PUT("/admin/show-seat-price/bulk-edit", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.BulkEditShowSeatPriceAdmin)
+		v1.PUT("/admin/show-seat-price/copy", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.CopyShowSeatPriceAdmin)
+		v1.GET("/admin/show-seat-price/history/:showSeatID", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.ShowSeatPriceHistoryAdmin)
+
+		v1.POST("/admin/booking/:bookingID/refund", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.RefundBookingAdmin)
+
+		v1.GET("/admin/promo-code/all", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.AllPromoCodesAdmin)
+		v1.POST("/admin/promo-code/new", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.NewPromoCodeAdmin)
+		v1.GET("/admin/promo-code/:promoCodeID", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.PromoCodeAdmin)
+		v1.PUT("/admin/promo-code/edit", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.EditPromoCodeAdmin)
+		v1.DELETE("/admin/promo-code/delete", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.DeletePromoCodeAdmin)
+
+		v1.GET("/admin/price-rule/all", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.AllPriceRulesAdmin)
+		v1.POST("/admin/price-rule/new", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.NewPriceRuleAdmin)
+		v1.PUT("/admin/price-rule/edit", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.EditPriceRuleAdmin)
+		v1.DELETE("/admin/price-rule/delete", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.DeletePriceRuleAdmin)
+
+		v1.GET("/admin/ticket-category/all", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.AllTicketCategoriesAdmin)
+		v1.POST("/admin/ticket-category/new", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.NewTicketCategoryAdmin)
+		v1.PUT("/admin/ticket-category/edit", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.EditTicketCategoryAdmin)
+
+		v1.GET("/admin/concession/all", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.AllConcessionItemsAdmin)
+		v1.POST("/admin/concession/new", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.NewConcessionItemAdmin)
+		v1.PUT("/admin/concession/edit", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.EditConcessionItemAdmin)
+		v1.DELETE("/admin/concession/delete", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.DeleteConcessionItemAdmin)
+
+		v1.GET("/admin/exchange-rate/all", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.AllExchangeRatesAdmin)
+		v1.PUT("/admin/exchange-rate/edit", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.EditExchangeRateAdmin)
+		v1.DELETE("/admin/exchange-rate/delete", middlewares.UserAuthorizationJWT(), middlewares.AdminRoleRequired(), h.DeleteExchangeRateAdmin)
 
 	}
 
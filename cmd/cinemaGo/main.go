@@ -2,12 +2,15 @@ package main
 
 import (
 	"cinemaGo/backend/api/handlers"
+	"cinemaGo/backend/api/helpers"
 	"cinemaGo/backend/api/routes"
 	"cinemaGo/backend/internal/models"
 	"cinemaGo/backend/internal/services"
 	"cinemaGo/backend/pkg/configs"
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -73,12 +76,132 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	usersHandler := handlers.NewUsersHandler(usersService)
 
-	bookingService := services.NewBookingService(db)
+	// Load the country whose format is assumed for phone numbers without an international prefix (Uzbekistan by default).
+	phoneRegion, err := helpers.ParsePhoneRegion(configs.LoadEnvironmentVariableOrDefault("DEFAULT_PHONE_REGION", "UZ"))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	usersHandler := handlers.NewUsersHandler(usersService, phoneRegion)
+
+	// Load how many minutes a seat hold lasts while the customer pays (10 minutes by default).
+	seatHoldMinutes, err := configs.LoadIntEnvironmentVariable("SEAT_HOLD_MINUTES", 10)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Load how many minutes seats offered to a waitlisted customer are held for them (5 minutes by default).
+	waitlistHoldMinutes, err := configs.LoadIntEnvironmentVariable("WAITLIST_HOLD_MINUTES", 5)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Load the booking fee charged on top of every seat, in minor units of the currency (no fee by default).
+	bookingFeePerSeat, err := configs.LoadIntEnvironmentVariable("BOOKING_FEE_PER_SEAT", 0)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Load the VAT rate included in every price, in percent (no VAT by default).
+	vatRate, err := services.ParseVATRate(configs.LoadEnvironmentVariableOrDefault("VAT_RATE", "0"))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Load whether selections that leave a single empty seat on its own are refused (allowed by default).
+	blockSingleSeatGaps, err := configs.LoadBoolEnvironmentVariable("BLOCK_SINGLE_SEAT_GAPS", false)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Load the secret the payment provider signs its webhooks with.
+	// If the variable is missing or there's an error, the program will terminate.
+	paymentWebhookSecret, err := configs.LoadEnvironmentVariable("PAYMENT_WEBHOOK_SECRET")
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Load the payment provider bookings are charged through. It must be set explicitly, so the fake
+	// provider used for local development and tests ("fake") is never deployed by accident.
+	paymentProviderName, err := configs.LoadEnvironmentVariable("PAYMENT_PROVIDER")
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	paymentProvider, err := services.NewPaymentProvider(paymentProviderName, paymentWebhookSecret)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if paymentProvider.Name() == "Fake" {
+		log.Println("warning: payments go through the fake provider; no customer is charged")
+	}
+
+	// Load the refund rules for cancelled bookings (full refund up to 24h before the show, 50% after by default).
+	cancellationPolicy, err := services.ParseCancellationPolicy(configs.LoadEnvironmentVariableOrDefault("CANCELLATION_POLICY", "24:100,0:50"))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Load the key e-tickets are signed with, so the helpdesk can trust the tickets it scans.
+	// If the variable is missing or there's an error, the program will terminate.
+	ticketSigningKey, err := configs.LoadEnvironmentVariable("TICKET_SIGNING_KEY")
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Seat changes and waitlist offers are fanned out to every instance through Redis pub/sub.
+	seatEvents, err := services.NewRedisSeatEvents()
+	if err != nil {
+		log.Fatal(err)
+	}
+	go seatEvents.Run(context.Background())
+
+	bookingService := services.NewBookingService(db, paymentProvider, services.NewTicketSigner(ticketSigningKey), seatEvents, seatEvents, services.BookingSettings{
+		HoldMinutes:         seatHoldMinutes,
+		WaitlistHoldMinutes: waitlistHoldMinutes,
+		BookingFeePerSeat:   models.NewMoney(int64(bookingFeePerSeat), models.DefaultCurrency),
+		VATRate:             vatRate,
+		BlockSingleSeatGaps: blockSingleSeatGaps,
+		CancellationPolicy:  cancellationPolicy,
+	})
 	bookingHandler := handlers.NewBookingHandler(bookingService)
 
-	adminService := services.NewAdminService(db)
+	// Return the seats of abandoned holds to "Available" in the background.
+	go bookingService.RunSeatHoldExpirer(context.Background(), time.Minute)
+
+	// Pay out the refunds of cancelled bookings the payment provider has not paid out yet.
+	go bookingService.RunRefundSettler(context.Background(), time.Minute)
+
+	// Load the demand rules for shows with dynamic pricing (+10% from 50% booked, +25% from 80% booked,
+	// and +10% in the last 6 hours before the show by default).
+	dynamicPricingPolicy, err := services.ParseDynamicPricingPolicy(
+		configs.LoadEnvironmentVariableOrDefault("DYNAMIC_PRICING_OCCUPANCY", "50:10,80:25"),
+		configs.LoadEnvironmentVariableOrDefault("DYNAMIC_PRICING_SHOWTIME", "6:10"),
+	)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Move the prices of the remaining seats of those shows with demand in the background.
+	go services.NewDynamicPricer(db, seatEvents, dynamicPricingPolicy).Run(context.Background(), time.Minute)
+
+	// Load how many hours the response to an Idempotency-Key is remembered (24 hours by default).
+	idempotencyKeyTTLHours, err := configs.LoadIntEnvironmentVariable("IDEMPOTENCY_KEY_TTL_HOURS", 24)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Load how many seconds a request may hold its Idempotency-Key before a retry may run again (2 minutes by default).
+	idempotencyKeyLeaseSeconds, err := configs.LoadIntEnvironmentVariable("IDEMPOTENCY_KEY_LEASE_SECONDS", 120)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	idempotencyService := services.NewIdempotencyService(db, idempotencyKeyTTLHours, idempotencyKeyLeaseSeconds)
+
+	// Delete the Idempotency-Keys that are no longer remembered in the background.
+	go idempotencyService.RunIdempotencyKeySweeper(context.Background(), time.Hour)
+
+	adminService := services.NewAdminService(db, seatEvents)
 	adminHandler := handlers.NewAdminHandler(adminService)
 
 	serveHandlersWrapper := routes.ServeHandlersWrapper{
@@ -86,6 +209,7 @@ func main() {
 		UsersHandler:   usersHandler,
 		BookingHandler: bookingHandler,
 		AdminHandler:   adminHandler,
+		Idempotency:    idempotencyService,
 	}
 
 	router := routes.Router(&serveHandlersWrapper)
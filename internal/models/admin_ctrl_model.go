@@ -28,7 +28,7 @@ type DBContractAdminCtrl interface {
 	UpdateActorCrewInformation(fullName, imageURL, occupation, roleDescription, bornDate, birthplace, about string, isActor bool, actorCrewID int) error
 	DeleteActorCrewByID(actorCrewID int) error
 
-	InsertNewCinemaHall(hallName, hallType string, capacity int) error
+	InsertNewCinemaHall(hallName, hallType, currency string, capacity int) error
 	RetrieveAllCinemaHallsForAdmin() ([]CinemaHallForAdmin, error)
 	RetrieveCinemaHallInfoByID(cinemaHallID int) (CinemaHallForAdmin, error)
 
@@ -38,15 +38,49 @@ type DBContractAdminCtrl interface {
 	InsertCinemaSeats(seatRow string, seatNumber int, seatType string, hallID int) error
 	RetrieveALLCinemaSeatsByHallID(hallID int) ([]CinemaSeatForAdmin, error)
 	DeleteCinemaSeatByID(cinemaSeatID int) error
+	ReplaceHallLayout(hallLayout HallLayout) (int, error)
+	RetrieveHallLayout(hallID int) (HallLayout, error)
+	InsertHallSeats(hallLayout HallLayout) error
+	CloneHallSeats(fromHallID, toHallID int) (int, error)
 
 	InsertNewShow(showDate string, startTime string, hallID int, movieID int) (int, error)
 	RetrieveAllShowsForAdmin() ([]ShowForAdmin, error)
 	UpdateShowByID(showID int, showDate string, startTime string, hallID int, movieID int) error
 	DeleteShowByID(showID int) error
 
-	InsertNewShowSeat(seatStatus string, seatPrice int, cinemSeatID int, showID int) error
+	InsertNewShowSeat(seatStatus string, seatPrice Money, priceReason string, cinemSeatID int, showID int) error
 	RetrieveAllShowSeats(showID int) ([]ShowSeatForAdmin, error)
-	UpdateShowSeatByID(seatPrice int, showSeatID int) error
+	RetrieveShowCurrency(showID int) (string, error)
+	RetrieveShowSeatCurrency(showSeatID int) (string, error)
+	UpdateShowSeatByID(seatPrice Money, showSeatID int) (int, error)
+	UpdateShowSeatPrices(showID int, seatPrice Money, seatType, rowFrom, rowTo string) (int, error)
+	CopyShowSeatPrices(fromShowID, toShowID int) (int, error)
+	UpsertShowDynamicPricing(showID int, enabled bool, minPricePercent, maxPricePercent int) error
+	ApplyDynamicSeatPrices(showID, adjustmentPercent, minPricePercent, maxPricePercent int, reason string) (int, error)
+	RetrieveShowSeatPriceHistory(showSeatID int) ([]SeatPriceChange, error)
+
+	InsertNewPromoCode(promoCode PromoCode) error
+	RetrieveAllPromoCodes() ([]PromoCode, error)
+	RetrievePromoCodeByID(promoCodeID int) (PromoCode, error)
+	UpdatePromoCodeByID(promoCode PromoCode) error
+	DeletePromoCodeByID(promoCodeID int) error
+
+	InsertNewPriceRule(priceRule PriceRule) error
+	RetrieveAllPriceRules() ([]PriceRule, error)
+	UpdatePriceRuleByID(priceRule PriceRule) error
+	DeletePriceRuleByID(priceRuleID int) error
+
+	InsertNewTicketCategory(ticketCategory TicketCategory) error
+	RetrieveAllTicketCategories() ([]TicketCategory, error)
+	UpdateTicketCategoryByID(ticketCategory TicketCategory) error
+	InsertNewConcessionItem(concessionItem ConcessionItem) error
+	RetrieveConcessionItems(activeOnly bool) ([]ConcessionItem, error)
+	UpdateConcessionItemByID(concessionItem ConcessionItem) error
+	DeleteConcessionItemByID(concessionItemID int) error
+
+	RetrieveExchangeRates() ([]ExchangeRate, error)
+	UpsertExchangeRate(currency, rate string) error
+	DeleteExchangeRate(currency string) error
 }
 
 type AdminOperations struct {
@@ -375,7 +409,8 @@ func (psql *Postgres) UpdateMovieInfoForAdminByMovieID(movieID int, title, descr
 // Returns:
 //   - error: Returns nil if the deletion is successful. If an error occurs during the query execution, or while checking rows affected,
 //     an error is returned with context. If no rows are affected (i.e., no movie with the specified ID exists),
-//     it returns `ErrAdminPageMovieNotFound`.
+//     it returns `ErrAdminPageMovieNotFound`. If a show of the movie has a paid booking, whose receipt must be
+//     kept, it returns `ErrMovieHasPaidBookings`.
 func (psql *Postgres) DeleteMovieByMovieID(movieID int) error {
 	// SQL query to delete the movie by its ID
 	stmt := `DELETE FROM movies WHERE id = $1;`
@@ -383,6 +418,10 @@ func (psql *Postgres) DeleteMovieByMovieID(movieID int) error {
 	// Execute the query to delete the movie with the provided ID
 	result, err := psql.DB.Exec(stmt, movieID)
 	if err != nil {
+		// Receipts keep their booking, so the cascade down to paid bookings is refused (23503)
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return ErrMovieHasPaidBookings
+		}
 		// Return a wrapped error if query execution fails
 		return fmt.Errorf("failed to delete movie: %w", err)
 	}
@@ -634,17 +673,18 @@ func (psql *Postgres) DeleteActorCrewByID(actorCrewID int) error {
 // Parameters:
 //   - hallName (string): The name of the cinema hall.
 //   - hallType (string): The type of the cinema hall (e.g., IMAX, regular, etc.).
+//   - currency (string): The ISO 4217 code of the currency the hall's seats are priced and paid in.
 //   - capacity (int): The seating capacity of the cinema hall.
 //
 // Returns:
 //   - error: Returns nil if the insertion is successful. If an error occurs during the query execution,
 //     it returns a wrapped error with context.
-func (psql *Postgres) InsertNewCinemaHall(hallName, hallType string, capacity int) error {
+func (psql *Postgres) InsertNewCinemaHall(hallName, hallType, currency string, capacity int) error {
 	// SQL query to insert a new cinema hall into the database
-	stmt := `INSERT INTO cinema_hall (hall_name, hall_type, capacity) VALUES ($1, $2, $3)`
+	stmt := `INSERT INTO cinema_hall (hall_name, hall_type, capacity, currency) VALUES ($1, $2, $3, $4)`
 
 	// Execute the query to insert the new cinema hall
-	_, err := psql.DB.Exec(stmt, hallName, hallType, capacity)
+	_, err := psql.DB.Exec(stmt, hallName, hallType, capacity, currency)
 	if err != nil {
 		if strings.Contains(err.Error(), "unique constraint") {
 			// Handle duplicate entry error gracefully
@@ -666,7 +706,7 @@ func (psql *Postgres) InsertNewCinemaHall(hallName, hallType string, capacity in
 //   - error: Returns an error if there's a problem retrieving the cinema hall data, scanning the rows, or no halls are found.
 func (psql *Postgres) RetrieveAllCinemaHallsForAdmin() ([]CinemaHallForAdmin, error) {
 	// SQL query to retrieve all cinema hall records from the database
-	stmt := `SELECT * FROM cinema_hall`
+	stmt := `SELECT cinema_hall_id, hall_name, hall_type, capacity, currency FROM cinema_hall`
 
 	// Execute the query to fetch rows
 	rows, err := psql.DB.Query(stmt)
@@ -686,7 +726,7 @@ func (psql *Postgres) RetrieveAllCinemaHallsForAdmin() ([]CinemaHallForAdmin, er
 		var cinemaHall CinemaHallForAdmin
 
 		// Scan the columns of the current row into the cinemaHall struct
-		err := rows.Scan(&cinemaHall.CinemaHallID, &cinemaHall.HallName, &cinemaHall.HallType, &cinemaHall.Capacity)
+		err := rows.Scan(&cinemaHall.CinemaHallID, &cinemaHall.HallName, &cinemaHall.HallType, &cinemaHall.Capacity, &cinemaHall.Currency)
 		if err != nil {
 			// Check if no rows were found and return a custom error if so
 			if errors.Is(err, sql.ErrNoRows) {
@@ -714,13 +754,13 @@ func (psql *Postgres) RetrieveAllCinemaHallsForAdmin() ([]CinemaHallForAdmin, er
 //   - error: Returns an error if there's an issue retrieving or scanning the data, or if no cinema hall is found.
 func (psql *Postgres) RetrieveCinemaHallInfoByID(cinemaHallID int) (CinemaHallForAdmin, error) {
 	// SQL query to retrieve cinema hall information by ID from the database
-	stmt := `SELECT * FROM cinema_hall WHERE cinema_hall_id = $1`
+	stmt := `SELECT cinema_hall_id, hall_name, hall_type, capacity, currency FROM cinema_hall WHERE cinema_hall_id = $1`
 
 	// Variable to hold the cinema hall data
 	var cinemaHall CinemaHallForAdmin
 
 	// Execute the query and scan the result into the cinemaHall struct
-	err := psql.DB.QueryRow(stmt, cinemaHallID).Scan(&cinemaHall.CinemaHallID, &cinemaHall.HallName, &cinemaHall.HallType, &cinemaHall.Capacity)
+	err := psql.DB.QueryRow(stmt, cinemaHallID).Scan(&cinemaHall.CinemaHallID, &cinemaHall.HallName, &cinemaHall.HallType, &cinemaHall.Capacity, &cinemaHall.Currency)
 	if err != nil {
 		// Check if no rows were found and return a custom error if so
 		if errors.Is(err, sql.ErrNoRows) {
@@ -1058,7 +1098,8 @@ func (psql *Postgres) UpdateShowByID(showID int, showDate string, startTime stri
 //   - showID (int): The ID of the show to be deleted.
 //
 // Returns:
-//   - error: If an issue occurs while deleting the show, an error is returned.
+//   - error: ErrShowNotFound if there is no such show, ErrShowHasPaidBookings if it has a paid booking,
+//     whose receipt must be kept, or a wrapped error if the delete fails.
 func (psql *Postgres) DeleteShowByID(showID int) error {
 	// SQL query to delete a show based on show_id
 	stmt := `DELETE FROM show WHERE show_id = $1`
@@ -1066,6 +1107,10 @@ func (psql *Postgres) DeleteShowByID(showID int) error {
 	// Execute the DELETE query with the provided showID
 	result, err := psql.DB.Exec(stmt, showID)
 	if err != nil {
+		// Receipts keep their booking, so the cascade down to paid bookings is refused (23503)
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return ErrShowHasPaidBookings
+		}
 		return fmt.Errorf("failed to delete show: %w", err)
 	}
 
@@ -1085,20 +1130,22 @@ func (psql *Postgres) DeleteShowByID(showID int) error {
 }
 
 // InsertNewShowSeat inserts a new show seat record into the database.
+// A priced seat also gets its first entry in the seat's price history.
 // Parameters:
 //   - seatStatus (string): The status of the seat (e.g., "available", "reserved", etc.)
-//   - seatPrice (int): The price of the seat for the show
+//   - seatPrice (Money): The price of the seat for the show
+//   - priceReason (string): Why the seat has this price (e.g., "Price rule: Matinee")
 //   - cinemaSeatID (int): The ID of the corresponding cinema seat
 //   - showID (int): The ID of the show the seat is associated with
 //
 // Returns:
 //   - error: If an issue occurs during the insertion, an error is returned.
-func (psql *Postgres) InsertNewShowSeat(seatStatus string, seatPrice int, cinemaSeatID int, showID int) error {
-	// SQL query to insert a new show seat into the show_seat table
-	stmt := `INSERT INTO show_seat (cinema_seat_id, status, price, show_id) VALUES ($1, $2, $3, $4)`
+func (psql *Postgres) InsertNewShowSeat(seatStatus string, seatPrice Money, priceReason string, cinemaSeatID int, showID int) error {
+	// SQL query to insert a new show seat into the show_seat table and record its first price
+	stmt := `WITH inserted AS (INSERT INTO show_seat (cinema_seat_id, status, price, base_price, show_id) VALUES ($1, $2, $3, $3, $4) RETURNING show_seat_id, price) INSERT INTO seat_price_history (show_seat_id, old_price, new_price, reason) SELECT show_seat_id, 0, price, $5 FROM inserted WHERE price > 0`
 
 	// Execute the query
-	_, err := psql.DB.Exec(stmt, cinemaSeatID, seatStatus, seatPrice, showID)
+	_, err := psql.DB.Exec(stmt, cinemaSeatID, seatStatus, seatPrice, showID, priceReason)
 	if err != nil {
 		// If an error occurs during execution, return a wrapped error
 		return fmt.Errorf("failed to insert new show seat: %w", err)
@@ -1117,7 +1164,7 @@ func (psql *Postgres) InsertNewShowSeat(seatStatus string, seatPrice int, cinema
 //   - error: Returns an error if something goes wrong while fetching or processing the data
 func (psql *Postgres) RetrieveAllShowSeats(showID int) ([]ShowSeatForAdmin, error) {
 	// SQL query to fetch all show seats for a given show
-	stmt := `SELECT show_seat_id, cinema_seat_id, status, price, show_id FROM show_seat WHERE show_id = $1`
+	stmt := `SELECT ss.show_seat_id, ss.cinema_seat_id, ss.status, ss.price, ch.currency, ss.show_id FROM show_seat ss JOIN show s ON ss.show_id = s.show_id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id WHERE ss.show_id = $1`
 
 	// Execute the query and fetch rows
 	rows, err := psql.DB.Query(stmt, showID)
@@ -1132,9 +1179,10 @@ func (psql *Postgres) RetrieveAllShowSeats(showID int) ([]ShowSeatForAdmin, erro
 	// Loop through all rows in the result set
 	for rows.Next() {
 		var showSeat ShowSeatForAdmin
+		var currency string
 
 		// Scan each row into the showSeat struct
-		err := rows.Scan(&showSeat.ShowSeatID, &showSeat.CinemaSeatID, &showSeat.SeatStatus, &showSeat.SeatPrice, &showSeat.ShowID)
+		err := rows.Scan(&showSeat.ShowSeatID, &showSeat.CinemaSeatID, &showSeat.SeatStatus, &showSeat.SeatPrice, &currency, &showSeat.ShowID)
 		if err != nil {
 			// If scanning fails, return an error
 			if errors.Is(err, sql.ErrNoRows) {
@@ -1143,6 +1191,9 @@ func (psql *Postgres) RetrieveAllShowSeats(showID int) ([]ShowSeatForAdmin, erro
 			return nil, fmt.Errorf("failed to scan show seat: %w", err)
 		}
 
+		// The price is in the currency of the hall
+		showSeat.SeatPrice.Currency = currency
+
 		// Append the scanned show seat to the result slice
 		allShowSeats = append(allShowSeats, showSeat)
 	}
@@ -1151,36 +1202,775 @@ func (psql *Postgres) RetrieveAllShowSeats(showID int) ([]ShowSeatForAdmin, erro
 	return allShowSeats, nil
 }
 
+// RetrieveShowCurrency retrieves the currency the seats of a show are priced in, which is the currency of its hall.
+//
+// Parameters:
+//   - showID (int): The ID of the show.
+//
+// Returns:
+//   - string: The ISO 4217 code of the currency.
+//   - error: ErrShowNotFound if the show does not exist, or a wrapped error if the query fails.
+func (psql *Postgres) RetrieveShowCurrency(showID int) (string, error) {
+	stmt := `SELECT ch.currency FROM show s JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id WHERE s.show_id = $1`
+
+	var currency string
+	if err := psql.DB.QueryRow(stmt, showID).Scan(&currency); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrShowNotFound
+		}
+		return "", fmt.Errorf("failed to retrieve the currency of the show: %w", err)
+	}
+
+	return currency, nil
+}
+
+// RetrieveShowSeatCurrency retrieves the currency a show seat is priced in, which is the currency of its hall.
+//
+// Parameters:
+//   - showSeatID (int): The ID of the show seat.
+//
+// Returns:
+//   - string: The ISO 4217 code of the currency.
+//   - error: ErrShowSeatNotFound if the show seat does not exist, or a wrapped error if the query fails.
+func (psql *Postgres) RetrieveShowSeatCurrency(showSeatID int) (string, error) {
+	stmt := `SELECT ch.currency FROM show_seat ss JOIN show s ON ss.show_id = s.show_id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id WHERE ss.show_seat_id = $1`
+
+	var currency string
+	if err := psql.DB.QueryRow(stmt, showSeatID).Scan(&currency); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrShowSeatNotFound
+		}
+		return "", fmt.Errorf("failed to retrieve the currency of the show seat: %w", err)
+	}
+
+	return currency, nil
+}
+
 // UpdateShowSeatByID updates the price of a specific show seat by its ID.
+// The new price becomes the seat's base price and the change is recorded in its price history.
 // Parameters:
-//   - seatPrice (int): The new price for the seat
+//   - seatPrice (Money): The new price for the seat
 //   - showSeatID (int): The ID of the show seat to update
 //
 // Returns:
+//   - int: The ID of the show the seat belongs to.
 //   - error: If any error occurs during the update or if no rows are affected.
-func (psql *Postgres) UpdateShowSeatByID(seatPrice int, showSeatID int) error {
-	// SQL query to update the price of a show seat by its ID
-	stmt := `UPDATE show_seat SET price = $1 WHERE show_seat_id = $2`
+func (psql *Postgres) UpdateShowSeatByID(seatPrice Money, showSeatID int) (int, error) {
+	// SQL query to update the price of a show seat by its ID and record the change
+	stmt := `WITH old AS (SELECT show_seat_id, COALESCE(price, 0) AS price FROM show_seat WHERE show_seat_id = $2 FOR UPDATE), updated AS (UPDATE show_seat ss SET price = $1, base_price = $1 FROM old WHERE ss.show_seat_id = old.show_seat_id RETURNING ss.show_seat_id, ss.show_id, old.price AS old_price, ss.price AS new_price), history AS (INSERT INTO seat_price_history (show_seat_id, old_price, new_price, reason) SELECT show_seat_id, old_price, new_price, 'Set by admin' FROM updated) SELECT show_id FROM updated`
 
 	// Execute the query
-	result, err := psql.DB.Exec(stmt, seatPrice, showSeatID)
+	var showID int
+	err := psql.DB.QueryRow(stmt, seatPrice, showSeatID).Scan(&showID)
 	if err != nil {
+		// If no rows were affected, return a custom error indicating the seat wasn't found
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrShowSeatNotFound
+		}
 		// Return error if query execution fails
-		return fmt.Errorf("error occurred while updating seat price: %w", err)
+		return 0, fmt.Errorf("error occurred while updating seat price: %w", err)
+	}
+
+	// Return the show of the updated seat
+	return showID, nil
+}
+
+// UpdateShowSeatPrices sets the price of many seats of a show in a single statement, so either every
+// matching seat is repriced or none is. The new price becomes the seats' base price and every change
+// is recorded in the seats' price history.
+//
+// Row labels are compared the way rows are painted in a hall: "A" to "Z", then "AA", "AB" and so on.
+//
+// Parameters:
+//   - showID (int): The ID of the show to reprice.
+//   - seatPrice (Money): The new price.
+//   - seatType (string): Only reprice seats of this type, or empty for every type.
+//   - rowFrom (string): Only reprice rows from this one on, or empty for no lower bound.
+//   - rowTo (string): Only reprice rows up to this one, or empty for no upper bound.
+//
+// Returns:
+//   - int: The number of seats repriced.
+//   - error: ErrShowSeatNotFound if no seat matches, or a wrapped error if the update fails.
+func (psql *Postgres) UpdateShowSeatPrices(showID int, seatPrice Money, seatType, rowFrom, rowTo string) (int, error) {
+	stmt := `WITH old AS (SELECT ss.show_seat_id, COALESCE(ss.price, 0) AS price FROM show_seat ss JOIN cinema_seat cs ON ss.cinema_seat_id = cs.cinema_seat_id WHERE ss.show_id = $2 AND ($3 = '' OR LOWER(cs.seat_type) = LOWER($3)) AND ($4 = '' OR (LENGTH(cs.seat_row), cs.seat_row) >= (LENGTH($4), $4)) AND ($5 = '' OR (LENGTH(cs.seat_row), cs.seat_row) <= (LENGTH($5), $5)) FOR UPDATE OF ss), updated AS (UPDATE show_seat ss SET price = $1, base_price = $1 FROM old WHERE ss.show_seat_id = old.show_seat_id RETURNING ss.show_seat_id, old.price AS old_price, ss.price AS new_price), history AS (INSERT INTO seat_price_history (show_seat_id, old_price, new_price, reason) SELECT show_seat_id, old_price, new_price, 'Set by admin (bulk edit)' FROM updated) SELECT COUNT(*) FROM updated`
+
+	var updated int
+	if err := psql.DB.QueryRow(stmt, seatPrice, showID, seatType, rowFrom, rowTo).Scan(&updated); err != nil {
+		return 0, fmt.Errorf("error occurred while updating seat prices: %w", err)
+	}
+
+	if updated == 0 {
+		return 0, ErrShowSeatNotFound
+	}
+
+	return updated, nil
+}
+
+// CopyShowSeatPrices copies the seat base prices of one show onto another in a single statement.
+// Seats are matched by row and seat number, so the shows may play in different halls of the same currency; seats of
+// the source show that have no price yet are skipped. Every change is recorded in the seats' price history.
+//
+// Parameters:
+//   - fromShowID (int): The ID of the show whose prices are copied.
+//   - toShowID (int): The ID of the show that is repriced.
+//
+// Returns:
+//   - int: The number of seats repriced.
+//   - error: ErrShowSeatNotFound if no seat of the two shows matches, or a wrapped error if the update fails.
+func (psql *Postgres) CopyShowSeatPrices(fromShowID, toShowID int) (int, error) {
+	stmt := `WITH old AS (SELECT dst.show_seat_id, COALESCE(dst.price, 0) AS old_price, src.base_price AS new_price FROM show_seat dst JOIN cinema_seat dcs ON dst.cinema_seat_id = dcs.cinema_seat_id JOIN cinema_seat scs ON scs.seat_row = dcs.seat_row AND scs.seat_number = dcs.seat_number JOIN show_seat src ON src.cinema_seat_id = scs.cinema_seat_id AND src.show_id = $1 WHERE dst.show_id = $2 AND src.base_price > 0 FOR UPDATE OF dst), updated AS (UPDATE show_seat ss SET price = old.new_price, base_price = old.new_price FROM old WHERE ss.show_seat_id = old.show_seat_id RETURNING ss.show_seat_id, old.old_price, ss.price AS new_price), history AS (INSERT INTO seat_price_history (show_seat_id, old_price, new_price, reason) SELECT show_seat_id, old_price, new_price, $3 FROM updated) SELECT COUNT(*) FROM updated`
+
+	var updated int
+	if err := psql.DB.QueryRow(stmt, fromShowID, toShowID, fmt.Sprintf("Copied from show %d", fromShowID)).Scan(&updated); err != nil {
+		return 0, fmt.Errorf("error occurred while copying seat prices: %w", err)
+	}
+
+	if updated == 0 {
+		return 0, ErrShowSeatNotFound
+	}
+
+	return updated, nil
+}
+
+// UpsertShowDynamicPricing turns demand-based pricing of a show on or off and sets the floor and
+// ceiling its seat prices may move between, as percentages of each seat's base price.
+//
+// Parameters:
+//   - showID (int): The ID of the show.
+//   - enabled (bool): Whether the show's prices follow demand.
+//   - minPricePercent (int): The lowest price allowed, as a percentage of the base price.
+//   - maxPricePercent (int): The highest price allowed, as a percentage of the base price.
+//
+// Returns:
+//   - error: ErrShowNotFound if the show does not exist, or a wrapped error if the query fails.
+func (psql *Postgres) UpsertShowDynamicPricing(showID int, enabled bool, minPricePercent, maxPricePercent int) error {
+	stmt := `INSERT INTO show_dynamic_pricing (show_id, enabled, min_price_percent, max_price_percent) VALUES ($1, $2, $3, $4) ON CONFLICT (show_id) DO UPDATE SET enabled = EXCLUDED.enabled, min_price_percent = EXCLUDED.min_price_percent, max_price_percent = EXCLUDED.max_price_percent, updated_at = CURRENT_TIMESTAMP`
+
+	_, err := psql.DB.Exec(stmt, showID, enabled, minPricePercent, maxPricePercent)
+	if err != nil {
+		// The show does not exist.
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return ErrShowNotFound
+		}
+		return fmt.Errorf("failed to save dynamic pricing of the show: %w", err)
+	}
+
+	return nil
+}
+
+// RetrieveShowSeatPriceHistory retrieves every price change of a show seat, oldest first.
+//
+// Parameters:
+//   - showSeatID (int): The ID of the show seat.
+//
+// Returns:
+//   - []SeatPriceChange: The price changes of the seat.
+//   - error: ErrShowSeatNotFound if the seat has no recorded price, or a wrapped error if the query fails.
+func (psql *Postgres) RetrieveShowSeatPriceHistory(showSeatID int) ([]SeatPriceChange, error) {
+	stmt := `SELECT sph.seat_price_history_id, sph.show_seat_id, sph.old_price, sph.new_price, ch.currency, sph.reason, sph.created_at FROM seat_price_history sph JOIN show_seat ss ON sph.show_seat_id = ss.show_seat_id JOIN show s ON ss.show_id = s.show_id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id WHERE sph.show_seat_id = $1 ORDER BY sph.seat_price_history_id`
+
+	rows, err := psql.DB.Query(stmt, showSeatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve seat price history: %w", err)
+	}
+
+	// Ensure the rows are closed after the function finishes
+	defer rows.Close()
+
+	var priceChanges []SeatPriceChange
+
+	for rows.Next() {
+		var priceChange SeatPriceChange
+		var currency string
+		err := rows.Scan(&priceChange.SeatPriceHistoryID, &priceChange.ShowSeatID, &priceChange.OldPrice, &priceChange.NewPrice, &currency, &priceChange.Reason, &priceChange.ChangedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan seat price history: %w", err)
+		}
+		priceChange.OldPrice.Currency = currency
+		priceChange.NewPrice.Currency = currency
+		priceChanges = append(priceChanges, priceChange)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over seat price history: %w", err)
+	}
+
+	if len(priceChanges) == 0 {
+		return nil, ErrShowSeatNotFound
+	}
+
+	return priceChanges, nil
+}
+
+// promoCodeColumns selects every column of a promo code, turning the optional limits and
+// restrictions into their zero values and counting the bookings that currently use the code.
+// Failed and cancelled bookings give their use back.
+const promoCodeColumns = `SELECT pc.promo_code_id, pc.code, COALESCE(pc.description, ''), pc.discount_type, pc.discount_value, pc.valid_from, pc.valid_until,
+	COALESCE(pc.max_uses, 0), COALESCE(pc.max_uses_per_user, 0), COALESCE(pc.movie_id, 0), COALESCE(pc.hall_type, ''), COALESCE(pc.seat_type, ''),
+	(SELECT COUNT(*) FROM promo_code_redemption r JOIN booking b ON b.booking_id = r.booking_id WHERE r.promo_code_id = pc.promo_code_id AND b.status IN ('Pending', 'Confirmed'))
+	FROM promo_code pc`
+
+// scanPromoCode scans a row selected with promoCodeColumns. The discount value is a percent for
+// "Percentage" codes and an amount in minor units for "Fixed" codes.
+func scanPromoCode(row interface{ Scan(...any) error }) (PromoCode, error) {
+	var promoCode PromoCode
+	var discountValue int64
+	err := row.Scan(&promoCode.PromoCodeID, &promoCode.Code, &promoCode.Description, &promoCode.DiscountType, &discountValue,
+		&promoCode.ValidFrom, &promoCode.ValidUntil, &promoCode.MaxUses, &promoCode.MaxUsesPerUser, &promoCode.MovieID,
+		&promoCode.HallType, &promoCode.SeatType, &promoCode.TimesUsed)
+
+	if promoCode.DiscountType == "Percentage" {
+		promoCode.DiscountPercent = int(discountValue)
+	} else {
+		promoCode.DiscountAmount = NewMoney(discountValue, DefaultCurrency)
+	}
+
+	return promoCode, err
+}
+
+// promoCodeArgs turns the optional limits and restrictions of a promo code into NULLs
+// when they are not set, so they are stored as "no limit" and "any".
+func promoCodeArgs(promoCode PromoCode) []any {
+	return []any{
+		promoCode.Code,
+		sql.NullString{String: promoCode.Description, Valid: promoCode.Description != ""},
+		promoCode.DiscountType,
+		promoCodeDiscountValue(promoCode),
+		promoCode.ValidFrom,
+		promoCode.ValidUntil,
+		sql.NullInt64{Int64: int64(promoCode.MaxUses), Valid: promoCode.MaxUses > 0},
+		sql.NullInt64{Int64: int64(promoCode.MaxUsesPerUser), Valid: promoCode.MaxUsesPerUser > 0},
+		sql.NullInt64{Int64: int64(promoCode.MovieID), Valid: promoCode.MovieID > 0},
+		sql.NullString{String: promoCode.HallType, Valid: promoCode.HallType != ""},
+		sql.NullString{String: promoCode.SeatType, Valid: promoCode.SeatType != ""},
+	}
+}
+
+// promoCodeDiscountValue returns the value stored in promo_code.discount_value: the percent off, or
+// the amount off in minor units.
+func promoCodeDiscountValue(promoCode PromoCode) int64 {
+	if promoCode.DiscountType == "Percentage" {
+		return int64(promoCode.DiscountPercent)
+	}
+
+	return promoCode.DiscountAmount.Amount
+}
+
+// InsertNewPromoCode inserts a new promo code into the database.
+//
+// Parameters:
+//   - promoCode (PromoCode): The code to insert. Zero limits and empty restrictions are stored as NULL.
+//
+// Returns:
+//   - error: ErrPromoCodeAlreadyExists if the code is taken, ErrMovieNotFoundByID if the movie does not exist,
+//     or a wrapped error if the insertion fails.
+func (psql *Postgres) InsertNewPromoCode(promoCode PromoCode) error {
+	// SQL query to insert a new promo code into the promo_code table
+	stmt := `INSERT INTO promo_code (code, description, discount_type, discount_value, valid_from, valid_until, max_uses, max_uses_per_user, movie_id, hall_type, seat_type) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := psql.DB.Exec(stmt, promoCodeArgs(promoCode)...)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			// The code is unique across every campaign.
+			if pqErr.Code == "23505" {
+				return ErrPromoCodeAlreadyExists
+			}
+			// The movie the code is restricted to does not exist.
+			if pqErr.Code == "23503" {
+				return ErrMovieNotFoundByID
+			}
+		}
+		return fmt.Errorf("failed to insert new promo code: %w", err)
+	}
+
+	return nil
+}
+
+// RetrieveAllPromoCodes retrieves every promo code for the admin page, newest first.
+//
+// Returns:
+//   - []PromoCode: All promo codes with how many times each one is in use.
+//   - error: ErrPromoCodeNotFound if there is no promo code yet, or a wrapped error if the query fails.
+func (psql *Postgres) RetrieveAllPromoCodes() ([]PromoCode, error) {
+	stmt := promoCodeColumns + ` ORDER BY pc.promo_code_id DESC`
+
+	rows, err := psql.DB.Query(stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve promo codes: %w", err)
 	}
 
-	// Check if any rows were affected by the update
+	// Ensure the rows are closed after the function finishes
+	defer rows.Close()
+
+	var promoCodes []PromoCode
+
+	for rows.Next() {
+		promoCode, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan promo code: %w", err)
+		}
+		promoCodes = append(promoCodes, promoCode)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over promo codes: %w", err)
+	}
+
+	if len(promoCodes) == 0 {
+		return nil, ErrPromoCodeNotFound
+	}
+
+	return promoCodes, nil
+}
+
+// RetrievePromoCodeByID retrieves a single promo code by its ID.
+//
+// Parameters:
+//   - promoCodeID (int): The ID of the promo code.
+//
+// Returns:
+//   - PromoCode: The promo code with how many times it is in use.
+//   - error: ErrPromoCodeNotFound if there is no such code, or a wrapped error if the query fails.
+func (psql *Postgres) RetrievePromoCodeByID(promoCodeID int) (PromoCode, error) {
+	stmt := promoCodeColumns + ` WHERE pc.promo_code_id = $1`
+
+	promoCode, err := scanPromoCode(psql.DB.QueryRow(stmt, promoCodeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PromoCode{}, ErrPromoCodeNotFound
+		}
+		return PromoCode{}, fmt.Errorf("failed to retrieve promo code: %w", err)
+	}
+
+	return promoCode, nil
+}
+
+// UpdatePromoCodeByID replaces every editable field of a promo code.
+//
+// Parameters:
+//   - promoCode (PromoCode): The new values, identified by PromoCodeID.
+//
+// Returns:
+//   - error: ErrPromoCodeNotFound, ErrPromoCodeAlreadyExists, ErrMovieNotFoundByID, or a wrapped error if the update fails.
+func (psql *Postgres) UpdatePromoCodeByID(promoCode PromoCode) error {
+	stmt := `UPDATE promo_code SET code = $1, description = $2, discount_type = $3, discount_value = $4, valid_from = $5, valid_until = $6, max_uses = $7, max_uses_per_user = $8, movie_id = $9, hall_type = $10, seat_type = $11, updated_at = CURRENT_TIMESTAMP WHERE promo_code_id = $12`
+
+	result, err := psql.DB.Exec(stmt, append(promoCodeArgs(promoCode), promoCode.PromoCodeID)...)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return ErrPromoCodeAlreadyExists
+			}
+			if pqErr.Code == "23503" {
+				return ErrMovieNotFoundByID
+			}
+		}
+		return fmt.Errorf("failed to update promo code with ID %d: %w", promoCode.PromoCodeID, err)
+	}
+
+	// Check how many rows were affected by the update
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		// Return error if we can't check the number of affected rows
-		return fmt.Errorf("error occurred while checking affected rows: %w", err)
+		return fmt.Errorf("failed to check affected rows: %w", err)
 	}
 
-	// If no rows were affected, return a custom error indicating the seat wasn't found
 	if rowsAffected == 0 {
-		return ErrShowSeatNotFound
+		return ErrPromoCodeNotFound
+	}
+
+	return nil
+}
+
+// DeletePromoCodeByID deletes a promo code. Bookings that used it keep their discount.
+//
+// Parameters:
+//   - promoCodeID (int): The ID of the promo code to delete.
+//
+// Returns:
+//   - error: ErrPromoCodeNotFound if there is no such code, or a wrapped error if the delete fails.
+func (psql *Postgres) DeletePromoCodeByID(promoCodeID int) error {
+	result, err := psql.DB.Exec(`DELETE FROM promo_code WHERE promo_code_id = $1`, promoCodeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete promo code: %w", err)
+	}
+
+	// Check how many rows were affected by the delete operation
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrPromoCodeNotFound
+	}
+
+	return nil
+}
+
+// priceRuleArgs turns the optional criteria of a price rule into NULLs when they are not set,
+// so they are stored as "any".
+func priceRuleArgs(priceRule PriceRule) []any {
+	var dayOfWeek sql.NullInt64
+	if priceRule.DayOfWeek != nil {
+		dayOfWeek = sql.NullInt64{Int64: int64(*priceRule.DayOfWeek), Valid: true}
+	}
+
+	return []any{
+		priceRule.RuleName,
+		sql.NullString{String: priceRule.HallType, Valid: priceRule.HallType != ""},
+		sql.NullString{String: priceRule.SeatType, Valid: priceRule.SeatType != ""},
+		dayOfWeek,
+		sql.NullString{String: priceRule.StartTimeFrom, Valid: priceRule.StartTimeFrom != ""},
+		sql.NullString{String: priceRule.StartTimeUntil, Valid: priceRule.StartTimeUntil != ""},
+		priceRule.Price,
+	}
+}
+
+// InsertNewPriceRule inserts a new seat price rule into the database.
+//
+// Parameters:
+//   - priceRule (PriceRule): The rule to insert. Empty criteria are stored as NULL and match anything.
+//
+// Returns:
+//   - error: A wrapped error if the insertion fails.
+func (psql *Postgres) InsertNewPriceRule(priceRule PriceRule) error {
+	// SQL query to insert a new rule into the price_rule table
+	stmt := `INSERT INTO price_rule (rule_name, hall_type, seat_type, day_of_week, start_time_from, start_time_until, price) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := psql.DB.Exec(stmt, priceRuleArgs(priceRule)...)
+	if err != nil {
+		return fmt.Errorf("failed to insert new price rule: %w", err)
+	}
+
+	return nil
+}
+
+// RetrieveAllPriceRules retrieves every seat price rule. Start times are returned as "HH:MM".
+//
+// Returns:
+//   - []PriceRule: All price rules, oldest first.
+//   - error: ErrPriceRuleNotFound if there is no rule yet, or a wrapped error if the query fails.
+func (psql *Postgres) RetrieveAllPriceRules() ([]PriceRule, error) {
+	stmt := `SELECT price_rule_id, rule_name, COALESCE(hall_type, ''), COALESCE(seat_type, ''), day_of_week, COALESCE(TO_CHAR(start_time_from, 'HH24:MI'), ''), COALESCE(TO_CHAR(start_time_until, 'HH24:MI'), ''), price FROM price_rule ORDER BY price_rule_id`
+
+	rows, err := psql.DB.Query(stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve price rules: %w", err)
+	}
+
+	// Ensure the rows are closed after the function finishes
+	defer rows.Close()
+
+	var priceRules []PriceRule
+
+	for rows.Next() {
+		var priceRule PriceRule
+		var dayOfWeek sql.NullInt64
+
+		err := rows.Scan(&priceRule.PriceRuleID, &priceRule.RuleName, &priceRule.HallType, &priceRule.SeatType, &dayOfWeek,
+			&priceRule.StartTimeFrom, &priceRule.StartTimeUntil, &priceRule.Price)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price rule: %w", err)
+		}
+
+		if dayOfWeek.Valid {
+			day := int(dayOfWeek.Int64)
+			priceRule.DayOfWeek = &day
+		}
+
+		priceRules = append(priceRules, priceRule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over price rules: %w", err)
+	}
+
+	if len(priceRules) == 0 {
+		return nil, ErrPriceRuleNotFound
+	}
+
+	return priceRules, nil
+}
+
+// UpdatePriceRuleByID replaces every field of a seat price rule. Show seats already priced by the
+// rule keep their price.
+//
+// Parameters:
+//   - priceRule (PriceRule): The new values, identified by PriceRuleID.
+//
+// Returns:
+//   - error: ErrPriceRuleNotFound if there is no such rule, or a wrapped error if the update fails.
+func (psql *Postgres) UpdatePriceRuleByID(priceRule PriceRule) error {
+	stmt := `UPDATE price_rule SET rule_name = $1, hall_type = $2, seat_type = $3, day_of_week = $4, start_time_from = $5, start_time_until = $6, price = $7, updated_at = CURRENT_TIMESTAMP WHERE price_rule_id = $8`
+
+	result, err := psql.DB.Exec(stmt, append(priceRuleArgs(priceRule), priceRule.PriceRuleID)...)
+	if err != nil {
+		return fmt.Errorf("failed to update price rule with ID %d: %w", priceRule.PriceRuleID, err)
+	}
+
+	// Check how many rows were affected by the update
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrPriceRuleNotFound
+	}
+
+	return nil
+}
+
+// DeletePriceRuleByID deletes a seat price rule. Show seats already priced by the rule keep their price.
+//
+// Parameters:
+//   - priceRuleID (int): The ID of the price rule to delete.
+//
+// Returns:
+//   - error: ErrPriceRuleNotFound if there is no such rule, or a wrapped error if the delete fails.
+func (psql *Postgres) DeletePriceRuleByID(priceRuleID int) error {
+	result, err := psql.DB.Exec(`DELETE FROM price_rule WHERE price_rule_id = $1`, priceRuleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete price rule: %w", err)
+	}
+
+	// Check how many rows were affected by the delete operation
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrPriceRuleNotFound
+	}
+
+	return nil
+}
+
+// ticketCategoryArgs turns a ticket category into the arguments of its INSERT and UPDATE statements,
+// storing a missing age bound as NULL.
+func ticketCategoryArgs(ticketCategory TicketCategory) []any {
+	return []any{
+		ticketCategory.Name,
+		ticketCategory.PricePercent,
+		sql.NullInt64{Int64: int64(ticketCategory.MinAge), Valid: ticketCategory.MinAge != 0},
+		sql.NullInt64{Int64: int64(ticketCategory.MaxAge), Valid: ticketCategory.MaxAge != 0},
+	}
+}
+
+// InsertNewTicketCategory inserts a new ticket category into the database.
+//
+// Parameters:
+//   - ticketCategory (TicketCategory): The category to insert. An age bound of 0 is stored as "no bound".
+//
+// Returns:
+//   - error: ErrTicketCategoryAlreadyExists if the name is taken, or a wrapped error if the insertion fails.
+func (psql *Postgres) InsertNewTicketCategory(ticketCategory TicketCategory) error {
+	stmt := `INSERT INTO ticket_category (name, price_percent, min_age, max_age) VALUES ($1, $2, $3, $4)`
+
+	_, err := psql.DB.Exec(stmt, ticketCategoryArgs(ticketCategory)...)
+	if err != nil {
+		// Check if the error is a unique constraint violation on the name.
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return ErrTicketCategoryAlreadyExists
+		}
+		return fmt.Errorf("failed to insert new ticket category: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateTicketCategoryByID replaces every field of a ticket category. Seats already booked in the
+// category keep the price they were charged.
+//
+// Parameters:
+//   - ticketCategory (TicketCategory): The new values, identified by TicketCategoryID.
+//
+// Returns:
+//   - error: ErrTicketCategoryNotFound if there is no such category, ErrTicketCategoryAlreadyExists if the
+//     name is taken, or a wrapped error if the update fails.
+func (psql *Postgres) UpdateTicketCategoryByID(ticketCategory TicketCategory) error {
+	stmt := `UPDATE ticket_category SET name = $1, price_percent = $2, min_age = $3, max_age = $4, updated_at = CURRENT_TIMESTAMP WHERE ticket_category_id = $5`
+
+	result, err := psql.DB.Exec(stmt, append(ticketCategoryArgs(ticketCategory), ticketCategory.TicketCategoryID)...)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return ErrTicketCategoryAlreadyExists
+		}
+		return fmt.Errorf("failed to update ticket category with ID %d: %w", ticketCategory.TicketCategoryID, err)
+	}
+
+	// Check how many rows were affected by the update
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrTicketCategoryNotFound
+	}
+
+	return nil
+}
+
+// concessionItemArgs turns a concession item into the arguments of its INSERT and UPDATE statements,
+// storing an empty description as NULL and unlimited stock as NULL.
+func concessionItemArgs(concessionItem ConcessionItem) []any {
+	var stock sql.NullInt64
+	if concessionItem.Stock != nil {
+		stock = sql.NullInt64{Int64: int64(*concessionItem.Stock), Valid: true}
+	}
+
+	return []any{
+		concessionItem.Name,
+		sql.NullString{String: concessionItem.Description, Valid: concessionItem.Description != ""},
+		concessionItem.Category,
+		concessionItem.Price,
+		stock,
+		concessionItem.Active,
+	}
+}
+
+// concessionItemError maps the constraint violations of a concession item write to their sentinel errors.
+func concessionItemError(err error, action string) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case "23505":
+			return ErrConcessionItemAlreadyExists
+		case "23503":
+			// A combo component that does not exist.
+			return ErrConcessionItemNotFound
+		}
+	}
+
+	return fmt.Errorf("failed to %s concession item: %w", action, err)
+}
+
+// replaceConcessionComboItems stores the items a combo contains, replacing what it contained before.
+func replaceConcessionComboItems(tx *sql.Tx, comboID int, comboItems []ConcessionComboItem) error {
+	if _, err := tx.Exec(`DELETE FROM concession_combo_item WHERE combo_id = $1`, comboID); err != nil {
+		return fmt.Errorf("failed to delete concession combo items: %w", err)
+	}
+
+	for _, comboItem := range comboItems {
+		_, err := tx.Exec(`INSERT INTO concession_combo_item (combo_id, component_id, quantity) VALUES ($1, $2, $3)`, comboID, comboItem.ConcessionItemID, comboItem.Quantity)
+		if err != nil {
+			return concessionItemError(err, "insert combo items of")
+		}
+	}
+
+	return nil
+}
+
+// InsertNewConcessionItem inserts a new food or drink item, and the items it contains if it is a combo,
+// in a single transaction.
+//
+// Parameters:
+//   - concessionItem (ConcessionItem): The item to insert. A nil Stock is stored as unlimited.
+//
+// Returns:
+//   - error: ErrConcessionItemAlreadyExists if the name is taken, ErrConcessionItemNotFound if a combo
+//     contains an item that does not exist, or a wrapped error if the insertion fails.
+func (psql *Postgres) InsertNewConcessionItem(concessionItem ConcessionItem) error {
+	tx, err := psql.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin concession item transaction: %w", err)
+	}
+
+	// Rolling back after a successful commit is a no-op.
+	defer tx.Rollback()
+
+	stmt := `INSERT INTO concession_item (name, description, category, price, stock, active) VALUES ($1, $2, $3, $4, $5, $6) RETURNING concession_item_id`
+
+	var concessionItemID int
+	if err := tx.QueryRow(stmt, concessionItemArgs(concessionItem)...).Scan(&concessionItemID); err != nil {
+		return concessionItemError(err, "insert new")
+	}
+
+	if err := replaceConcessionComboItems(tx, concessionItemID, concessionItem.ComboItems); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit concession item transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateConcessionItemByID replaces every field of a concession item and the items it contains.
+// Orders already placed keep the price they were charged.
+//
+// Parameters:
+//   - concessionItem (ConcessionItem): The new values, identified by ConcessionItemID.
+//
+// Returns:
+//   - error: ErrConcessionItemNotFound if there is no such item or a combo contains an item that does not
+//     exist, ErrConcessionItemAlreadyExists if the name is taken, or a wrapped error if the update fails.
+func (psql *Postgres) UpdateConcessionItemByID(concessionItem ConcessionItem) error {
+	tx, err := psql.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin concession item transaction: %w", err)
+	}
+
+	// Rolling back after a successful commit is a no-op.
+	defer tx.Rollback()
+
+	stmt := `UPDATE concession_item SET name = $1, description = $2, category = $3, price = $4, stock = $5, active = $6, updated_at = CURRENT_TIMESTAMP WHERE concession_item_id = $7`
+
+	result, err := tx.Exec(stmt, append(concessionItemArgs(concessionItem), concessionItem.ConcessionItemID)...)
+	if err != nil {
+		return concessionItemError(err, "update")
+	}
+
+	// Check how many rows were affected by the update
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrConcessionItemNotFound
+	}
+
+	if err := replaceConcessionComboItems(tx, concessionItem.ConcessionItemID, concessionItem.ComboItems); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit concession item transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteConcessionItemByID deletes a concession item that was never ordered and is not part of a combo.
+// Items with orders should be deactivated instead, so past bookings keep their lines.
+//
+// Parameters:
+//   - concessionItemID (int): The ID of the item to delete.
+//
+// Returns:
+//   - error: ErrConcessionItemNotFound if there is no such item, ErrConcessionItemInUse if it was ordered
+//     or a combo contains it, or a wrapped error if the delete fails.
+func (psql *Postgres) DeleteConcessionItemByID(concessionItemID int) error {
+	result, err := psql.DB.Exec(`DELETE FROM concession_item WHERE concession_item_id = $1`, concessionItemID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return ErrConcessionItemInUse
+		}
+		return fmt.Errorf("failed to delete concession item: %w", err)
+	}
+
+	// Check how many rows were affected by the delete operation
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrConcessionItemNotFound
 	}
 
-	// Return nil if the update was successful
 	return nil
 }
@@ -4,8 +4,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type DBContractBooking interface {
@@ -13,12 +14,27 @@ type DBContractBooking interface {
 	RetrieveShowInfo(movieID int) ([]ShowInfo, error)
 	RetrieveShowStartTimes(showDate string) ([]ShowStartTime, error)
 	RetrieveShowSeats(showID int) ([]ShowSeat, error)
+	RetrieveShowHallLayout(showID int) (HallLayout, error)
 	RetrieveShowSeatsMovieInfo(showID int) (ShowSeatsMovieInfo, error)
+	RetrieveShowSeatsByIDs(showID int, showSeatIDs []int) ([]ShowSeat, error)
+	RetrieveUserBookings(userID int, when string, limit, offset int) ([]BookingHistoryEntry, error)
+	CountUserBookings(userID int, when string) (int, error)
+	RetrieveUserBooking(bookingID, userID int) (BookingDetail, error)
+	RetrieveBookingByReference(bookingReference string) (BookingDetail, error)
+	RetrieveReceipt(bookingID int) (ReceiptRecord, error)
+	RetrieveShowMovieHall(showID int) (ShowMovieHall, error)
+	RetrievePromoCode(code string) (PromoCode, error)
+	RetrieveAllTicketCategories() ([]TicketCategory, error)
+	RetrieveConcessionItems(activeOnly bool) ([]ConcessionItem, error)
+	RetrieveExchangeRates() ([]ExchangeRate, error)
+	RetrieveWaitlistEntry(showID, userID int) (WaitlistEntry, error)
 
-	RetrieveShowSeatStatus(showSeatID, showID int) (string, error)
-	UpdateShowSeatStatus(status string, showSeatID, showID int) error
-	InsertNewBooking(numberOfSeats int, paymentStatus string, userID, showID int) (int, error)
-	InsertPaymentDetails(amount, remoteTransactionID int, paymentMethod string, bookingID int) error
+	BeginBookingTx() (BookingTx, error)
+	ReleaseSeatHold(holdID, userID int) (int, error)
+	ExpireSeatHolds() ([]int, error)
+	RetrievePendingRefunds() ([]PendingRefund, error)
+	InsertWaitlistEntry(showID, userID, numberOfSeats int) (int, error)
+	CancelWaitlistEntry(showID, userID int) (bool, error)
 }
 
 type Bookings struct {
@@ -73,7 +89,7 @@ func (psql *Postgres) RetrieveShowMovieInfo(showID int) (ShowMovieInfo, error) {
 //   - error: If something goes wrong during the database query or data processing, an error
 //     will be returned.
 func (psql *Postgres) RetrieveShowInfo(movieID int) ([]ShowInfo, error) {
-	stmt := `SELECT DISTINCT ch.hall_name, ch.hall_type, s.show_date FROM cinema_hall ch JOIN show s ON ch.cinema_hall_id = s.hall_id WHERE s.movie_id = $1 ORDER BY s.show_date`
+	stmt := `SELECT DISTINCT ch.hall_name, ch.hall_type, ch.currency, s.show_date FROM cinema_hall ch JOIN show s ON ch.cinema_hall_id = s.hall_id WHERE s.movie_id = $1 ORDER BY s.show_date`
 
 	// Execute the query and get the rows of data.
 	rows, err := psql.DB.Query(stmt, movieID)
@@ -89,7 +105,7 @@ func (psql *Postgres) RetrieveShowInfo(movieID int) ([]ShowInfo, error) {
 	for rows.Next() {
 		var showInfo ShowInfo
 
-		err := rows.Scan(&showInfo.HallName, &showInfo.HallType, &showInfo.ShowDate)
+		err := rows.Scan(&showInfo.HallName, &showInfo.HallType, &showInfo.Currency, &showInfo.ShowDate)
 		if err != nil {
 			// Handle edge cases where no rows are returned (not necessarily an error).
 			if errors.Is(err, sql.ErrNoRows) {
@@ -157,7 +173,11 @@ func (psql *Postgres) RetrieveShowStartTimes(showDate string) ([]ShowStartTime,
 
 // RetrieveShowSeats retrieves a list of all seats for a given show, including seat details
 // such as the row, seat number, type, show seat ID, status, and price. It queries the database
-// to get this information by joining the `cinema_seat`, `show_seat`, and `show` tables.
+// to get this information by joining the `cinema_seat`, `show_seat`, `show` and `cinema_hall` tables;
+// prices are in the currency of the hall. Seats carry their place on the hall layout, if the hall has one.
+//
+// A "Selected" seat whose hold has lapsed but has not been swept by the expirer yet is
+// reported as "Available", so customers never see stale holds.
 //
 // Params:
 //   - showID (int): The ID of the show for which the seats need to be retrieved.
@@ -167,7 +187,7 @@ func (psql *Postgres) RetrieveShowStartTimes(showDate string) ([]ShowStartTime,
 //     for the specified show, including row, seat number, type, status, and price.
 //   - error: An error if the query fails, or if there is any issue scanning the results.
 func (psql *Postgres) RetrieveShowSeats(showID int) ([]ShowSeat, error) {
-	stmt := `SELECT cs.seat_row, cs.seat_number, cs.seat_type, ss.show_seat_id, ss.status, ss.price FROM cinema_seat cs JOIN show_seat ss ON cs.cinema_seat_id = ss.cinema_seat_id JOIN show s ON ss.show_id = s.show_id WHERE s.show_id = $1`
+	stmt := `SELECT cs.seat_row, cs.seat_number, cs.seat_type, ss.show_seat_id, CASE WHEN ss.status = 'Selected' AND sh.hold_id IS NULL THEN 'Available' ELSE ss.status END AS status, COALESCE(ss.price, 0), ch.currency, COALESCE(cs.grid_x, 0), COALESCE(cs.grid_y, 0), cs.angle FROM cinema_seat cs JOIN show_seat ss ON cs.cinema_seat_id = ss.cinema_seat_id JOIN show s ON ss.show_id = s.show_id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id LEFT JOIN seat_hold sh ON ss.hold_id = sh.hold_id AND sh.status = 'Active' AND sh.expires_at > NOW() WHERE s.show_id = $1 ORDER BY cs.seat_row, cs.seat_number`
 
 	// Execute the query using the provided showID.
 	rows, err := psql.DB.Query(stmt, showID)
@@ -179,38 +199,83 @@ func (psql *Postgres) RetrieveShowSeats(showID int) ([]ShowSeat, error) {
 	// Ensure that rows are closed after processing to avoid resource leaks.
 	defer rows.Close()
 
-	// Check if no rows were returned
-	if !rows.Next() {
-		// If no rows are found, return a custom error indicating no seats are found
-		return nil, ErrShowSeatNotFound
-	}
-
 	var showSeats []ShowSeat
 
 	// Iterate through the result rows and scan each seat's information into the ShowSeat struct.
 	for rows.Next() {
 		var showSeat ShowSeat
+		var currency string
 
 		// Scan the current row of data into the showSeat struct.
 		err := rows.Scan(&showSeat.SeatRow, &showSeat.SeatNumber, &showSeat.SeatType,
-			&showSeat.ShowSeatID, &showSeat.SeatStatus, &showSeat.SeatPrice)
+			&showSeat.ShowSeatID, &showSeat.SeatStatus, &showSeat.SeatPrice, &currency, &showSeat.GridX, &showSeat.GridY, &showSeat.Angle)
 		if err != nil {
-			// Handle the case where no rows are found.
-			if errors.Is(err, sql.ErrNoRows) {
-				return nil, ErrShowSeatNotFound
-			}
 			// Return an error if scanning fails.
 			return nil, fmt.Errorf("failed to scan show seats: %w", err)
 		}
+		showSeat.SeatPrice.Currency = currency
 
 		// Append the successfully scanned seat to the result slice.
 		showSeats = append(showSeats, showSeat)
 	}
 
+	// If no seats were found, return a custom error indicating no seats are found.
+	if len(showSeats) == 0 {
+		return nil, ErrShowSeatNotFound
+	}
+
 	// Return the list of show seats.
 	return showSeats, nil
 }
 
+// RetrieveShowSeatsByIDs retrieves the given seats of a show without locking them. It is used
+// to preview the price of a selection before the customer commits to it.
+//
+// Params:
+//   - showID (int): The ID of the show the seats belong to.
+//   - showSeatIDs ([]int): The IDs of the show seats to retrieve.
+//
+// Returns:
+//   - []ShowSeat: The requested seats with their position, status and price in the currency of the hall.
+//   - error: ErrShowSeatNotFound if any of the seats does not belong to the show,
+//     or a wrapped error if the query fails.
+func (psql *Postgres) RetrieveShowSeatsByIDs(showID int, showSeatIDs []int) ([]ShowSeat, error) {
+	stmt := `SELECT cs.seat_row, cs.seat_number, cs.seat_type, ss.show_seat_id, CASE WHEN ss.status = 'Selected' AND sh.hold_id IS NULL THEN 'Available' ELSE ss.status END AS status, ss.price, ch.currency FROM show_seat ss JOIN cinema_seat cs ON ss.cinema_seat_id = cs.cinema_seat_id JOIN show s ON ss.show_id = s.show_id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id LEFT JOIN seat_hold sh ON ss.hold_id = sh.hold_id AND sh.status = 'Active' AND sh.expires_at > NOW() WHERE ss.show_id = $1 AND ss.show_seat_id = ANY($2) ORDER BY ss.show_seat_id`
+
+	rows, err := psql.DB.Query(stmt, showID, pq.Array(showSeatIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve selected show seats from the database: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	var showSeats []ShowSeat
+
+	for rows.Next() {
+		var showSeat ShowSeat
+
+		// A seat may not have a price yet, so scan it through a nullable value.
+		var price sql.NullInt64
+		var currency string
+		err := rows.Scan(&showSeat.SeatRow, &showSeat.SeatNumber, &showSeat.SeatType,
+			&showSeat.ShowSeatID, &showSeat.SeatStatus, &price, &currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan selected show seats: %w", err)
+		}
+		showSeat.SeatPrice = NewMoney(price.Int64, currency)
+
+		showSeats = append(showSeats, showSeat)
+	}
+
+	// Every requested seat must exist and belong to the given show.
+	if len(showSeats) != len(showSeatIDs) {
+		return nil, ErrShowSeatNotFound
+	}
+
+	return showSeats, nil
+}
+
 // RetrieveShowSeatsMovieInfo retrieves movie details (such as title, show ID, show date, and start time)
 // for a specific show using the showID. This is used to gather necessary information for the seats page.
 //
@@ -242,123 +307,1088 @@ func (psql *Postgres) RetrieveShowSeatsMovieInfo(showID int) (ShowSeatsMovieInfo
 	return showSeatsMovieInfo, nil
 }
 
-// RetrieveShowSeatStatus retrieves the status of a specific seat for a given show.
-// The status is returned as a string (e.g., "Available", "Selected", etc.).
+// RetrieveShowMovieHall retrieves the movie and the type of hall of a show, which promo codes
+// may be restricted to, the currency the hall charges in, and the movie's age limit, which ticket
+// categories are checked against.
 //
 // Params:
-//   - showSeatID (int): The ID of the seat to retrieve the status for.
-//   - showID (int): The ID of the show to which the seat belongs.
+//   - showID (int): The ID of the show.
+//
+// Returns:
+//   - ShowMovieHall: The show's movie ID, hall type, currency and age limit.
+//   - error: ErrShowNotFound if the show does not exist, or a wrapped error.
+func (psql *Postgres) RetrieveShowMovieHall(showID int) (ShowMovieHall, error) {
+	stmt := `SELECT s.show_id, s.movie_id, COALESCE(ch.hall_type, ''), ch.currency, COALESCE(m.age_limit, '') FROM show s JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id JOIN movies m ON s.movie_id = m.id WHERE s.show_id = $1`
+
+	var showMovieHall ShowMovieHall
+	err := psql.DB.QueryRow(stmt, showID).Scan(&showMovieHall.ShowID, &showMovieHall.MovieID, &showMovieHall.HallType, &showMovieHall.Currency, &showMovieHall.MovieAgeLimit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ShowMovieHall{}, ErrShowNotFound
+		}
+		return ShowMovieHall{}, fmt.Errorf("failed to retrieve movie and hall of the show: %w", err)
+	}
+
+	return showMovieHall, nil
+}
+
+// RetrieveAllTicketCategories retrieves every ticket category customers can pick for a seat.
 //
 // Returns:
-//   - string: The status of the seat, such as "Available", "Selected", etc.
-//   - error: An error if the query fails, or if the seat or show is not found.
-func (psql *Postgres) RetrieveShowSeatStatus(showSeatID, showID int) (string, error) {
-	// SQL query to retrieve the status of a specific seat for a given show.
-	stmt := `SELECT status FROM show_seat WHERE show_seat_id = $1 AND show_id = $2`
+//   - []TicketCategory: All ticket categories, oldest first. A missing age bound is returned as 0.
+//   - error: ErrTicketCategoryNotFound if there is no category, or a wrapped error if the query fails.
+func (psql *Postgres) RetrieveAllTicketCategories() ([]TicketCategory, error) {
+	stmt := `SELECT ticket_category_id, name, price_percent, COALESCE(min_age, 0), COALESCE(max_age, 0) FROM ticket_category ORDER BY ticket_category_id`
+
+	rows, err := psql.DB.Query(stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve ticket categories: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	var ticketCategories []TicketCategory
+
+	for rows.Next() {
+		var ticketCategory TicketCategory
+		err := rows.Scan(&ticketCategory.TicketCategoryID, &ticketCategory.Name, &ticketCategory.PricePercent, &ticketCategory.MinAge, &ticketCategory.MaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ticket category: %w", err)
+		}
+		ticketCategories = append(ticketCategories, ticketCategory)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over ticket categories: %w", err)
+	}
+
+	if len(ticketCategories) == 0 {
+		return nil, ErrTicketCategoryNotFound
+	}
 
-	// Variable to hold the seat status.
-	var showSeatStatus string
+	return ticketCategories, nil
+}
 
-	// Execute the query and scan the result into the showSeatStatus variable.
-	err := psql.DB.QueryRow(stmt, showSeatID, showID).Scan(&showSeatStatus)
+// RetrievePromoCode looks a promo code up by the code customers type, without locking it.
+// It is used to preview a discount; the booking itself locks the code with LockPromoCode.
+//
+// Params:
+//   - code (string): The promo code, in upper case.
+//
+// Returns:
+//   - PromoCode: The promo code with how many times it is in use.
+//   - error: ErrPromoCodeNotFound if there is no such code, or a wrapped error.
+func (psql *Postgres) RetrievePromoCode(code string) (PromoCode, error) {
+	promoCode, err := scanPromoCode(psql.DB.QueryRow(promoCodeColumns+` WHERE pc.code = $1`, code))
 	if err != nil {
-		// Handle case where no result is found for the given showSeatID and showID.
 		if errors.Is(err, sql.ErrNoRows) {
-			// Return a specific error indicating no data found.
-			return "", ErrShowNotFound
+			return PromoCode{}, ErrPromoCodeNotFound
 		}
-		// Return a formatted error with more context if any other error occurs.
-		return "", fmt.Errorf("failed to retrieve show seat status: %w", err)
+		return PromoCode{}, fmt.Errorf("failed to retrieve promo code: %w", err)
 	}
 
-	// Return the status of the seat if no errors occurred.
-	return showSeatStatus, nil
+	return promoCode, nil
+}
+
+// bookingHistoryColumns selects one booking of a user together with its show, movie, hall, seats
+// and payment. Seats are found both through show_seat.booking_id and through the booking's items,
+// so cancelled or failed bookings whose seats were released still list them. The amounts are in the
+// currency of the hall, which comes right after them.
+const bookingHistoryColumns = `SELECT b.booking_id, b.status, b.booking_reference, b.show_id, m.title, COALESCE(m.poster_url, ''), ch.hall_name, COALESCE(ch.hall_type, ''), TO_CHAR(s.show_date, 'YYYY-MM-DD'), TO_CHAR(s.start_time, 'HH24:MI'), b.number_of_seats, COALESCE(ARRAY_AGG(COALESCE(cs.seat_row, '') || cs.seat_number ORDER BY cs.seat_row, cs.seat_number) FILTER (WHERE cs.cinema_seat_id IS NOT NULL), '{}'), COALESCE(p.amount, 0), COALESCE((SELECT SUM(r.amount) FROM refund r WHERE r.payment_id = p.payment_id), 0), ch.currency, COALESCE(p.status, '')`
+
+// bookingHistoryJoins joins every table a booking history entry is built from.
+const bookingHistoryJoins = ` FROM booking b JOIN show s ON b.show_id = s.show_id JOIN movies m ON s.movie_id = m.id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id LEFT JOIN payment p ON p.booking_id = b.booking_id LEFT JOIN show_seat ss ON ss.booking_id = b.booking_id OR ss.show_seat_id IN (SELECT bi.show_seat_id FROM booking_item bi WHERE bi.booking_id = b.booking_id) LEFT JOIN cinema_seat cs ON ss.cinema_seat_id = cs.cinema_seat_id`
+
+// bookingHistoryGroupBy groups the seat rows back into one row per booking.
+const bookingHistoryGroupBy = ` GROUP BY b.booking_id, s.show_id, m.id, ch.cinema_hall_id, p.payment_id`
+
+// bookingTimeFilter returns the SQL condition restricting bookings to past or upcoming shows.
+// Any other value of when matches every booking.
+func bookingTimeFilter(when string) string {
+	switch when {
+	case "upcoming":
+		return ` AND s.show_date + s.start_time >= LOCALTIMESTAMP`
+	case "past":
+		return ` AND s.show_date + s.start_time < LOCALTIMESTAMP`
+	default:
+		return ``
+	}
 }
 
-// UpdateShowSeatStatus updates the status of a specific seat for a given show.
+// RetrieveUserBookings retrieves one page of a user's booking history, including the movie,
+// hall, show date and time, seats, amount paid and status of every booking.
 //
-// This function allows updating the status of a seat, such as marking it as "Selected", "Booked", etc.
+// Upcoming bookings are ordered with the next show first; past and unfiltered bookings are
+// ordered with the most recent show first.
 //
 // Params:
-//   - status (string): The new status to assign to the seat (e.g., "Selected", "Booked").
-//   - showSeatID (int): The ID of the seat to update the status for.
-//   - showID (int): The ID of the show to which the seat belongs.
+//   - userID (int): The ID of the user whose bookings are listed.
+//   - when (string): "upcoming" or "past" to filter on the show time, or "" for every booking.
+//   - limit (int): The maximum number of bookings to return.
+//   - offset (int): The number of bookings to skip.
 //
 // Returns:
-//   - error: An error if the query fails, or if the seat/show combination is not found.
-func (psql *Postgres) UpdateShowSeatStatus(status string, showSeatID, showID int) error {
-	// SQL query to update the status of a specific seat for a given show.
-	stmt := `UPDATE show_seat SET status = $1 WHERE show_seat_id = $2 AND show_id = $3`
+//   - []BookingHistoryEntry: The bookings of the page; empty when there are none.
+//   - error: A wrapped error if the query fails.
+func (psql *Postgres) RetrieveUserBookings(userID int, when string, limit, offset int) ([]BookingHistoryEntry, error) {
+	order := ` ORDER BY s.show_date DESC, s.start_time DESC, b.booking_id DESC`
+	if when == "upcoming" {
+		order = ` ORDER BY s.show_date, s.start_time, b.booking_id`
+	}
+
+	stmt := bookingHistoryColumns + bookingHistoryJoins + ` WHERE b.user_id = $1` + bookingTimeFilter(when) + bookingHistoryGroupBy + order + ` LIMIT $2 OFFSET $3`
 
-	// Execute the update query with the provided status, showSeatID, and showID.
-	_, err := psql.DB.Exec(stmt, status, showSeatID, showID)
+	rows, err := psql.DB.Query(stmt, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user bookings from the database: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	bookings := []BookingHistoryEntry{}
+
+	for rows.Next() {
+		var booking BookingHistoryEntry
+		var currency string
+
+		err := rows.Scan(&booking.BookingID, &booking.BookingStatus, &booking.BookingReference, &booking.ShowID, &booking.MovieTitle, &booking.MoviePosterUrl,
+			&booking.HallName, &booking.HallType, &booking.ShowDate, &booking.ShowStartTime, &booking.NumberOfSeats,
+			pq.Array(&booking.Seats), &booking.AmountPaid, &booking.AmountRefunded, &currency, &booking.PaymentStatus)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user bookings: %w", err)
+		}
+		booking.AmountPaid.Currency = currency
+		booking.AmountRefunded.Currency = currency
+
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over user bookings: %w", err)
+	}
+
+	return bookings, nil
+}
+
+// CountUserBookings counts the bookings of a user matching the same filter as RetrieveUserBookings,
+// so callers can tell how many pages there are.
+//
+// Params:
+//   - userID (int): The ID of the user whose bookings are counted.
+//   - when (string): "upcoming" or "past" to filter on the show time, or "" for every booking.
+//
+// Returns:
+//   - int: The number of matching bookings.
+//   - error: A wrapped error if the query fails.
+func (psql *Postgres) CountUserBookings(userID int, when string) (int, error) {
+	stmt := `SELECT COUNT(*) FROM booking b JOIN show s ON b.show_id = s.show_id WHERE b.user_id = $1` + bookingTimeFilter(when)
+
+	var total int
+	if err := psql.DB.QueryRow(stmt, userID).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count user bookings: %w", err)
+	}
+
+	return total, nil
+}
+
+// RetrieveUserBooking retrieves a single booking of a user with everything shown on its detail
+// page: the history entry, the itemised lines charged at booking time, the totals, the payment
+// method and when it was cancelled.
+//
+// Params:
+//   - bookingID (int): The ID of the booking.
+//   - userID (int): The ID of the user who must own the booking.
+//
+// Returns:
+//   - BookingDetail: The booking details.
+//   - error: ErrBookingNotFound if the user has no booking with that ID, or a wrapped error.
+func (psql *Postgres) RetrieveUserBooking(bookingID, userID int) (BookingDetail, error) {
+	return psql.retrieveBookingDetail(` WHERE b.booking_id = $1 AND b.user_id = $2`, bookingID, userID)
+}
+
+// RetrieveBookingByReference retrieves any booking by the reference the customer quotes, with the same
+// details as RetrieveUserBooking. It is meant for the helpdesk, so the booking may belong to anyone.
+//
+// Params:
+//   - bookingReference (string): The booking reference in its stored form (e.g., "CG-7KQ2MX").
+//
+// Returns:
+//   - BookingDetail: The booking details.
+//   - error: ErrBookingNotFound if no booking has that reference, or a wrapped error.
+func (psql *Postgres) RetrieveBookingByReference(bookingReference string) (BookingDetail, error) {
+	return psql.retrieveBookingDetail(` WHERE b.booking_reference = $1`, bookingReference)
+}
+
+// retrieveBookingDetail retrieves the single booking matching condition, together with its itemised lines
+// and its food and drink order.
+func (psql *Postgres) retrieveBookingDetail(condition string, args ...any) (BookingDetail, error) {
+	stmt := bookingHistoryColumns + `, b.subtotal_amount, b.concessions_amount, b.fee_amount, b.discount_amount, b.total_amount, COALESCE(p.payment_method, ''), b.cancelled_at` + bookingHistoryJoins + condition + bookingHistoryGroupBy
+
+	var booking BookingDetail
+	var currency string
+
+	err := psql.DB.QueryRow(stmt, args...).Scan(&booking.BookingID, &booking.BookingStatus, &booking.BookingReference, &booking.ShowID, &booking.MovieTitle,
+		&booking.MoviePosterUrl, &booking.HallName, &booking.HallType, &booking.ShowDate, &booking.ShowStartTime, &booking.NumberOfSeats,
+		pq.Array(&booking.Seats), &booking.AmountPaid, &booking.AmountRefunded, &currency, &booking.PaymentStatus,
+		&booking.Subtotal, &booking.ConcessionsAmount, &booking.Fees, &booking.Discount, &booking.Total, &booking.PaymentMethod, &booking.CancelledAt)
 	if err != nil {
-		// Handle case where no rows are affected by the update (i.e., seat/show combination not found).
 		if errors.Is(err, sql.ErrNoRows) {
-			// Return a specific error indicating no data was found to update.
-			return ErrShowNotFound
+			return BookingDetail{}, ErrBookingNotFound
 		}
-		// Return a formatted error with more context if any other error occurs.
-		return fmt.Errorf("failed to update show seat status: %w", err)
+		return BookingDetail{}, fmt.Errorf("failed to retrieve booking: %w", err)
 	}
 
-	// Return nil if the status update was successful.
-	return nil
+	// Every amount of the booking was charged in the currency of the hall.
+	for _, amount := range []*Money{&booking.AmountPaid, &booking.AmountRefunded, &booking.Subtotal, &booking.ConcessionsAmount, &booking.Fees, &booking.Discount, &booking.Total} {
+		amount.Currency = currency
+	}
+
+	// Load the lines the customer was charged for, in the order they were quoted, with the reason for each seat's price
+	// and the ticket category it was sold as.
+	rows, err := psql.DB.Query(`SELECT bi.item_type, COALESCE(bi.show_seat_id, 0), bi.description, bi.amount, COALESCE(bi.seat_price_history_id, 0), COALESCE(sph.reason, ''), COALESCE(bi.ticket_category_id, 0), COALESCE(tc.name, '') FROM booking_item bi LEFT JOIN seat_price_history sph ON bi.seat_price_history_id = sph.seat_price_history_id LEFT JOIN ticket_category tc ON bi.ticket_category_id = tc.ticket_category_id WHERE bi.booking_id = $1 ORDER BY bi.booking_item_id`, booking.BookingID)
+	if err != nil {
+		return BookingDetail{}, fmt.Errorf("failed to retrieve booking items: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	booking.Items = []PriceQuoteItem{}
+
+	for rows.Next() {
+		var item PriceQuoteItem
+		if err := rows.Scan(&item.ItemType, &item.ShowSeatID, &item.Description, &item.Amount, &item.SeatPriceHistoryID, &item.PriceReason, &item.TicketCategoryID, &item.TicketCategory); err != nil {
+			return BookingDetail{}, fmt.Errorf("failed to scan booking items: %w", err)
+		}
+		item.Amount.Currency = currency
+
+		booking.Items = append(booking.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return BookingDetail{}, fmt.Errorf("failed to iterate over booking items: %w", err)
+	}
+
+	booking.Concessions, err = retrieveBookingConcessions(psql.DB, booking.BookingID)
+	if err != nil {
+		return BookingDetail{}, err
+	}
+	for i := range booking.Concessions {
+		booking.Concessions[i].UnitPrice.Currency = currency
+	}
+
+	return booking, nil
+}
+
+// BookingTx is a unit of work that groups every write of a single booking into one
+// database transaction. The show seats locked by LockShowSeats stay locked until
+// Commit or Rollback is called, so no other booking can take them in the meantime.
+type BookingTx interface {
+	LockShowSeats(showID int, showSeatIDs []int) ([]LockedShowSeat, error)
+	InsertNewBooking(numberOfSeats int, bookingStatus, bookingReference string, quote PriceQuote, userID int) (int, error)
+	InsertBookingItems(bookingID int, items []PriceQuoteItem) error
+	LinkShowSeatsToBooking(seatStatus string, bookingID, showID int, showSeatIDs []int) error
+	ConvertSeatHolds(userID, showID int) error
+	InsertSeatHold(userID, showID, holdMinutes int) (int, time.Time, error)
+	HoldShowSeats(holdID, showID int, showSeatIDs []int) error
+	InsertPaymentDetails(amount Money, remoteTransactionID, paymentMethod, paymentStatus string, bookingID int) error
+	LockBookingPayment(remoteTransactionID string) (BookingPayment, error)
+	LockUserBookingPayment(bookingID, userID int) (BookingPayment, error)
+	LockBookingPaymentByID(bookingID int) (BookingPayment, error)
+	UpdateBookingStatus(bookingID int, bookingStatus string) error
+	UpdatePaymentStatus(paymentID int, paymentStatus string) error
+	UpdatePaymentAuthorization(paymentID int, remoteTransactionID string) error
+	ReleaseBookingSeats(bookingID int) error
+	CancelBooking(bookingID int) error
+	InsertRefund(paymentID int, amount Money, remoteRefundID, reason string) error
+	CompleteRefund(paymentID int, remoteRefundID string) error
+	LockBookingForCheckIn(bookingID int) (BookingCheckIn, error)
+	AdmitBookingSeats(bookingID, staffID int) (int, error)
+	LockPromoCode(code string) (PromoCode, error)
+	CountUserPromoCodeUses(promoCodeID, userID int) (int, error)
+	InsertPromoCodeRedemption(promoCodeID, userID, bookingID int, discountAmount Money) error
+	LockConcessionItems(concessionItemIDs []int) ([]ConcessionItem, error)
+	DecrementConcessionStock(units map[int]int) error
+	InsertBookingConcessions(bookingID int, concessions []BookingConcession) error
+	RestockBookingConcessions(bookingID int) error
+	CollectBookingConcessions(bookingID, staffID int, bookingConcessionIDs []int) (int, error)
+	InsertReceipt(bookingID, vatRate int) error
+	ExpireWaitlistOffers(showID int) error
+	LockWaitingEntries(showID int) ([]WaitlistEntry, error)
+	OfferWaitlistEntry(waitlistEntryID, holdID int) error
+	FulfillWaitlistEntries(userID, showID int) error
+	Commit() error
+	Rollback() error
+}
+
+// postgresBookingTx is the PostgreSQL implementation of BookingTx backed by a *sql.Tx.
+type postgresBookingTx struct {
+	tx *sql.Tx
+}
+
+// BeginBookingTx starts a new database transaction for creating a booking.
+//
+// The caller must always finish the returned unit of work with either Commit or Rollback.
+// Calling Rollback after a successful Commit is harmless, so it is safe to defer it.
+//
+// Returns:
+//   - BookingTx: The unit of work bound to the new transaction.
+//   - error: An error if the transaction could not be started.
+func (psql *Postgres) BeginBookingTx() (BookingTx, error) {
+	tx, err := psql.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin booking transaction: %w", err)
+	}
+
+	return &postgresBookingTx{tx: tx}, nil
 }
 
-// InsertNewBooking creates a new booking entry in the database.
+// LockShowSeats locks the requested show seats of a show with SELECT ... FOR UPDATE and
+// returns their current status, price and position. For a seat reserved by an active, unexpired
+// hold, HoldUserID is the ID of the user holding it; otherwise it is 0. PriceHistoryID is the
+// latest change of the seat's price, which explains the price it is booked at.
 //
-// This function inserts a new booking record with the number of seats, payment status, user ID, and show ID.
+// Rows are locked in show_seat_id order so that two concurrent bookings touching the same
+// seats always acquire the locks in the same order and cannot deadlock each other.
+//
+// Params:
+//   - showID (int): The ID of the show the seats belong to.
+//   - showSeatIDs ([]int): The IDs of the show seats to lock.
+//
+// Returns:
+//   - []LockedShowSeat: The locked seats with their status and price in the currency of the hall.
+//   - error: ErrShowSeatNotFound if any of the seats does not belong to the show,
+//     or a wrapped error if the query fails.
+func (btx *postgresBookingTx) LockShowSeats(showID int, showSeatIDs []int) ([]LockedShowSeat, error) {
+	stmt := `SELECT ss.show_seat_id, cs.seat_row, cs.seat_number, cs.seat_type, ss.status, ss.price, ch.currency, sh.user_id, (SELECT MAX(sph.seat_price_history_id) FROM seat_price_history sph WHERE sph.show_seat_id = ss.show_seat_id) FROM show_seat ss JOIN cinema_seat cs ON ss.cinema_seat_id = cs.cinema_seat_id JOIN show s ON ss.show_id = s.show_id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id LEFT JOIN seat_hold sh ON ss.hold_id = sh.hold_id AND sh.status = 'Active' AND sh.expires_at > NOW() WHERE ss.show_id = $1 AND ss.show_seat_id = ANY($2) ORDER BY ss.show_seat_id FOR UPDATE OF ss`
+
+	// Execute the locking query inside the transaction.
+	rows, err := btx.tx.Query(stmt, showID, pq.Array(showSeatIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock show seats: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	var lockedSeats []LockedShowSeat
+
+	for rows.Next() {
+		var lockedSeat LockedShowSeat
+
+		// A seat may not have a price, a hold or a price history, so scan those through nullable values.
+		var price, holdUserID, priceHistoryID sql.NullInt64
+		var currency string
+		if err := rows.Scan(&lockedSeat.ShowSeatID, &lockedSeat.SeatRow, &lockedSeat.SeatNumber, &lockedSeat.SeatType,
+			&lockedSeat.SeatStatus, &price, &currency, &holdUserID, &priceHistoryID); err != nil {
+			return nil, fmt.Errorf("failed to scan locked show seat: %w", err)
+		}
+		lockedSeat.SeatPrice = NewMoney(price.Int64, currency)
+		lockedSeat.HoldUserID = int(holdUserID.Int64)
+		lockedSeat.PriceHistoryID = int(priceHistoryID.Int64)
+
+		lockedSeats = append(lockedSeats, lockedSeat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate locked show seats: %w", err)
+	}
+
+	// Every requested seat must exist and belong to the given show.
+	if len(lockedSeats) != len(showSeatIDs) {
+		return nil, ErrShowSeatNotFound
+	}
+
+	return lockedSeats, nil
+}
+
+// InsertNewBooking creates a new booking entry inside the transaction, storing the
+// totals of the price quote the customer accepted.
+//
+// A reference that is already taken does not abort the transaction; the booking is simply not
+// created and ErrBookingReferenceTaken is returned, so the caller can draw another reference.
 //
 // Params:
 //   - numberOfSeats (int): The number of seats to be booked.
-//   - paymentStatus (string): The current payment status (e.g., "Pending").
+//   - bookingStatus (string): The current status of the booking (e.g., "Pending").
+//   - bookingReference (string): The unique reference customers quote for the booking (e.g., "CG-7KQ2MX").
+//   - quote (PriceQuote): The price quote of the booking; its ShowID is the show being booked.
 //   - userID (int): The ID of the user making the booking.
-//   - showID (int): The ID of the show for which the seats are being booked.
 //
 // Returns:
-//   - bookingID (int): The ID of the newly created booking.
-//   - error: An error if the insertion fails.
-func (psql *Postgres) InsertNewBooking(numberOfSeats int, paymentStatus string, userID, showID int) (int, error) {
-	// SQL query to insert a new booking into the 'booking' table and return the booking ID.
-	stmt := `INSERT INTO booking (number_of_seats, status, user_id, show_id) VALUES ($1, $2, $3, $4) RETURNING booking_id`
+//   - int: The ID of the newly created booking.
+//   - error: ErrBookingReferenceTaken if another booking has the reference, or an error if the insertion fails.
+func (btx *postgresBookingTx) InsertNewBooking(numberOfSeats int, bookingStatus, bookingReference string, quote PriceQuote, userID int) (int, error) {
+	stmt := `INSERT INTO booking (number_of_seats, status, user_id, show_id, subtotal_amount, concessions_amount, fee_amount, discount_amount, total_amount, booking_reference) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) ON CONFLICT (booking_reference) DO NOTHING RETURNING booking_id`
 
 	var bookingID int
 	// Execute the query and retrieve the generated booking ID.
-	err := psql.DB.QueryRow(stmt, numberOfSeats, paymentStatus, userID, showID).Scan(&bookingID)
+	err := btx.tx.QueryRow(stmt, numberOfSeats, bookingStatus, userID, quote.ShowID, quote.Subtotal, quote.Concessions, quote.Fees, quote.Discount, quote.Total, bookingReference).Scan(&bookingID)
 	if err != nil {
-		// Return an error if the insertion fails.
+		// Nothing is returned when the reference collides with an existing booking.
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrBookingReferenceTaken
+		}
 		return 0, fmt.Errorf("failed to insert new booking into the database: %w", err)
 	}
 
-	// Return the generated booking ID if the insertion is successful.
 	return bookingID, nil
 }
 
-// InsertPaymentDetails inserts payment details for a specific booking into the database.
+// InsertBookingItems stores the itemised lines of a booking's price quote inside the transaction,
+// so the booking keeps the prices it was charged even if seat prices change later. Seat lines are
+// linked to the price change that set their price, so the booking can explain it, and to the ticket
+// category they were sold as.
+//
+// Params:
+//   - bookingID (int): The ID of the booking the lines belong to.
+//   - items ([]PriceQuoteItem): The quoted lines (seats and fees).
+//
+// Returns:
+//   - error: An error if any insertion fails.
+func (btx *postgresBookingTx) InsertBookingItems(bookingID int, items []PriceQuoteItem) error {
+	stmt := `INSERT INTO booking_item (booking_id, item_type, show_seat_id, description, amount, seat_price_history_id, ticket_category_id) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	for _, item := range items {
+		// Non-seat lines such as fees are not linked to any show seat, price change or ticket category.
+		showSeatID := sql.NullInt64{Int64: int64(item.ShowSeatID), Valid: item.ShowSeatID != 0}
+		seatPriceHistoryID := sql.NullInt64{Int64: int64(item.SeatPriceHistoryID), Valid: item.SeatPriceHistoryID != 0}
+		ticketCategoryID := sql.NullInt64{Int64: int64(item.TicketCategoryID), Valid: item.TicketCategoryID != 0}
+
+		_, err := btx.tx.Exec(stmt, bookingID, item.ItemType, showSeatID, item.Description, item.Amount, seatPriceHistoryID, ticketCategoryID)
+		if err != nil {
+			return fmt.Errorf("failed to insert booking item into the database: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// LinkShowSeatsToBooking sets the status of the given show seats and links them to a booking.
+// Any hold on the seats is detached, since the seats now belong to the booking.
+//
+// Params:
+//   - seatStatus (string): The new status of the seats (e.g., "Booked").
+//   - bookingID (int): The ID of the booking the seats belong to.
+//   - showID (int): The ID of the show the seats belong to.
+//   - showSeatIDs ([]int): The IDs of the show seats to update.
+//
+// Returns:
+//   - error: ErrShowSeatNotFound if not every seat was updated, or a wrapped error if the update fails.
+func (btx *postgresBookingTx) LinkShowSeatsToBooking(seatStatus string, bookingID, showID int, showSeatIDs []int) error {
+	stmt := `UPDATE show_seat SET status = $1, booking_id = $2, hold_id = NULL WHERE show_id = $3 AND show_seat_id = ANY($4)`
+
+	result, err := btx.tx.Exec(stmt, seatStatus, bookingID, showID, pq.Array(showSeatIDs))
+	if err != nil {
+		return fmt.Errorf("failed to link show seats to booking: %w", err)
+	}
+
+	// Check how many rows were affected by the update.
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	// Every requested seat must have been linked to the booking.
+	if rowsAffected != int64(len(showSeatIDs)) {
+		return ErrShowSeatNotFound
+	}
+
+	return nil
+}
+
+// ConvertSeatHolds marks the active holds of a user on a show as "Converted" once none of
+// their seats is held anymore, i.e. once every held seat has been booked.
 //
-// This function records the payment information for a booking, including the amount,
-// remote transaction ID, payment method, and the associated booking ID.
+// Params:
+//   - userID (int): The ID of the user who owns the holds.
+//   - showID (int): The ID of the show the holds belong to.
+//
+// Returns:
+//   - error: A wrapped error if the update fails.
+func (btx *postgresBookingTx) ConvertSeatHolds(userID, showID int) error {
+	stmt := `UPDATE seat_hold sh SET status = 'Converted' WHERE sh.user_id = $1 AND sh.show_id = $2 AND sh.status = 'Active' AND NOT EXISTS (SELECT 1 FROM show_seat ss WHERE ss.hold_id = sh.hold_id)`
+
+	_, err := btx.tx.Exec(stmt, userID, showID)
+	if err != nil {
+		return fmt.Errorf("failed to convert seat holds: %w", err)
+	}
+
+	return nil
+}
+
+// InsertSeatHold creates a new active seat hold that lapses after the given number of minutes.
+// The expiry is computed by the database clock, the same clock the expirer compares against.
 //
 // Params:
-//   - amount (int): The payment amount for the booking.
-//   - remoteTransactionID (int): The unique transaction ID from a remote payment gateway.
-//   - paymentMethod (string): The method used for the payment (e.g., "Credit Card", "PayPal").
-//   - bookingID (int): The ID of the booking for which the payment is being recorded.
+//   - userID (int): The ID of the user placing the hold.
+//   - showID (int): The ID of the show the seats belong to.
+//   - holdMinutes (int): How long the hold lasts, in minutes.
+//
+// Returns:
+//   - int: The ID of the new hold.
+//   - time.Time: The moment the hold expires.
+//   - error: An error if the insertion fails.
+func (btx *postgresBookingTx) InsertSeatHold(userID, showID, holdMinutes int) (int, time.Time, error) {
+	stmt := `INSERT INTO seat_hold (user_id, show_id, expires_at) VALUES ($1, $2, NOW() + make_interval(mins => $3)) RETURNING hold_id, expires_at`
+
+	var holdID int
+	var expiresAt time.Time
+	err := btx.tx.QueryRow(stmt, userID, showID, holdMinutes).Scan(&holdID, &expiresAt)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to insert new seat hold into the database: %w", err)
+	}
+
+	return holdID, expiresAt, nil
+}
+
+// HoldShowSeats marks the given show seats as "Selected" and links them to a seat hold.
+//
+// Params:
+//   - holdID (int): The ID of the hold reserving the seats.
+//   - showID (int): The ID of the show the seats belong to.
+//   - showSeatIDs ([]int): The IDs of the show seats to hold.
+//
+// Returns:
+//   - error: ErrShowSeatNotFound if not every seat was updated, or a wrapped error if the update fails.
+func (btx *postgresBookingTx) HoldShowSeats(holdID, showID int, showSeatIDs []int) error {
+	stmt := `UPDATE show_seat SET status = 'Selected', hold_id = $1 WHERE show_id = $2 AND show_seat_id = ANY($3)`
+
+	result, err := btx.tx.Exec(stmt, holdID, showID, pq.Array(showSeatIDs))
+	if err != nil {
+		return fmt.Errorf("failed to hold show seats: %w", err)
+	}
+
+	// Check how many rows were affected by the update.
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	// Every requested seat must have been held.
+	if rowsAffected != int64(len(showSeatIDs)) {
+		return ErrShowSeatNotFound
+	}
+
+	return nil
+}
+
+// InsertPaymentDetails records the payment of a booking inside the transaction.
+//
+// Params:
+//   - amount (Money): The payment amount.
+//   - remoteTransactionID (string): The transaction ID from the payment provider.
+//   - paymentMethod (string): The provider or method used for the payment (e.g., "Fake").
+//   - paymentStatus (string): The status of the payment at the provider (e.g., "Authorized").
+//   - bookingID (int): The ID of the booking the payment belongs to.
 //
 // Returns:
 //   - error: An error if the insertion fails, otherwise nil.
-func (psql *Postgres) InsertPaymentDetails(amount, remoteTransactionID int, paymentMethod string, bookingID int) error {
-	// SQL query to insert payment details into the 'payment' table.
-	stmt := `INSERT INTO payment (amount, remote_transaction_id, payment_method, booking_id) VALUES ($1, $2, $3, $4)`
+func (btx *postgresBookingTx) InsertPaymentDetails(amount Money, remoteTransactionID, paymentMethod, paymentStatus string, bookingID int) error {
+	stmt := `INSERT INTO payment (amount, remote_transaction_id, payment_method, status, booking_id) VALUES ($1, $2, $3, $4, $5)`
 
-	// Execute the query to insert the payment details.
-	_, err := psql.DB.Exec(stmt, amount, remoteTransactionID, paymentMethod, bookingID)
+	_, err := btx.tx.Exec(stmt, amount, remoteTransactionID, paymentMethod, paymentStatus, bookingID)
 	if err != nil {
-		// Return an error if the insertion fails.
 		return fmt.Errorf("failed to insert payment details into the database: %w", err)
 	}
 
-	// Return nil if the payment details are successfully inserted.
 	return nil
 }
+
+// LockBookingPayment locks a payment and its booking, looked up by the provider's transaction ID,
+// so that concurrent webhook deliveries for the same payment are processed one at a time.
+//
+// Params:
+//   - remoteTransactionID (string): The transaction ID from the payment provider.
+//
+// Returns:
+//   - BookingPayment: The booking and payment details.
+//   - error: ErrPaymentNotFound if no payment has that transaction ID, or a wrapped error.
+func (btx *postgresBookingTx) LockBookingPayment(remoteTransactionID string) (BookingPayment, error) {
+	stmt := `SELECT b.booking_id, b.show_id, b.user_id, b.status, p.payment_id, p.amount, p.status, p.remote_transaction_id, EXTRACT(EPOCH FROM (s.show_date + s.start_time) - LOCALTIMESTAMP)::INT, EXISTS (SELECT 1 FROM show_seat ss WHERE ss.booking_id = b.booking_id AND ss.admitted_at IS NOT NULL), ch.currency FROM payment p JOIN booking b ON p.booking_id = b.booking_id JOIN show s ON b.show_id = s.show_id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id WHERE p.remote_transaction_id = $1 FOR UPDATE OF b, p`
+
+	bookingPayment, err := btx.scanBookingPayment(stmt, remoteTransactionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BookingPayment{}, ErrPaymentNotFound
+		}
+		return BookingPayment{}, fmt.Errorf("failed to lock booking payment: %w", err)
+	}
+
+	return bookingPayment, nil
+}
+
+// LockUserBookingPayment locks a booking owned by the given user together with its payment.
+//
+// Params:
+//   - bookingID (int): The ID of the booking.
+//   - userID (int): The ID of the user who must own the booking.
+//
+// Returns:
+//   - BookingPayment: The booking and payment details, including the time left until the show.
+//   - error: ErrBookingNotFound if the user has no such booking, or a wrapped error.
+func (btx *postgresBookingTx) LockUserBookingPayment(bookingID, userID int) (BookingPayment, error) {
+	stmt := `SELECT b.booking_id, b.show_id, b.user_id, b.status, p.payment_id, p.amount, p.status, p.remote_transaction_id, EXTRACT(EPOCH FROM (s.show_date + s.start_time) - LOCALTIMESTAMP)::INT, EXISTS (SELECT 1 FROM show_seat ss WHERE ss.booking_id = b.booking_id AND ss.admitted_at IS NOT NULL), ch.currency FROM booking b JOIN payment p ON p.booking_id = b.booking_id JOIN show s ON b.show_id = s.show_id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id WHERE b.booking_id = $1 AND b.user_id = $2 FOR UPDATE OF b, p`
+
+	bookingPayment, err := btx.scanBookingPayment(stmt, bookingID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BookingPayment{}, ErrBookingNotFound
+		}
+		return BookingPayment{}, fmt.Errorf("failed to lock user booking payment: %w", err)
+	}
+
+	return bookingPayment, nil
+}
+
+// LockBookingPaymentByID locks a booking of any user together with its payment, for staff acting on it.
+//
+// Params:
+//   - bookingID (int): The ID of the booking.
+//
+// Returns:
+//   - BookingPayment: The booking and payment details, including the time left until the show.
+//   - error: ErrBookingNotFound if there is no such booking, or a wrapped error.
+func (btx *postgresBookingTx) LockBookingPaymentByID(bookingID int) (BookingPayment, error) {
+	stmt := `SELECT b.booking_id, b.show_id, b.user_id, b.status, p.payment_id, p.amount, p.status, p.remote_transaction_id, EXTRACT(EPOCH FROM (s.show_date + s.start_time) - LOCALTIMESTAMP)::INT, EXISTS (SELECT 1 FROM show_seat ss WHERE ss.booking_id = b.booking_id AND ss.admitted_at IS NOT NULL), ch.currency FROM booking b JOIN payment p ON p.booking_id = b.booking_id JOIN show s ON b.show_id = s.show_id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id WHERE b.booking_id = $1 FOR UPDATE OF b, p`
+
+	bookingPayment, err := btx.scanBookingPayment(stmt, bookingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BookingPayment{}, ErrBookingNotFound
+		}
+		return BookingPayment{}, fmt.Errorf("failed to lock booking payment: %w", err)
+	}
+
+	return bookingPayment, nil
+}
+
+// scanBookingPayment runs a booking payment query inside the transaction and scans its single row.
+// The amount was paid in the currency of the hall, selected last.
+func (btx *postgresBookingTx) scanBookingPayment(stmt string, args ...any) (BookingPayment, error) {
+	var bookingPayment BookingPayment
+	var currency string
+	err := btx.tx.QueryRow(stmt, args...).Scan(&bookingPayment.BookingID, &bookingPayment.ShowID, &bookingPayment.UserID,
+		&bookingPayment.BookingStatus, &bookingPayment.PaymentID, &bookingPayment.Amount, &bookingPayment.PaymentStatus,
+		&bookingPayment.RemoteTransactionID, &bookingPayment.SecondsUntilShow, &bookingPayment.Admitted, &currency)
+	if err != nil {
+		return BookingPayment{}, err
+	}
+	bookingPayment.Amount.Currency = currency
+
+	return bookingPayment, nil
+}
+
+// UpdateBookingStatus changes the status of a booking inside the transaction.
+//
+// Params:
+//   - bookingID (int): The ID of the booking to update.
+//   - bookingStatus (string): The new status (e.g., "Confirmed", "Failed").
+//
+// Returns:
+//   - error: ErrBookingNotFound if the booking does not exist, or a wrapped error.
+func (btx *postgresBookingTx) UpdateBookingStatus(bookingID int, bookingStatus string) error {
+	result, err := btx.tx.Exec(`UPDATE booking SET status = $1 WHERE booking_id = $2`, bookingStatus, bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to update booking status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrBookingNotFound
+	}
+
+	return nil
+}
+
+// UpdatePaymentStatus changes the status of a payment inside the transaction.
+//
+// Params:
+//   - paymentID (int): The ID of the payment to update.
+//   - paymentStatus (string): The new status (e.g., "Captured", "Failed").
+//
+// Returns:
+//   - error: ErrPaymentNotFound if the payment does not exist, or a wrapped error.
+func (btx *postgresBookingTx) UpdatePaymentStatus(paymentID int, paymentStatus string) error {
+	result, err := btx.tx.Exec(`UPDATE payment SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE payment_id = $2`, paymentStatus, paymentID)
+	if err != nil {
+		return fmt.Errorf("failed to update payment status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrPaymentNotFound
+	}
+
+	return nil
+}
+
+// UpdatePaymentAuthorization records the provider's transaction ID of a payment once its amount has been
+// authorized, and marks the payment as "Authorized".
+//
+// Params:
+//   - paymentID (int): The ID of the payment to update.
+//   - remoteTransactionID (string): The transaction ID returned by the payment provider.
+//
+// Returns:
+//   - error: ErrPaymentNotFound if the payment does not exist, or a wrapped error.
+func (btx *postgresBookingTx) UpdatePaymentAuthorization(paymentID int, remoteTransactionID string) error {
+	result, err := btx.tx.Exec(`UPDATE payment SET remote_transaction_id = $1, status = 'Authorized', updated_at = CURRENT_TIMESTAMP WHERE payment_id = $2`, remoteTransactionID, paymentID)
+	if err != nil {
+		return fmt.Errorf("failed to update payment authorization: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrPaymentNotFound
+	}
+
+	return nil
+}
+
+// ReleaseBookingSeats returns every seat linked to a booking to "Available" and unlinks it.
+//
+// Params:
+//   - bookingID (int): The ID of the booking whose seats are released.
+//
+// Returns:
+//   - error: A wrapped error if the update fails.
+func (btx *postgresBookingTx) ReleaseBookingSeats(bookingID int) error {
+	_, err := btx.tx.Exec(`UPDATE show_seat SET status = 'Available', booking_id = NULL, admitted_at = NULL, admitted_by = NULL WHERE booking_id = $1`, bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to release booking seats: %w", err)
+	}
+
+	return nil
+}
+
+// CancelBooking marks a booking as "Cancelled" and records when it happened.
+//
+// Params:
+//   - bookingID (int): The ID of the booking to cancel.
+//
+// Returns:
+//   - error: ErrBookingNotFound if the booking does not exist, or a wrapped error.
+func (btx *postgresBookingTx) CancelBooking(bookingID int) error {
+	result, err := btx.tx.Exec(`UPDATE booking SET status = 'Cancelled', cancelled_at = CURRENT_TIMESTAMP WHERE booking_id = $1`, bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel booking: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrBookingNotFound
+	}
+
+	return nil
+}
+
+// InsertRefund records a refund next to the payment it returns money from.
+//
+// Params:
+//   - paymentID (int): The ID of the refunded payment.
+//   - amount (Money): The refunded amount.
+//   - remoteRefundID (string): The refund ID from the payment provider, or empty while the refund is
+//     pending and the provider has not paid it out yet.
+//   - reason (string): Why the refund was issued.
+//
+// Returns:
+//   - error: A wrapped error if the insertion fails.
+func (btx *postgresBookingTx) InsertRefund(paymentID int, amount Money, remoteRefundID, reason string) error {
+	stmt := `INSERT INTO refund (payment_id, amount, remote_refund_id, reason) VALUES ($1, $2, NULLIF($3, ''), $4)`
+
+	_, err := btx.tx.Exec(stmt, paymentID, amount, remoteRefundID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to insert refund into the database: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteRefund records the provider's refund ID on the pending refund of a payment, once the
+// provider has paid it out.
+//
+// Params:
+//   - paymentID (int): The ID of the refunded payment.
+//   - remoteRefundID (string): The refund ID from the payment provider.
+//
+// Returns:
+//   - error: ErrPaymentNotFound if the payment has no pending refund, or a wrapped error.
+func (btx *postgresBookingTx) CompleteRefund(paymentID int, remoteRefundID string) error {
+	result, err := btx.tx.Exec(`UPDATE refund SET remote_refund_id = $1 WHERE payment_id = $2 AND remote_refund_id IS NULL`, remoteRefundID, paymentID)
+	if err != nil {
+		return fmt.Errorf("failed to complete refund: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrPaymentNotFound
+	}
+
+	return nil
+}
+
+// LockBookingForCheckIn locks a booking so that two simultaneous scans of the same ticket
+// cannot both admit it, and reports whether any of its seats has already been admitted.
+//
+// Params:
+//   - bookingID (int): The ID of the booking named by the ticket.
+//
+// Returns:
+//   - BookingCheckIn: The booking's show, owner, status and first admission time.
+//   - error: ErrBookingNotFound if the booking does not exist, or a wrapped error.
+func (btx *postgresBookingTx) LockBookingForCheckIn(bookingID int) (BookingCheckIn, error) {
+	stmt := `SELECT b.booking_id, b.show_id, b.user_id, b.status, (SELECT MIN(ss.admitted_at) FROM show_seat ss WHERE ss.booking_id = b.booking_id) FROM booking b WHERE b.booking_id = $1 FOR UPDATE`
+
+	var checkIn BookingCheckIn
+	err := btx.tx.QueryRow(stmt, bookingID).Scan(&checkIn.BookingID, &checkIn.ShowID, &checkIn.UserID, &checkIn.BookingStatus, &checkIn.AdmittedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BookingCheckIn{}, ErrBookingNotFound
+		}
+		return BookingCheckIn{}, fmt.Errorf("failed to lock booking for check-in: %w", err)
+	}
+
+	return checkIn, nil
+}
+
+// AdmitBookingSeats marks every not yet admitted seat of a booking as admitted by a helpdesk user.
+//
+// Params:
+//   - bookingID (int): The ID of the booking being checked in.
+//   - staffID (int): The ID of the helpdesk user scanning the ticket.
+//
+// Returns:
+//   - int: The number of seats admitted.
+//   - error: ErrShowSeatNotFound if the booking has no seats left to admit, or a wrapped error.
+func (btx *postgresBookingTx) AdmitBookingSeats(bookingID, staffID int) (int, error) {
+	result, err := btx.tx.Exec(`UPDATE show_seat SET admitted_at = CURRENT_TIMESTAMP, admitted_by = $2 WHERE booking_id = $1 AND admitted_at IS NULL`, bookingID, staffID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to admit booking seats: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return 0, ErrShowSeatNotFound
+	}
+
+	return int(rowsAffected), nil
+}
+
+// LockPromoCode locks a promo code until the transaction ends, so two bookings cannot both take
+// the last use of a capped code.
+//
+// Params:
+//   - code (string): The promo code, in upper case.
+//
+// Returns:
+//   - PromoCode: The promo code with how many times it is in use.
+//   - error: ErrPromoCodeNotFound if there is no such code, or a wrapped error.
+func (btx *postgresBookingTx) LockPromoCode(code string) (PromoCode, error) {
+	promoCode, err := scanPromoCode(btx.tx.QueryRow(promoCodeColumns+` WHERE pc.code = $1 FOR UPDATE OF pc`, code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PromoCode{}, ErrPromoCodeNotFound
+		}
+		return PromoCode{}, fmt.Errorf("failed to lock promo code: %w", err)
+	}
+
+	return promoCode, nil
+}
+
+// CountUserPromoCodeUses counts the pending and confirmed bookings of a user that used a promo code.
+//
+// Params:
+//   - promoCodeID (int): The ID of the promo code.
+//   - userID (int): The ID of the user.
+//
+// Returns:
+//   - int: How many of the user's bookings use the code.
+//   - error: A wrapped error if the query fails.
+func (btx *postgresBookingTx) CountUserPromoCodeUses(promoCodeID, userID int) (int, error) {
+	stmt := `SELECT COUNT(*) FROM promo_code_redemption r JOIN booking b ON b.booking_id = r.booking_id WHERE r.promo_code_id = $1 AND r.user_id = $2 AND b.status IN ('Pending', 'Confirmed')`
+
+	var uses int
+	if err := btx.tx.QueryRow(stmt, promoCodeID, userID).Scan(&uses); err != nil {
+		return 0, fmt.Errorf("failed to count promo code uses: %w", err)
+	}
+
+	return uses, nil
+}
+
+// InsertPromoCodeRedemption records that a booking used a promo code.
+//
+// Params:
+//   - promoCodeID (int): The ID of the promo code.
+//   - userID (int): The ID of the user who used it.
+//   - bookingID (int): The ID of the booking it was applied to.
+//   - discountAmount (Money): The discount granted.
+//
+// Returns:
+//   - error: A wrapped error if the insertion fails.
+func (btx *postgresBookingTx) InsertPromoCodeRedemption(promoCodeID, userID, bookingID int, discountAmount Money) error {
+	stmt := `INSERT INTO promo_code_redemption (promo_code_id, user_id, booking_id, discount_amount) VALUES ($1, $2, $3, $4)`
+
+	_, err := btx.tx.Exec(stmt, promoCodeID, userID, bookingID, discountAmount)
+	if err != nil {
+		return fmt.Errorf("failed to record promo code redemption: %w", err)
+	}
+
+	return nil
+}
+
+// Commit commits the booking transaction, making every write visible at once.
+func (btx *postgresBookingTx) Commit() error {
+	if err := btx.tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit booking transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Rollback aborts the booking transaction and releases the seat locks.
+// It returns nil if the transaction has already been committed or rolled back.
+func (btx *postgresBookingTx) Rollback() error {
+	if err := btx.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return fmt.Errorf("failed to roll back booking transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseSeatHold releases an active hold owned by the given user and returns its
+// still-held seats to "Available" in a single transaction.
+//
+// Params:
+//   - holdID (int): The ID of the hold to release.
+//   - userID (int): The ID of the user who owns the hold.
+//
+// Returns:
+//   - int: The ID of the show the hold was for.
+//   - error: ErrSeatHoldNotFound if the user has no active hold with that ID, or a wrapped error.
+func (psql *Postgres) ReleaseSeatHold(holdID, userID int) (int, error) {
+	tx, err := psql.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin seat hold release transaction: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	// Close the hold itself, making sure it belongs to the user and is still active.
+	var showID int
+	err = tx.QueryRow(`UPDATE seat_hold SET status = 'Released' WHERE hold_id = $1 AND user_id = $2 AND status = 'Active' RETURNING show_id`, holdID, userID).Scan(&showID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrSeatHoldNotFound
+		}
+		return 0, fmt.Errorf("failed to release seat hold: %w", err)
+	}
+
+	// Give the seats that are still held back to other customers.
+	_, err = tx.Exec(`UPDATE show_seat SET status = 'Available', hold_id = NULL WHERE hold_id = $1 AND status = 'Selected'`, holdID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release held show seats: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit seat hold release: %w", err)
+	}
+
+	return showID, nil
+}
+
+// RetrievePendingRefunds retrieves the refunds of cancelled bookings that the payment provider has
+// not paid out yet, oldest first.
+//
+// Returns:
+//   - []PendingRefund: The pending refunds; empty if there is none.
+//   - error: A wrapped error if the query fails.
+func (psql *Postgres) RetrievePendingRefunds() ([]PendingRefund, error) {
+	stmt := `SELECT p.booking_id, p.remote_transaction_id, r.amount, ch.currency FROM payment p JOIN refund r ON r.payment_id = p.payment_id AND r.remote_refund_id IS NULL JOIN booking b ON p.booking_id = b.booking_id JOIN show s ON b.show_id = s.show_id JOIN cinema_hall ch ON s.hall_id = ch.cinema_hall_id WHERE p.status = 'RefundPending' ORDER BY r.refund_id`
+
+	rows, err := psql.DB.Query(stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pending refunds: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	var pendingRefunds []PendingRefund
+	for rows.Next() {
+		var pendingRefund PendingRefund
+		var currency string
+		if err := rows.Scan(&pendingRefund.BookingID, &pendingRefund.RemoteTransactionID, &pendingRefund.Amount, &currency); err != nil {
+			return nil, fmt.Errorf("failed to scan pending refund: %w", err)
+		}
+		pendingRefund.Amount.Currency = currency
+		pendingRefunds = append(pendingRefunds, pendingRefund)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over pending refunds: %w", err)
+	}
+
+	return pendingRefunds, nil
+}
+
+// ExpireSeatHolds marks every active hold whose expiry has passed as "Expired" and returns
+// its still-held seats to "Available". Both updates run in one statement, so a hold is
+// never expired without its seats being released.
+//
+// Returns:
+//   - []int: The IDs of the shows that had seats released.
+//   - error: A wrapped error if the update fails.
+func (psql *Postgres) ExpireSeatHolds() ([]int, error) {
+	stmt := `WITH expired AS (UPDATE seat_hold SET status = 'Expired' WHERE status = 'Active' AND expires_at <= NOW() RETURNING hold_id), released AS (UPDATE show_seat SET status = 'Available', hold_id = NULL WHERE hold_id IN (SELECT hold_id FROM expired) AND status = 'Selected' RETURNING show_id) SELECT DISTINCT show_id FROM released`
+
+	rows, err := psql.DB.Query(stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire seat holds: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	var showIDs []int
+	for rows.Next() {
+		var showID int
+		if err := rows.Scan(&showID); err != nil {
+			return nil, fmt.Errorf("failed to scan expired seat hold show: %w", err)
+		}
+		showIDs = append(showIDs, showID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over expired seat hold shows: %w", err)
+	}
+
+	return showIDs, nil
+}
@@ -0,0 +1,287 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/lib/pq"
+)
+
+// querier is satisfied by both *sql.DB and *sql.Tx, so the concession queries can run
+// inside or outside a booking transaction.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+const concessionItemColumns = `SELECT concession_item_id, name, COALESCE(description, ''), category, price, stock, active FROM concession_item`
+
+// RetrieveConcessionItems retrieves the food and drink catalogue, each combo with the items it contains.
+//
+// Params:
+//   - activeOnly (bool): Whether to leave out items customers cannot order.
+//
+// Returns:
+//   - []ConcessionItem: The concession items ordered by category and name; empty if there is none.
+//   - error: A wrapped error if a query fails.
+func (psql *Postgres) RetrieveConcessionItems(activeOnly bool) ([]ConcessionItem, error) {
+	stmt := concessionItemColumns + ` WHERE active OR NOT $1 ORDER BY category, name`
+
+	return retrieveConcessionItems(psql.DB, stmt, activeOnly)
+}
+
+// retrieveConcessionItems runs a query selecting concessionItemColumns and loads the contents
+// of every combo it returns.
+func retrieveConcessionItems(q querier, stmt string, args ...any) ([]ConcessionItem, error) {
+	rows, err := q.Query(stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve concession items: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	var concessionItems []ConcessionItem
+	var comboIDs []int
+
+	for rows.Next() {
+		var concessionItem ConcessionItem
+
+		// Items with unlimited stock have no stock count.
+		var stock sql.NullInt64
+		err := rows.Scan(&concessionItem.ConcessionItemID, &concessionItem.Name, &concessionItem.Description, &concessionItem.Category,
+			&concessionItem.Price, &stock, &concessionItem.Active)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan concession item: %w", err)
+		}
+		if stock.Valid {
+			units := int(stock.Int64)
+			concessionItem.Stock = &units
+		}
+
+		if concessionItem.Category == "Combo" {
+			comboIDs = append(comboIDs, concessionItem.ConcessionItemID)
+		}
+		concessionItems = append(concessionItems, concessionItem)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over concession items: %w", err)
+	}
+
+	if len(comboIDs) == 0 {
+		return concessionItems, nil
+	}
+
+	comboItems, err := retrieveConcessionComboItems(q, comboIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range concessionItems {
+		concessionItems[i].ComboItems = comboItems[concessionItems[i].ConcessionItemID]
+	}
+
+	return concessionItems, nil
+}
+
+// retrieveConcessionComboItems loads the items contained in the given combos, keyed by combo ID.
+func retrieveConcessionComboItems(q querier, comboIDs []int) (map[int][]ConcessionComboItem, error) {
+	stmt := `SELECT cci.combo_id, cci.component_id, ci.name, cci.quantity FROM concession_combo_item cci JOIN concession_item ci ON cci.component_id = ci.concession_item_id WHERE cci.combo_id = ANY($1) ORDER BY cci.combo_id, ci.name`
+
+	rows, err := q.Query(stmt, pq.Array(comboIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve concession combo items: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	comboItems := make(map[int][]ConcessionComboItem, len(comboIDs))
+
+	for rows.Next() {
+		var comboID int
+		var comboItem ConcessionComboItem
+		if err := rows.Scan(&comboID, &comboItem.ConcessionItemID, &comboItem.Name, &comboItem.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan concession combo item: %w", err)
+		}
+		comboItems[comboID] = append(comboItems[comboID], comboItem)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over concession combo items: %w", err)
+	}
+
+	return comboItems, nil
+}
+
+// retrieveBookingConcessions loads the food and drink ordered with a booking, in the order it was ordered.
+func retrieveBookingConcessions(q querier, bookingID int) ([]BookingConcession, error) {
+	stmt := `SELECT bc.booking_concession_id, bc.concession_item_id, ci.name, bc.quantity, bc.unit_price, bc.pickup_status, bc.collected_at FROM booking_concession bc JOIN concession_item ci ON bc.concession_item_id = ci.concession_item_id WHERE bc.booking_id = $1 ORDER BY bc.booking_concession_id`
+
+	rows, err := q.Query(stmt, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve booking concessions: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	concessions := []BookingConcession{}
+
+	for rows.Next() {
+		var concession BookingConcession
+		err := rows.Scan(&concession.BookingConcessionID, &concession.ConcessionItemID, &concession.Name, &concession.Quantity,
+			&concession.UnitPrice, &concession.PickupStatus, &concession.CollectedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking concession: %w", err)
+		}
+		concessions = append(concessions, concession)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over booking concessions: %w", err)
+	}
+
+	return concessions, nil
+}
+
+// LockConcessionItems locks the ordered concession items, and the items inside any ordered combo,
+// with SELECT ... FOR UPDATE so their stock cannot be sold twice before the transaction ends.
+// Rows are locked in concession_item_id order so concurrent orders cannot deadlock each other.
+//
+// Params:
+//   - concessionItemIDs ([]int): The IDs of the ordered concession items.
+//
+// Returns:
+//   - []ConcessionItem: The ordered items and the items inside the ordered combos, with their stock.
+//   - error: ErrConcessionItemNotFound if any ordered item does not exist, or a wrapped error.
+func (btx *postgresBookingTx) LockConcessionItems(concessionItemIDs []int) ([]ConcessionItem, error) {
+	stmt := concessionItemColumns + ` WHERE concession_item_id = ANY($1) OR concession_item_id IN (SELECT component_id FROM concession_combo_item WHERE combo_id = ANY($1)) ORDER BY concession_item_id FOR UPDATE`
+
+	concessionItems, err := retrieveConcessionItems(btx.tx, stmt, pq.Array(concessionItemIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock concession items: %w", err)
+	}
+
+	found := make(map[int]bool, len(concessionItems))
+	for _, concessionItem := range concessionItems {
+		found[concessionItem.ConcessionItemID] = true
+	}
+
+	for _, concessionItemID := range concessionItemIDs {
+		if !found[concessionItemID] {
+			return nil, ErrConcessionItemNotFound
+		}
+	}
+
+	return concessionItems, nil
+}
+
+// DecrementConcessionStock takes sold units off the stock of concession items.
+// Only items with a stock count may be passed; items with unlimited stock are never decremented.
+//
+// Params:
+//   - units (map[int]int): The number of units sold of each concession item ID.
+//
+// Returns:
+//   - error: ErrConcessionOutOfStock if an item has fewer units left than sold, or a wrapped error.
+func (btx *postgresBookingTx) DecrementConcessionStock(units map[int]int) error {
+	stmt := `UPDATE concession_item SET stock = stock - $2, updated_at = CURRENT_TIMESTAMP WHERE concession_item_id = $1 AND stock >= $2`
+
+	// Update in a fixed order so concurrent orders take the row locks in the same order.
+	concessionItemIDs := make([]int, 0, len(units))
+	for concessionItemID := range units {
+		concessionItemIDs = append(concessionItemIDs, concessionItemID)
+	}
+	sort.Ints(concessionItemIDs)
+
+	for _, concessionItemID := range concessionItemIDs {
+		result, err := btx.tx.Exec(stmt, concessionItemID, units[concessionItemID])
+		if err != nil {
+			return fmt.Errorf("failed to decrement concession stock: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to check affected rows: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return ErrConcessionOutOfStock
+		}
+	}
+
+	return nil
+}
+
+// InsertBookingConcessions stores the food and drink ordered with a booking, at the price it was sold for.
+// Every line starts "Pending" until the customer collects it.
+//
+// Params:
+//   - bookingID (int): The ID of the booking the order belongs to.
+//   - concessions ([]BookingConcession): The ordered items with their quantity and unit price.
+//
+// Returns:
+//   - error: A wrapped error if any insertion fails.
+func (btx *postgresBookingTx) InsertBookingConcessions(bookingID int, concessions []BookingConcession) error {
+	stmt := `INSERT INTO booking_concession (booking_id, concession_item_id, quantity, unit_price) VALUES ($1, $2, $3, $4)`
+
+	for _, concession := range concessions {
+		_, err := btx.tx.Exec(stmt, bookingID, concession.ConcessionItemID, concession.Quantity, concession.UnitPrice)
+		if err != nil {
+			return fmt.Errorf("failed to insert booking concession into the database: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RestockBookingConcessions cancels the food and drink of a booking that has not been collected yet
+// and puts it back in stock. A cancelled combo restocks the items it contains.
+//
+// Params:
+//   - bookingID (int): The ID of the booking whose order is cancelled.
+//
+// Returns:
+//   - error: A wrapped error if the update fails.
+func (btx *postgresBookingTx) RestockBookingConcessions(bookingID int) error {
+	stmt := `WITH cancelled AS (UPDATE booking_concession SET pickup_status = 'Cancelled' WHERE booking_id = $1 AND pickup_status = 'Pending' RETURNING concession_item_id, quantity), units AS (SELECT concession_item_id, quantity FROM cancelled UNION ALL SELECT cci.component_id, c.quantity * cci.quantity FROM cancelled c JOIN concession_combo_item cci ON cci.combo_id = c.concession_item_id) UPDATE concession_item ci SET stock = ci.stock + u.quantity, updated_at = CURRENT_TIMESTAMP FROM (SELECT concession_item_id, SUM(quantity) AS quantity FROM units GROUP BY concession_item_id) u WHERE ci.concession_item_id = u.concession_item_id AND ci.stock IS NOT NULL`
+
+	_, err := btx.tx.Exec(stmt, bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to restock booking concessions: %w", err)
+	}
+
+	return nil
+}
+
+// CollectBookingConcessions marks ordered food and drink of a booking as handed over by a helpdesk user.
+//
+// Params:
+//   - bookingID (int): The ID of the booking the order belongs to.
+//   - staffID (int): The ID of the helpdesk user handing the order over.
+//   - bookingConcessionIDs ([]int): The ordered lines being collected; empty to collect every pending line.
+//
+// Returns:
+//   - int: The number of lines marked as collected; 0 if none was pending.
+//   - error: A wrapped error if the update fails.
+func (btx *postgresBookingTx) CollectBookingConcessions(bookingID, staffID int, bookingConcessionIDs []int) (int, error) {
+	stmt := `UPDATE booking_concession SET pickup_status = 'Collected', collected_at = CURRENT_TIMESTAMP, collected_by = $2 WHERE booking_id = $1 AND pickup_status = 'Pending' AND (CARDINALITY($3::INT[]) = 0 OR booking_concession_id = ANY($3))`
+
+	if bookingConcessionIDs == nil {
+		bookingConcessionIDs = []int{}
+	}
+
+	result, err := btx.tx.Exec(stmt, bookingID, staffID, pq.Array(bookingConcessionIDs))
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect booking concessions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
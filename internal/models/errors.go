@@ -11,6 +11,19 @@ var ErrUserNotFound = errors.New("models: user not found")
 var ErrShowNotFound = errors.New("models: show not found by given id")
 var ErrStartTimeNotFound = errors.New("models: start time not found")
 var ErrShowSeatNotFound = errors.New("models: show seat not found")
+var ErrSeatHoldNotFound = errors.New("models: seat hold not found")
+var ErrPaymentNotFound = errors.New("models: payment not found")
+var ErrBookingNotFound = errors.New("models: booking not found")
+var ErrBookingReferenceTaken = errors.New("models: booking reference already taken")
+var ErrReceiptNotFound = errors.New("models: receipt not found")
+var ErrIdempotencyKeyNotFound = errors.New("models: idempotency key not found")
+var ErrPromoCodeNotFound = errors.New("models: promo code not found")
+var ErrConcessionItemNotFound = errors.New("models: concession item not found")
+var ErrConcessionOutOfStock = errors.New("models: concession item out of stock")
+var ErrInvalidMoney = errors.New("models: invalid amount of money")
+var ErrExchangeRateNotFound = errors.New("models: exchange rate not found")
+var ErrWaitlistEntryNotFound = errors.New("models: waitlist entry not found")
+var ErrAlreadyWaitlisted = errors.New("models: already on the waitlist of the show")
 
 var ErrAdminPageCarouselImagesNotFound = errors.New("models: Admin Page, Carousel Images Not Found")
 var ErrAdminPageMovieNotFound = errors.New("models: Admin Page, Movie Not Found")
@@ -19,4 +32,16 @@ var ErrDuplicatedCinemaHall = errors.New("models: Admin page, cinema hall with t
 var ErrCinemaHallNotFound = errors.New("models: Admin page, cinema hall not found")
 var ErrCinemaSeatNotFound = errors.New("models: Admin page, cinema seat not found")
 var ErrCinemaSeatAlreadyExists = errors.New("models: Admin page, cinema seat with hall_id, seat_row, seat_number already exists")
-var ErrShowAlreadyExists = errors.New("models: Admin page, a show already exists at the given hall, date, and time")
\ No newline at end of file
+var ErrCinemaHallCapacityExceeded = errors.New("models: Admin page, cinema hall has more seats than its capacity")
+var ErrCinemaHallNotEmpty = errors.New("models: Admin page, cinema hall already has seats")
+var ErrHallLayoutNotFound = errors.New("models: hall layout not found")
+var ErrHallLayoutIncomplete = errors.New("models: Admin page, hall layout leaves existing seats without a place")
+var ErrShowAlreadyExists = errors.New("models: Admin page, a show already exists at the given hall, date, and time")
+var ErrPromoCodeAlreadyExists = errors.New("models: Admin page, a promo code with this code already exists")
+var ErrPriceRuleNotFound = errors.New("models: Admin page, price rule not found")
+var ErrTicketCategoryNotFound = errors.New("models: ticket category not found")
+var ErrTicketCategoryAlreadyExists = errors.New("models: Admin page, a ticket category with this name already exists")
+var ErrConcessionItemAlreadyExists = errors.New("models: Admin page, a concession item with this name already exists")
+var ErrConcessionItemInUse = errors.New("models: Admin page, concession item is part of a combo or an order")
+var ErrShowHasPaidBookings = errors.New("models: Admin page, show has paid bookings")
+var ErrMovieHasPaidBookings = errors.New("models: Admin page, movie has shows with paid bookings")
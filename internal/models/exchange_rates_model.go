@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RetrieveExchangeRates retrieves every exchange rate maintained by the admins.
+//
+// Returns:
+//   - []ExchangeRate: The rates, by currency code; empty if there is none. Each rate is the number of
+//     units of DefaultCurrency one unit of the currency is worth, as a decimal string (e.g., "12650.5").
+//   - error: A wrapped error if the query fails.
+func (psql *Postgres) RetrieveExchangeRates() ([]ExchangeRate, error) {
+	stmt := `SELECT currency, rate::TEXT, updated_at FROM exchange_rate ORDER BY currency`
+
+	rows, err := psql.DB.Query(stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve exchange rates: %w", err)
+	}
+
+	// Ensure the rows are closed after the function finishes
+	defer rows.Close()
+
+	exchangeRates := []ExchangeRate{}
+
+	for rows.Next() {
+		var exchangeRate ExchangeRate
+		if err := rows.Scan(&exchangeRate.Currency, &exchangeRate.Rate, &exchangeRate.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate: %w", err)
+		}
+		exchangeRate.Rate = trimDecimal(exchangeRate.Rate)
+
+		exchangeRates = append(exchangeRates, exchangeRate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over exchange rates: %w", err)
+	}
+
+	return exchangeRates, nil
+}
+
+// UpsertExchangeRate sets the exchange rate of a currency, adding it if it has none yet.
+//
+// Parameters:
+//   - currency (string): The ISO 4217 code of the currency.
+//   - rate (string): The units of DefaultCurrency one unit of the currency is worth, as a decimal string.
+//
+// Returns:
+//   - error: A wrapped error if the query fails.
+func (psql *Postgres) UpsertExchangeRate(currency, rate string) error {
+	stmt := `INSERT INTO exchange_rate (currency, rate) VALUES ($1, $2) ON CONFLICT (currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = CURRENT_TIMESTAMP`
+
+	if _, err := psql.DB.Exec(stmt, currency, rate); err != nil {
+		return fmt.Errorf("failed to save exchange rate: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExchangeRate removes the exchange rate of a currency.
+//
+// Parameters:
+//   - currency (string): The ISO 4217 code of the currency.
+//
+// Returns:
+//   - error: ErrExchangeRateNotFound if the currency has no rate, or a wrapped error if the query fails.
+func (psql *Postgres) DeleteExchangeRate(currency string) error {
+	result, err := psql.DB.Exec(`DELETE FROM exchange_rate WHERE currency = $1`, currency)
+	if err != nil {
+		return fmt.Errorf("failed to delete exchange rate: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrExchangeRateNotFound
+	}
+
+	return nil
+}
+
+// trimDecimal drops the trailing zeros PostgreSQL pads NUMERIC values with, so "12650.5000000000" reads "12650.5".
+func trimDecimal(value string) string {
+	if !strings.Contains(value, ".") {
+		return value
+	}
+
+	return strings.TrimSuffix(strings.TrimRight(value, "0"), ".")
+}
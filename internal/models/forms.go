@@ -78,6 +78,7 @@ type ShowMovieInfo struct {
 type ShowInfo struct {
 	HallName       string
 	HallType       string
+	Currency       string
 	ShowDate       string
 	ShowStartTimes []ShowStartTime
 }
@@ -88,12 +89,180 @@ type ShowStartTime struct {
 }
 
 type ShowSeat struct {
-	ShowSeatID int
-	SeatRow    string
-	SeatNumber int
-	SeatType   string
-	SeatStatus string
-	SeatPrice  int
+	ShowSeatID   int
+	SeatRow      string
+	SeatNumber   int
+	SeatType     string
+	SeatStatus   string
+	SeatPrice    Money
+	DisplayPrice Money
+	GridX        int // Column of the seat on the hall layout, counted from 1; 0 if the hall has no layout.
+	GridY        int // Row of the seat on the hall layout, counted from 1; 0 if the hall has no layout.
+	Angle        int // Rotation of the seat in degrees, for curved rows.
+}
+
+type ShowSeatsSummary struct {
+	Available int
+	Held      int
+	Booked    int
+}
+
+type LockedShowSeat struct {
+	ShowSeat
+	HoldUserID     int
+	PriceHistoryID int
+}
+
+type PriceQuote struct {
+	ShowID      int
+	Items       []PriceQuoteItem
+	Subtotal    Money
+	Concessions Money
+	Fees        Money
+	Discount    Money
+	Total       Money
+	PromoCode   string
+}
+
+type PriceQuoteItem struct {
+	ItemType           string
+	ShowSeatID         int
+	Description        string
+	Amount             Money
+	SeatPriceHistoryID int
+	PriceReason        string
+	TicketCategoryID   int
+	TicketCategory     string
+}
+
+type SeatHold struct {
+	HoldID      int
+	ShowID      int
+	ShowSeatsID []int
+	ExpiresAt   time.Time
+}
+
+type WaitlistEntry struct {
+	WaitlistEntryID int
+	ShowID          int
+	UserID          int
+	NumberOfSeats   int
+	Status          string // "Waiting", "Offered", "Fulfilled", "Expired" or "Cancelled"
+	Position        int    // Place in the queue while waiting, starting at 1; 0 otherwise
+	HoldID          int
+	ShowSeatsID     []int      // Seats held for the customer while the offer lasts
+	HoldExpiresAt   *time.Time // When the offer lapses
+	CreatedAt       time.Time
+	OfferedAt       *time.Time
+}
+
+type WaitlistOffer struct {
+	WaitlistEntryID int
+	UserID          int
+	SeatHold        SeatHold
+}
+
+type CreatedBooking struct {
+	BookingID        int
+	BookingReference string
+	BookingStatus    string
+	Quote            PriceQuote
+}
+
+type BookingPayment struct {
+	BookingID           int
+	ShowID              int
+	UserID              int
+	BookingStatus       string
+	PaymentID           int
+	Amount              Money
+	PaymentStatus       string
+	RemoteTransactionID string
+	SecondsUntilShow    int
+	Admitted            bool
+}
+
+type PendingRefund struct {
+	BookingID           int
+	RemoteTransactionID string
+	Amount              Money // The amount to refund, not the amount paid
+}
+
+type CancelledBooking struct {
+	BookingID     int
+	RefundPercent int
+	RefundAmount  Money
+}
+
+type BookingHistoryEntry struct {
+	BookingID        int
+	BookingReference string
+	BookingStatus    string
+	ShowID           int
+	MovieTitle       string
+	MoviePosterUrl   string
+	HallName         string
+	HallType         string
+	ShowDate         string
+	ShowStartTime    string
+	NumberOfSeats    int
+	Seats            []string
+	AmountPaid       Money
+	AmountRefunded   Money
+	PaymentStatus    string
+}
+
+type BookingHistoryPage struct {
+	Bookings      []BookingHistoryEntry
+	Page          int
+	PageSize      int
+	TotalBookings int
+}
+
+type BookingDetail struct {
+	BookingHistoryEntry
+	Items             []PriceQuoteItem
+	Concessions       []BookingConcession
+	Subtotal          Money
+	ConcessionsAmount Money
+	Fees              Money
+	Discount          Money
+	Total             Money
+	PaymentMethod     string
+	CancelledAt       *time.Time
+}
+
+type Ticket struct {
+	BookingID        int
+	BookingReference string
+	Token            string
+	MovieTitle       string
+	HallName         string
+	ShowDate         string
+	ShowStartTime    string
+	Seats            []string
+	Concessions      []BookingConcession
+}
+
+type BookingCheckIn struct {
+	BookingID     int
+	ShowID        int
+	UserID        int
+	BookingStatus string
+	AdmittedAt    *time.Time
+}
+
+type CheckedInBooking struct {
+	BookingHistoryEntry
+	SeatsAdmitted int
+	Concessions   []BookingConcession
+}
+
+type IdempotentResponse struct {
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	ClaimToken   string // Set when the request claimed the key and must store its response with it
 }
 
 type ShowSeatsMovieInfo struct {
@@ -160,6 +329,7 @@ type CinemaHallForAdmin struct {
 	HallName     string
 	HallType     string
 	Capacity     int
+	Currency     string
 }
 
 type CinemaSeatForAdmin struct {
@@ -170,6 +340,54 @@ type CinemaSeatForAdmin struct {
 	HallID       int
 }
 
+type HallLayout struct {
+	HallID         int
+	Columns        int
+	Rows           int
+	ScreenPosition string // "Top" or "Bottom".
+	Markers        []HallLayoutMarker
+	Seats          []HallLayoutSeat
+}
+
+type HallSeatSpec struct {
+	HallID          int
+	RowFrom         string   // First row letter, e.g., "A".
+	RowTo           string   // Last row letter, e.g., "M".
+	SeatFrom        int      // First seat number of every row.
+	SeatTo          int      // Last seat number of every row.
+	VIPRows         []string // Rows whose seats are "VIP".
+	AccessibleSeats []string // Seats that are "Accessible", e.g., "M1".
+}
+
+type HallLayoutMarker struct {
+	MarkerType string // "Aisle", "Stairs" or "Exit".
+	GridX      int
+	GridY      int
+	Width      int
+	Height     int
+}
+
+type HallLayoutSeat struct {
+	SeatRow    string
+	SeatNumber int
+	SeatType   string
+	GridX      int
+	GridY      int
+	Angle      int
+}
+
+type SeatGrid struct {
+	Columns        int
+	Rows           int
+	ScreenPosition string
+	Cells          [][]SeatGridCell // Cells[y][x], the top-left cell first.
+}
+
+type SeatGridCell struct {
+	Kind string    // "Seat", "Aisle", "Stairs", "Exit" or "Empty".
+	Seat *ShowSeat // The seat drawn in the cell, if Kind is "Seat".
+}
+
 type ShowForAdmin struct {
 	ShowID    int
 	ShowDate  string
@@ -182,6 +400,130 @@ type ShowSeatForAdmin struct {
 	ShowSeatID   int
 	CinemaSeatID int
 	SeatStatus   string
-	SeatPrice    float32
+	SeatPrice    Money
 	ShowID       int
 }
+
+type PromoCode struct {
+	PromoCodeID     int
+	Code            string
+	Description     string
+	DiscountType    string
+	DiscountPercent int
+	DiscountAmount  Money
+	ValidFrom       time.Time
+	ValidUntil      time.Time
+	MaxUses         int
+	MaxUsesPerUser  int
+	MovieID         int
+	HallType        string
+	SeatType        string
+	TimesUsed       int
+}
+
+type ShowMovieHall struct {
+	ShowID        int
+	MovieID       int
+	HallType      string
+	Currency      string
+	MovieAgeLimit string
+}
+
+type PriceRule struct {
+	PriceRuleID    int
+	RuleName       string
+	HallType       string
+	SeatType       string
+	DayOfWeek      *int
+	StartTimeFrom  string
+	StartTimeUntil string
+	Price          Money
+}
+
+type SeatPriceChange struct {
+	SeatPriceHistoryID int
+	ShowSeatID         int
+	OldPrice           Money
+	NewPrice           Money
+	Reason             string
+	ChangedAt          time.Time
+}
+
+type DynamicPricingShow struct {
+	ShowID           int
+	MinPricePercent  int
+	MaxPricePercent  int
+	TotalSeats       int
+	BookedSeats      int
+	SecondsUntilShow int
+}
+
+type TicketCategory struct {
+	TicketCategoryID int
+	Name             string
+	PricePercent     int
+	MinAge           int
+	MaxAge           int
+}
+
+type ConcessionItem struct {
+	ConcessionItemID int
+	Name             string
+	Description      string
+	Category         string
+	Price            Money
+	Stock            *int
+	Active           bool
+	ComboItems       []ConcessionComboItem
+}
+
+type ConcessionComboItem struct {
+	ConcessionItemID int
+	Name             string
+	Quantity         int
+}
+
+type BookingConcession struct {
+	BookingConcessionID int
+	ConcessionItemID    int
+	Name                string
+	Quantity            int
+	UnitPrice           Money
+	PickupStatus        string
+	CollectedAt         *time.Time
+}
+
+type ReceiptRecord struct {
+	ReceiptNumber int64
+	BookingID     int
+	VATRate       int
+	IssuedAt      time.Time
+}
+
+type Receipt struct {
+	ReceiptNumber    string
+	IssuedAt         time.Time
+	BookingID        int
+	BookingReference string
+	MovieTitle       string
+	HallName         string
+	ShowDate         string
+	ShowStartTime    string
+	PaymentMethod    string
+	Items            []PriceQuoteItem
+	Subtotal         Money
+	Concessions      Money
+	Fees             Money
+	Discount         Money
+	Total            Money
+	VATRate          int
+	NetAmount        Money
+	VATAmount        Money
+	AmountRefunded   Money
+}
+
+type ExchangeRate struct {
+	Currency  string
+	Rate      string
+	UpdatedAt time.Time
+}
@@ -0,0 +1,344 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ReplaceHallLayout uploads the seat map of a hall in a single transaction: the grid, the screen position
+// and the aisle, stairs and exit markers replace the previous layout, and every seat of the layout is created,
+// or moved and retyped if the hall already has it. Show seats of existing seats are kept.
+//
+// Parameters:
+//   - hallLayout (HallLayout): The layout, identified by HallID.
+//
+// Returns:
+//   - int: The number of seats created; the other seats of the layout already existed.
+//   - error: ErrCinemaHallNotFound if there is no such hall, ErrHallLayoutIncomplete if an existing seat of
+//     the hall is missing from the layout, ErrCinemaHallCapacityExceeded if the hall would have more seats
+//     than its capacity, or a wrapped error if the upload fails.
+func (psql *Postgres) ReplaceHallLayout(hallLayout HallLayout) (int, error) {
+	tx, err := psql.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin hall layout transaction: %w", err)
+	}
+
+	// Rolling back after a successful commit is a no-op.
+	defer tx.Rollback()
+
+	capacity, err := lockCinemaHall(tx, hallLayout.HallID)
+	if err != nil {
+		return 0, err
+	}
+
+	created, err := saveHallLayout(tx, hallLayout)
+	if err != nil {
+		return 0, err
+	}
+
+	// Seats the layout does not place would be missing from the seat map.
+	var unplaced, total int
+	err = tx.QueryRow(`SELECT COUNT(*) FILTER (WHERE grid_x IS NULL OR grid_y IS NULL), COUNT(*) FROM cinema_seat WHERE hall_id = $1`, hallLayout.HallID).Scan(&unplaced, &total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count cinema seats for hall_id %d: %w", hallLayout.HallID, err)
+	}
+
+	if unplaced > 0 {
+		return 0, ErrHallLayoutIncomplete
+	}
+
+	if total > capacity {
+		return 0, ErrCinemaHallCapacityExceeded
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit hall layout transaction: %w", err)
+	}
+
+	return created, nil
+}
+
+// RetrieveHallLayout retrieves the seat map of a hall, with every seat and its place on the grid.
+//
+// Parameters:
+//   - hallID (int): The ID of the cinema hall.
+//
+// Returns:
+//   - HallLayout: The layout; seats are ordered by row and seat number.
+//   - error: ErrHallLayoutNotFound if no layout has been uploaded for the hall, or a wrapped error.
+func (psql *Postgres) RetrieveHallLayout(hallID int) (HallLayout, error) {
+	hallLayout, err := psql.retrieveHallLayout(`SELECT hall_id, grid_columns, grid_rows, screen_position FROM hall_layout WHERE hall_id = $1`, hallID)
+	if err != nil {
+		return HallLayout{}, err
+	}
+
+	stmt := `SELECT seat_row, seat_number, seat_type, COALESCE(grid_x, 0), COALESCE(grid_y, 0), angle FROM cinema_seat WHERE hall_id = $1 ORDER BY seat_row, seat_number`
+
+	rows, err := psql.DB.Query(stmt, hallID)
+	if err != nil {
+		return HallLayout{}, fmt.Errorf("failed to retrieve hall layout seats: %w", err)
+	}
+
+	// Ensure the rows are closed after processing
+	defer rows.Close()
+
+	hallLayout.Seats = []HallLayoutSeat{}
+
+	for rows.Next() {
+		var seat HallLayoutSeat
+		if err := rows.Scan(&seat.SeatRow, &seat.SeatNumber, &seat.SeatType, &seat.GridX, &seat.GridY, &seat.Angle); err != nil {
+			return HallLayout{}, fmt.Errorf("failed to scan hall layout seat: %w", err)
+		}
+
+		hallLayout.Seats = append(hallLayout.Seats, seat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return HallLayout{}, fmt.Errorf("failed to iterate over hall layout seats: %w", err)
+	}
+
+	return hallLayout, nil
+}
+
+// RetrieveShowHallLayout retrieves the seat map of the hall a show runs in, without its seats; the
+// seats of the show carry their own place on the grid.
+//
+// Parameters:
+//   - showID (int): The ID of the show.
+//
+// Returns:
+//   - HallLayout: The grid, the screen position and the markers of the hall.
+//   - error: ErrHallLayoutNotFound if the show does not exist or its hall has no layout, or a wrapped error.
+func (psql *Postgres) RetrieveShowHallLayout(showID int) (HallLayout, error) {
+	return psql.retrieveHallLayout(`SELECT hl.hall_id, hl.grid_columns, hl.grid_rows, hl.screen_position FROM hall_layout hl JOIN show s ON s.hall_id = hl.hall_id WHERE s.show_id = $1`, showID)
+}
+
+// retrieveHallLayout retrieves the layout selected by stmt, and its markers.
+func (psql *Postgres) retrieveHallLayout(stmt string, id int) (HallLayout, error) {
+	var hallLayout HallLayout
+
+	err := psql.DB.QueryRow(stmt, id).Scan(&hallLayout.HallID, &hallLayout.Columns, &hallLayout.Rows, &hallLayout.ScreenPosition)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return HallLayout{}, ErrHallLayoutNotFound
+		}
+		return HallLayout{}, fmt.Errorf("failed to retrieve hall layout: %w", err)
+	}
+
+	rows, err := psql.DB.Query(`SELECT marker_type, grid_x, grid_y, width, height FROM hall_layout_marker WHERE hall_id = $1 ORDER BY grid_y, grid_x`, hallLayout.HallID)
+	if err != nil {
+		return HallLayout{}, fmt.Errorf("failed to retrieve hall layout markers: %w", err)
+	}
+
+	// Ensure the rows are closed after processing
+	defer rows.Close()
+
+	hallLayout.Markers = []HallLayoutMarker{}
+
+	for rows.Next() {
+		var marker HallLayoutMarker
+		if err := rows.Scan(&marker.MarkerType, &marker.GridX, &marker.GridY, &marker.Width, &marker.Height); err != nil {
+			return HallLayout{}, fmt.Errorf("failed to scan hall layout marker: %w", err)
+		}
+
+		hallLayout.Markers = append(hallLayout.Markers, marker)
+	}
+
+	if err := rows.Err(); err != nil {
+		return HallLayout{}, fmt.Errorf("failed to iterate over hall layout markers: %w", err)
+	}
+
+	return hallLayout, nil
+}
+
+// InsertHallSeats creates every seat of an empty hall in a single transaction, together with the layout
+// they are placed on.
+//
+// Parameters:
+//   - hallLayout (HallLayout): The layout and seats, identified by HallID.
+//
+// Returns:
+//   - error: ErrCinemaHallNotFound if there is no such hall, ErrCinemaHallNotEmpty if it already has seats,
+//     ErrCinemaHallCapacityExceeded if there are more seats than its capacity, or a wrapped error.
+func (psql *Postgres) InsertHallSeats(hallLayout HallLayout) error {
+	tx, err := psql.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin hall seats transaction: %w", err)
+	}
+
+	// Rolling back after a successful commit is a no-op.
+	defer tx.Rollback()
+
+	capacity, err := lockCinemaHall(tx, hallLayout.HallID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkHallEmpty(tx, hallLayout.HallID); err != nil {
+		return err
+	}
+
+	if len(hallLayout.Seats) > capacity {
+		return ErrCinemaHallCapacityExceeded
+	}
+
+	if _, err := saveHallLayout(tx, hallLayout); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit hall seats transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CloneHallSeats copies every seat of a hall, and its layout if it has one, into an empty hall in a single
+// transaction. Only the seats are copied: shows, prices and bookings stay with the original hall.
+//
+// Parameters:
+//   - fromHallID (int): The ID of the hall to copy.
+//   - toHallID (int): The ID of the empty hall to fill.
+//
+// Returns:
+//   - int: The number of seats created.
+//   - error: ErrCinemaHallNotFound if either hall does not exist, ErrCinemaSeatNotFound if the hall to copy
+//     has no seats, ErrCinemaHallNotEmpty if the other hall already has seats, ErrCinemaHallCapacityExceeded
+//     if the copied seats do not fit its capacity, or a wrapped error.
+func (psql *Postgres) CloneHallSeats(fromHallID, toHallID int) (int, error) {
+	tx, err := psql.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin hall clone transaction: %w", err)
+	}
+
+	// Rolling back after a successful commit is a no-op.
+	defer tx.Rollback()
+
+	capacity, err := lockCinemaHall(tx, toHallID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := checkHallEmpty(tx, toHallID); err != nil {
+		return 0, err
+	}
+
+	var fromHallExists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM cinema_hall WHERE cinema_hall_id = $1)`, fromHallID).Scan(&fromHallExists); err != nil {
+		return 0, fmt.Errorf("failed to check cinema hall: %w", err)
+	}
+	if !fromHallExists {
+		return 0, ErrCinemaHallNotFound
+	}
+
+	// An empty hall may still have the layout of seats deleted since; the copied layout replaces it.
+	if _, err := tx.Exec(`DELETE FROM hall_layout WHERE hall_id = $1`, toHallID); err != nil {
+		return 0, fmt.Errorf("failed to delete hall layout: %w", err)
+	}
+
+	stmt := `INSERT INTO hall_layout (hall_id, grid_columns, grid_rows, screen_position) SELECT $2, grid_columns, grid_rows, screen_position FROM hall_layout WHERE hall_id = $1`
+	if _, err := tx.Exec(stmt, fromHallID, toHallID); err != nil {
+		return 0, fmt.Errorf("failed to copy hall layout: %w", err)
+	}
+
+	// Markers only exist for halls with a layout, so there is a layout to attach them to.
+	stmt = `INSERT INTO hall_layout_marker (hall_id, marker_type, grid_x, grid_y, width, height) SELECT $2, marker_type, grid_x, grid_y, width, height FROM hall_layout_marker WHERE hall_id = $1`
+	if _, err := tx.Exec(stmt, fromHallID, toHallID); err != nil {
+		return 0, fmt.Errorf("failed to copy hall layout markers: %w", err)
+	}
+
+	stmt = `INSERT INTO cinema_seat (seat_row, seat_number, seat_type, hall_id, grid_x, grid_y, angle) SELECT seat_row, seat_number, seat_type, $2, grid_x, grid_y, angle FROM cinema_seat WHERE hall_id = $1`
+	result, err := tx.Exec(stmt, fromHallID, toHallID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy cinema seats: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return 0, ErrCinemaSeatNotFound
+	}
+
+	if int(rowsAffected) > capacity {
+		return 0, ErrCinemaHallCapacityExceeded
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit hall clone transaction: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// lockCinemaHall locks a hall while its seats change, so concurrent changes cannot each stay within its
+// capacity and exceed it together, and returns its capacity.
+func lockCinemaHall(tx *sql.Tx, hallID int) (int, error) {
+	var capacity int
+
+	err := tx.QueryRow(`SELECT capacity FROM cinema_hall WHERE cinema_hall_id = $1 FOR UPDATE`, hallID).Scan(&capacity)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrCinemaHallNotFound
+		}
+		return 0, fmt.Errorf("failed to lock cinema hall: %w", err)
+	}
+
+	return capacity, nil
+}
+
+// checkHallEmpty returns ErrCinemaHallNotEmpty if a hall already has seats.
+func checkHallEmpty(tx *sql.Tx, hallID int) error {
+	var hasSeats bool
+
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM cinema_seat WHERE hall_id = $1)`, hallID).Scan(&hasSeats); err != nil {
+		return fmt.Errorf("failed to check cinema seats for hall_id %d: %w", hallID, err)
+	}
+
+	if hasSeats {
+		return ErrCinemaHallNotEmpty
+	}
+
+	return nil
+}
+
+// saveHallLayout writes a layout within tx: the grid, the screen position and the markers replace the
+// previous ones, and every seat is created, or moved and retyped if the hall already has it. It returns
+// the number of seats created.
+func saveHallLayout(tx *sql.Tx, hallLayout HallLayout) (int, error) {
+	stmt := `INSERT INTO hall_layout (hall_id, grid_columns, grid_rows, screen_position) VALUES ($1, $2, $3, $4) ON CONFLICT (hall_id) DO UPDATE SET grid_columns = EXCLUDED.grid_columns, grid_rows = EXCLUDED.grid_rows, screen_position = EXCLUDED.screen_position, updated_at = CURRENT_TIMESTAMP`
+	if _, err := tx.Exec(stmt, hallLayout.HallID, hallLayout.Columns, hallLayout.Rows, hallLayout.ScreenPosition); err != nil {
+		return 0, fmt.Errorf("failed to save hall layout: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM hall_layout_marker WHERE hall_id = $1`, hallLayout.HallID); err != nil {
+		return 0, fmt.Errorf("failed to delete hall layout markers: %w", err)
+	}
+
+	for _, marker := range hallLayout.Markers {
+		stmt := `INSERT INTO hall_layout_marker (hall_id, marker_type, grid_x, grid_y, width, height) VALUES ($1, $2, $3, $4, $5, $6)`
+		if _, err := tx.Exec(stmt, hallLayout.HallID, marker.MarkerType, marker.GridX, marker.GridY, marker.Width, marker.Height); err != nil {
+			return 0, fmt.Errorf("failed to insert hall layout marker: %w", err)
+		}
+	}
+
+	created := 0
+	for _, seat := range hallLayout.Seats {
+		// xmax is 0 for a freshly inserted row, and set for a row the conflict clause updated.
+		stmt := `INSERT INTO cinema_seat (seat_row, seat_number, seat_type, hall_id, grid_x, grid_y, angle) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (hall_id, seat_row, seat_number) DO UPDATE SET seat_type = EXCLUDED.seat_type, grid_x = EXCLUDED.grid_x, grid_y = EXCLUDED.grid_y, angle = EXCLUDED.angle RETURNING xmax = 0`
+
+		var inserted bool
+		err := tx.QueryRow(stmt, seat.SeatRow, seat.SeatNumber, seat.SeatType, hallLayout.HallID, seat.GridX, seat.GridY, seat.Angle).Scan(&inserted)
+		if err != nil {
+			return 0, fmt.Errorf("failed to save cinema seat %s%d: %w", seat.SeatRow, seat.SeatNumber, err)
+		}
+		if inserted {
+			created++
+		}
+	}
+
+	return created, nil
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+type DBContractIdempotency interface {
+	ClaimIdempotencyKey(userID int, key, requestHash string, ttlHours, leaseSeconds int) (string, error)
+	RetrieveIdempotentResponse(userID int, key string) (IdempotentResponse, error)
+	SaveIdempotentResponse(userID int, key, claimToken string, statusCode int, responseBody []byte) error
+	DeleteIdempotencyKey(userID int, key, claimToken string) error
+	DeleteExpiredIdempotencyKeys(ttlHours, leaseSeconds int) (int64, error)
+}
+
+// ClaimIdempotencyKey records that a user has started a request with the given idempotency key.
+// A key older than ttlHours is considered expired and is claimed again as if it were new. So is a key
+// whose request has not stored a response after leaseSeconds, as that request is assumed to have died.
+//
+// Every claim gets a new random token. A request that outlives its lease may find its key claimed by a
+// retry, so the response is only stored, or the key released, by the request holding the current token.
+//
+// Params:
+//   - userID (int): The ID of the user sending the request.
+//   - key (string): The value of the Idempotency-Key header.
+//   - requestHash (string): The SHA-256 of the request body.
+//   - ttlHours (int): How many hours a key is remembered.
+//   - leaseSeconds (int): How many seconds a request may hold a key without storing a response.
+//
+// Returns:
+//   - string: The claim token if the key was claimed by this request, or empty if it is already in use.
+//   - error: A wrapped error if the query fails.
+func (psql *Postgres) ClaimIdempotencyKey(userID int, key, requestHash string, ttlHours, leaseSeconds int) (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency claim token: %w", err)
+	}
+
+	stmt := `INSERT INTO idempotency_key (user_id, idempotency_key, request_hash, claim_token) VALUES ($1, $2, $3, $6) ON CONFLICT (user_id, idempotency_key) DO UPDATE SET request_hash = EXCLUDED.request_hash, claim_token = EXCLUDED.claim_token, status_code = NULL, response_body = NULL, created_at = CURRENT_TIMESTAMP WHERE idempotency_key.created_at < NOW() - make_interval(hours => $4) OR (idempotency_key.status_code IS NULL AND idempotency_key.created_at < NOW() - make_interval(secs => $5)) RETURNING claim_token`
+
+	var claimToken string
+	err := psql.DB.QueryRow(stmt, userID, key, requestHash, ttlHours, leaseSeconds, hex.EncodeToString(token)).Scan(&claimToken)
+	if err != nil {
+		// No row is returned when a live key already exists.
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	return claimToken, nil
+}
+
+// RetrieveIdempotentResponse retrieves what is stored for an idempotency key of a user.
+//
+// Params:
+//   - userID (int): The ID of the user who sent the request.
+//   - key (string): The value of the Idempotency-Key header.
+//
+// Returns:
+//   - IdempotentResponse: The request hash and, once the first request has finished, its response.
+//   - error: ErrIdempotencyKeyNotFound if the key is unknown, or a wrapped error.
+func (psql *Postgres) RetrieveIdempotentResponse(userID int, key string) (IdempotentResponse, error) {
+	stmt := `SELECT request_hash, COALESCE(status_code, 0), COALESCE(response_body, ''::BYTEA) FROM idempotency_key WHERE user_id = $1 AND idempotency_key = $2`
+
+	var response IdempotentResponse
+	err := psql.DB.QueryRow(stmt, userID, key).Scan(&response.RequestHash, &response.StatusCode, &response.ResponseBody)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return IdempotentResponse{}, ErrIdempotencyKeyNotFound
+		}
+		return IdempotentResponse{}, fmt.Errorf("failed to retrieve idempotent response: %w", err)
+	}
+
+	return response, nil
+}
+
+// SaveIdempotentResponse stores the response of the first request made with an idempotency key,
+// so that retries can be answered with it.
+//
+// Params:
+//   - userID (int): The ID of the user who sent the request.
+//   - key (string): The value of the Idempotency-Key header.
+//   - claimToken (string): The token returned when the request claimed the key.
+//   - statusCode (int): The HTTP status of the response.
+//   - responseBody ([]byte): The body of the response.
+//
+// Returns:
+//   - error: ErrIdempotencyKeyNotFound if the key is no longer claimed by this request, or a wrapped error.
+func (psql *Postgres) SaveIdempotentResponse(userID int, key, claimToken string, statusCode int, responseBody []byte) error {
+	stmt := `UPDATE idempotency_key SET status_code = $1, response_body = $2 WHERE user_id = $3 AND idempotency_key = $4 AND claim_token = $5`
+
+	result, err := psql.DB.Exec(stmt, statusCode, responseBody, userID, key, claimToken)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKey forgets an idempotency key, so the next request with it runs again.
+// A key claimed again by a retry in the meantime is left alone.
+//
+// Params:
+//   - userID (int): The ID of the user who sent the request.
+//   - key (string): The value of the Idempotency-Key header.
+//   - claimToken (string): The token returned when the request claimed the key.
+//
+// Returns:
+//   - error: A wrapped error if the delete fails.
+func (psql *Postgres) DeleteIdempotencyKey(userID int, key, claimToken string) error {
+	_, err := psql.DB.Exec(`DELETE FROM idempotency_key WHERE user_id = $1 AND idempotency_key = $2 AND claim_token = $3`, userID, key, claimToken)
+	if err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys forgets the keys that are no longer remembered: those older than ttlHours,
+// and those whose request has not stored a response after leaseSeconds.
+//
+// Params:
+//   - ttlHours (int): How many hours a key is remembered.
+//   - leaseSeconds (int): How many seconds a request may hold a key without storing a response.
+//
+// Returns:
+//   - int64: The number of keys deleted.
+//   - error: A wrapped error if the delete fails.
+func (psql *Postgres) DeleteExpiredIdempotencyKeys(ttlHours, leaseSeconds int) (int64, error) {
+	stmt := `DELETE FROM idempotency_key WHERE created_at < NOW() - make_interval(hours => $1) OR (status_code IS NULL AND created_at < NOW() - make_interval(secs => $2))`
+
+	result, err := psql.DB.Exec(stmt, ttlHours, leaseSeconds)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check affected rows: %w", err)
+	}
+
+	return deleted, nil
+}
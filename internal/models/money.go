@@ -0,0 +1,282 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the ISO 4217 code of the currency prices are kept in.
+const DefaultCurrency = "UZS"
+
+// maxMoneyDigits bounds the number of digits of a parsed amount, so it always fits in an int64.
+const maxMoneyDigits = 15
+
+// currencyDecimals lists the currencies whose minor unit is not a hundredth of the major unit.
+var currencyDecimals = map[string]int{
+	"BHD": 3,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"VND": 0,
+}
+
+// Money is an amount of money counted in the minor units of its currency (cents, tiyin, ...), so that
+// prices add up exactly. Only the amount is stored in the database; the currency is implied by where
+// the amount is stored.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney returns an amount of money in the minor units of the given currency, e.g., NewMoney(1999, "USD") is $19.99.
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// CurrencyDecimals returns the number of decimals of a currency: 2 for most currencies, 0 for the
+// yen, 3 for the Kuwaiti dinar.
+func CurrencyDecimals(currency string) int {
+	if decimals, found := currencyDecimals[currency]; found {
+		return decimals
+	}
+
+	return 2
+}
+
+// ParseMoney reads a decimal amount, such as "19.99" or "-5", in the major units of a currency.
+// The text is converted digit by digit, so "19.99" is exactly 1999 cents.
+//
+// Params:
+//   - amount (string): The amount, with at most as many decimals as the currency has.
+//   - currency (string): The ISO 4217 code of the currency, e.g., "UZS".
+//
+// Returns:
+//   - Money: The amount in minor units.
+//   - error: ErrInvalidMoney, wrapped with the reason, if the amount is malformed.
+func ParseMoney(amount, currency string) (Money, error) {
+	text := strings.TrimSpace(amount)
+	decimals := CurrencyDecimals(currency)
+
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(text, "-")
+
+	whole, fraction, hasPoint := strings.Cut(text, ".")
+	if whole == "" || (hasPoint && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("%w: %q is not a decimal amount", ErrInvalidMoney, amount)
+	}
+
+	if len(fraction) > decimals {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimals", ErrInvalidMoney, amount, decimals)
+	}
+
+	if len(whole)+decimals > maxMoneyDigits {
+		return Money{}, fmt.Errorf("%w: %q is too large", ErrInvalidMoney, amount)
+	}
+
+	// Pad the decimals to the minor unit, so "19.9" reads as "1990".
+	minorUnits, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q is not a decimal amount", ErrInvalidMoney, amount)
+	}
+
+	if negative {
+		minorUnits = -minorUnits
+	}
+
+	return Money{Amount: minorUnits, Currency: currency}, nil
+}
+
+// isDigits reports whether text only contains ASCII digits.
+func isDigits(text string) bool {
+	for _, char := range text {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Decimal prints the amount in major units with the decimals of its currency, e.g., "-12.50".
+func (m Money) Decimal() string {
+	decimals := CurrencyDecimals(m.Currency)
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if decimals == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+
+	unit := int64(1)
+	for i := 0; i < decimals; i++ {
+		unit *= 10
+	}
+
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, decimals, amount%unit)
+}
+
+// String prints the amount with its currency, e.g., "19.99 UZS".
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+
+	return m.Decimal() + " " + m.Currency
+}
+
+// Add returns the sum of two amounts. Adding to a zero Money without a currency takes the currency of the other amount.
+// Amounts in different currencies are never added: doing so is a programming error and panics.
+func (m Money) Add(other Money) Money {
+	return Money{Amount: m.Amount + other.Amount, Currency: m.commonCurrency(other)}
+}
+
+// Sub returns the difference of two amounts, with the same currency rules as Add.
+func (m Money) Sub(other Money) Money {
+	return Money{Amount: m.Amount - other.Amount, Currency: m.commonCurrency(other)}
+}
+
+// Mul returns the amount multiplied by a quantity, e.g., the price of several seats.
+func (m Money) Mul(quantity int) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.Currency}
+}
+
+// Percent returns the given percentage of the amount, dropping any fraction of a minor unit.
+func (m Money) Percent(percent int) Money {
+	return Money{Amount: m.Amount * int64(percent) / 100, Currency: m.Currency}
+}
+
+// Neg returns the amount with its sign flipped, e.g., to print a discount as a negative line.
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Min returns the smaller of two amounts in the same currency.
+func (m Money) Min(other Money) Money {
+	currency := m.commonCurrency(other)
+	if other.Amount < m.Amount {
+		return Money{Amount: other.Amount, Currency: currency}
+	}
+
+	return Money{Amount: m.Amount, Currency: currency}
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive reports whether the amount is greater than zero.
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// commonCurrency returns the currency two amounts share.
+func (m Money) commonCurrency(other Money) string {
+	switch {
+	case m.Currency == other.Currency || other.Currency == "":
+		return m.Currency
+	case m.Currency == "":
+		return other.Currency
+	default:
+		panic(fmt.Sprintf("models: cannot combine %s with %s", m, other))
+	}
+}
+
+// moneyJSON is how Money is written in JSON: the amount is a decimal string, so it is never rounded
+// by a float on the way in or out.
+type moneyJSON struct {
+	Amount   string
+	Currency string
+}
+
+// MarshalJSON writes the amount as {"Amount": "19.99", "Currency": "UZS"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+// UnmarshalJSON reads an amount written by MarshalJSON, or a bare decimal number or string such as
+// 19.99 or "19.99", which is taken to be in DefaultCurrency. Numbers are read from their text, never
+// through a float.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(data, []byte("{")) {
+		var value moneyJSON
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidMoney, err)
+		}
+
+		currency := strings.ToUpper(strings.TrimSpace(value.Currency))
+		if currency == "" {
+			currency = DefaultCurrency
+		}
+
+		parsed, err := ParseMoney(value.Amount, currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	text := string(data)
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidMoney, err)
+		}
+	}
+
+	parsed, err := ParseMoney(text, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount in minor units; the column it goes in decides its currency.
+func (m Money) Value() (driver.Value, error) {
+	return m.Amount, nil
+}
+
+// Scan reads an amount in minor units from an integer column. An amount scanned without a currency
+// is in DefaultCurrency.
+func (m *Money) Scan(src any) error {
+	switch value := src.(type) {
+	case int64:
+		m.Amount = value
+	case int:
+		m.Amount = int64(value)
+	case []byte:
+		amount, err := strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: cannot scan %q", ErrInvalidMoney, value)
+		}
+		m.Amount = amount
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidMoney, src)
+	}
+
+	if m.Currency == "" {
+		m.Currency = DefaultCurrency
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+)
+
+// DBContractPricing defines the operations the background dynamic pricer needs.
+type DBContractPricing interface {
+	RetrieveDynamicPricingShows() ([]DynamicPricingShow, error)
+	ApplyDynamicSeatPrices(showID, adjustmentPercent, minPricePercent, maxPricePercent int, reason string) (int, error)
+}
+
+// RetrieveDynamicPricingShows retrieves every upcoming show whose seat prices follow demand,
+// together with how full it is and how soon it starts.
+//
+// Returns:
+//   - []DynamicPricingShow: The shows to reprice; empty if there is none.
+//   - error: A wrapped error if the query fails.
+func (psql *Postgres) RetrieveDynamicPricingShows() ([]DynamicPricingShow, error) {
+	// Only booked seats count towards demand, since holds come and go; seats without a base price are never for sale.
+	stmt := `SELECT s.show_id, dp.min_price_percent, dp.max_price_percent, COUNT(ss.show_seat_id) FILTER (WHERE ss.base_price > 0), COUNT(ss.show_seat_id) FILTER (WHERE ss.base_price > 0 AND ss.status = 'Booked'), EXTRACT(EPOCH FROM ((s.show_date + s.start_time) - LOCALTIMESTAMP))::INT FROM show s JOIN show_dynamic_pricing dp ON dp.show_id = s.show_id LEFT JOIN show_seat ss ON ss.show_id = s.show_id WHERE dp.enabled AND (s.show_date + s.start_time) > LOCALTIMESTAMP GROUP BY s.show_id, dp.min_price_percent, dp.max_price_percent`
+
+	rows, err := psql.DB.Query(stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve dynamic pricing shows: %w", err)
+	}
+
+	// Ensure the rows are closed after the function finishes
+	defer rows.Close()
+
+	var shows []DynamicPricingShow
+
+	for rows.Next() {
+		var show DynamicPricingShow
+		err := rows.Scan(&show.ShowID, &show.MinPricePercent, &show.MaxPricePercent, &show.TotalSeats, &show.BookedSeats, &show.SecondsUntilShow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dynamic pricing show: %w", err)
+		}
+		shows = append(shows, show)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over dynamic pricing shows: %w", err)
+	}
+
+	return shows, nil
+}
+
+// ApplyDynamicSeatPrices moves the price of every available seat of a show to its base price adjusted by
+// adjustmentPercent, kept between the floor and the ceiling. Booked and held seats keep their price.
+// Only seats whose price actually changes are updated, and each change is recorded with the given reason.
+//
+// Parameters:
+//   - showID (int): The ID of the show to reprice.
+//   - adjustmentPercent (int): How much to move prices from their base, e.g., 25 for +25% or -10 for -10%.
+//   - minPricePercent (int): The floor, as a percentage of the base price.
+//   - maxPricePercent (int): The ceiling, as a percentage of the base price.
+//   - reason (string): Why the prices change, stored in the price history.
+//
+// Returns:
+//   - int: The number of seats repriced.
+//   - error: A wrapped error if the update fails.
+func (psql *Postgres) ApplyDynamicSeatPrices(showID, adjustmentPercent, minPricePercent, maxPricePercent int, reason string) (int, error) {
+	stmt := `WITH old AS (SELECT show_seat_id, COALESCE(price, 0) AS old_price, LEAST(GREATEST(base_price * (100 + $2) / 100, base_price * $3 / 100), base_price * $4 / 100) AS new_price FROM show_seat WHERE show_id = $1 AND status = 'Available' AND base_price > 0 FOR UPDATE), updated AS (UPDATE show_seat ss SET price = old.new_price FROM old WHERE ss.show_seat_id = old.show_seat_id AND old.new_price <> old.old_price RETURNING ss.show_seat_id, old.old_price, ss.price AS new_price), history AS (INSERT INTO seat_price_history (show_seat_id, old_price, new_price, reason) SELECT show_seat_id, old_price, new_price, $5 FROM updated) SELECT COUNT(*) FROM updated`
+
+	var updated int
+	if err := psql.DB.QueryRow(stmt, showID, adjustmentPercent, minPricePercent, maxPricePercent, reason).Scan(&updated); err != nil {
+		return 0, fmt.Errorf("failed to apply dynamic seat prices: %w", err)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// RetrieveReceipt retrieves the receipt issued for a booking.
+//
+// Params:
+//   - bookingID (int): The ID of the paid booking.
+//
+// Returns:
+//   - ReceiptRecord: The receipt number, VAT rate and issue time.
+//   - error: ErrReceiptNotFound if no receipt has been issued for the booking, or a wrapped error.
+func (psql *Postgres) RetrieveReceipt(bookingID int) (ReceiptRecord, error) {
+	stmt := `SELECT receipt_number, booking_id, vat_rate, issued_at FROM receipt WHERE booking_id = $1`
+
+	var receipt ReceiptRecord
+	err := psql.DB.QueryRow(stmt, bookingID).Scan(&receipt.ReceiptNumber, &receipt.BookingID, &receipt.VATRate, &receipt.IssuedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ReceiptRecord{}, ErrReceiptNotFound
+		}
+		return ReceiptRecord{}, fmt.Errorf("failed to retrieve receipt: %w", err)
+	}
+
+	return receipt, nil
+}
+
+// InsertReceipt issues the receipt of a paid booking under the next receipt number.
+//
+// The number is drawn from receipt_counter inside the transaction, so receipts are numbered without
+// gaps even when a payment is rolled back. A booking that already has a receipt keeps it and does not
+// use up a number. The caller must hold a lock on the booking so that it is not issued twice at once.
+//
+// Params:
+//   - bookingID (int): The ID of the paid booking; its payment is linked to the receipt.
+//   - vatRate (int): The VAT rate included in the prices, in basis points (1200 = 12%).
+//
+// Returns:
+//   - error: A wrapped error if the insertion fails.
+func (btx *postgresBookingTx) InsertReceipt(bookingID, vatRate int) error {
+	stmt := `WITH next AS (UPDATE receipt_counter SET last_number = last_number + 1 WHERE NOT EXISTS (SELECT 1 FROM receipt WHERE booking_id = $1) RETURNING last_number) INSERT INTO receipt (receipt_number, booking_id, payment_id, vat_rate) SELECT next.last_number, $1, (SELECT p.payment_id FROM payment p WHERE p.booking_id = $1 ORDER BY p.payment_id DESC LIMIT 1), $2 FROM next`
+
+	_, err := btx.tx.Exec(stmt, bookingID, vatRate)
+	if err != nil {
+		return fmt.Errorf("failed to insert receipt into the database: %w", err)
+	}
+
+	return nil
+}
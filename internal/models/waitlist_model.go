@@ -0,0 +1,233 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// InsertWaitlistEntry puts a customer at the back of the waitlist of a show.
+//
+// Parameters:
+//   - showID (int): The ID of the sold-out show.
+//   - userID (int): The ID of the waiting customer.
+//   - numberOfSeats (int): How many seats the customer needs.
+//
+// Returns:
+//   - int: The ID of the new waitlist entry.
+//   - error: ErrAlreadyWaitlisted if the customer is already waiting for the show or has seats offered,
+//     ErrShowNotFound if the show does not exist, or a wrapped error if the insertion fails.
+func (psql *Postgres) InsertWaitlistEntry(showID, userID, numberOfSeats int) (int, error) {
+	stmt := `INSERT INTO waitlist_entry (show_id, user_id, number_of_seats) VALUES ($1, $2, $3) RETURNING waitlist_entry_id`
+
+	var waitlistEntryID int
+	err := psql.DB.QueryRow(stmt, showID, userID, numberOfSeats).Scan(&waitlistEntryID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			// Only one open entry per customer and show is allowed.
+			if pqErr.Code == "23505" {
+				return 0, ErrAlreadyWaitlisted
+			}
+			// The show does not exist.
+			if pqErr.Code == "23503" {
+				return 0, ErrShowNotFound
+			}
+		}
+		return 0, fmt.Errorf("failed to insert waitlist entry: %w", err)
+	}
+
+	return waitlistEntryID, nil
+}
+
+// RetrieveWaitlistEntry retrieves the latest waitlist entry of a customer for a show, with their place
+// in the queue while they wait, and the seats held for them while an offer lasts.
+//
+// Parameters:
+//   - showID (int): The ID of the show.
+//   - userID (int): The ID of the customer.
+//
+// Returns:
+//   - WaitlistEntry: The entry.
+//   - error: ErrWaitlistEntryNotFound if the customer never joined the waitlist of the show, or a wrapped error.
+func (psql *Postgres) RetrieveWaitlistEntry(showID, userID int) (WaitlistEntry, error) {
+	stmt := `SELECT w.waitlist_entry_id, w.show_id, w.user_id, w.number_of_seats, w.status, CASE WHEN w.status = 'Waiting' THEN (SELECT COUNT(*) FROM waitlist_entry q WHERE q.show_id = w.show_id AND q.status = 'Waiting' AND (q.created_at, q.waitlist_entry_id) <= (w.created_at, w.waitlist_entry_id)) ELSE 0 END, COALESCE(w.hold_id, 0), ARRAY(SELECT ss.show_seat_id FROM show_seat ss WHERE ss.hold_id = w.hold_id AND ss.status = 'Selected' ORDER BY ss.show_seat_id), sh.expires_at, w.created_at, w.offered_at FROM waitlist_entry w LEFT JOIN seat_hold sh ON w.hold_id = sh.hold_id AND w.status = 'Offered' WHERE w.show_id = $1 AND w.user_id = $2 ORDER BY w.created_at DESC, w.waitlist_entry_id DESC LIMIT 1`
+
+	var waitlistEntry WaitlistEntry
+	var showSeatIDs pq.Int64Array
+	var holdExpiresAt, offeredAt sql.NullTime
+
+	err := psql.DB.QueryRow(stmt, showID, userID).Scan(&waitlistEntry.WaitlistEntryID, &waitlistEntry.ShowID, &waitlistEntry.UserID,
+		&waitlistEntry.NumberOfSeats, &waitlistEntry.Status, &waitlistEntry.Position, &waitlistEntry.HoldID, &showSeatIDs,
+		&holdExpiresAt, &waitlistEntry.CreatedAt, &offeredAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WaitlistEntry{}, ErrWaitlistEntryNotFound
+		}
+		return WaitlistEntry{}, fmt.Errorf("failed to retrieve waitlist entry: %w", err)
+	}
+
+	waitlistEntry.ShowSeatsID = make([]int, 0, len(showSeatIDs))
+	for _, showSeatID := range showSeatIDs {
+		waitlistEntry.ShowSeatsID = append(waitlistEntry.ShowSeatsID, int(showSeatID))
+	}
+	if holdExpiresAt.Valid {
+		waitlistEntry.HoldExpiresAt = &holdExpiresAt.Time
+	}
+	if offeredAt.Valid {
+		waitlistEntry.OfferedAt = &offeredAt.Time
+	}
+
+	return waitlistEntry, nil
+}
+
+// CancelWaitlistEntry takes a customer off the waitlist of a show. If seats were being held for them,
+// the hold is released and the seats go back to "Available" in the same transaction.
+//
+// Parameters:
+//   - showID (int): The ID of the show.
+//   - userID (int): The ID of the customer.
+//
+// Returns:
+//   - bool: Whether held seats were given back.
+//   - error: ErrWaitlistEntryNotFound if the customer is neither waiting nor has seats offered, or a wrapped error.
+func (psql *Postgres) CancelWaitlistEntry(showID, userID int) (bool, error) {
+	tx, err := psql.DB.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin waitlist cancellation transaction: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	var holdID sql.NullInt64
+	err = tx.QueryRow(`UPDATE waitlist_entry SET status = 'Cancelled' WHERE show_id = $1 AND user_id = $2 AND status IN ('Waiting', 'Offered') RETURNING hold_id`, showID, userID).Scan(&holdID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrWaitlistEntryNotFound
+		}
+		return false, fmt.Errorf("failed to cancel waitlist entry: %w", err)
+	}
+
+	released := false
+	if holdID.Valid {
+		// Close the offer's hold and give its seats that are still held to the next customer.
+		_, err = tx.Exec(`UPDATE seat_hold SET status = 'Released' WHERE hold_id = $1 AND status = 'Active'`, holdID.Int64)
+		if err != nil {
+			return false, fmt.Errorf("failed to release waitlist seat hold: %w", err)
+		}
+
+		result, err := tx.Exec(`UPDATE show_seat SET status = 'Available', hold_id = NULL WHERE hold_id = $1 AND status = 'Selected'`, holdID.Int64)
+		if err != nil {
+			return false, fmt.Errorf("failed to release waitlist show seats: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return false, fmt.Errorf("failed to check rows affected: %w", err)
+		}
+		released = rowsAffected > 0
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit waitlist cancellation: %w", err)
+	}
+
+	return released, nil
+}
+
+// ExpireWaitlistOffers closes the offers of a show whose hold is no longer active, because it lapsed
+// or was released. Offers whose seats were booked have already been fulfilled.
+//
+// Parameters:
+//   - showID (int): The ID of the show.
+//
+// Returns:
+//   - error: A wrapped error if the update fails.
+func (btx *postgresBookingTx) ExpireWaitlistOffers(showID int) error {
+	stmt := `UPDATE waitlist_entry w SET status = 'Expired' WHERE w.show_id = $1 AND w.status = 'Offered' AND NOT EXISTS (SELECT 1 FROM seat_hold sh WHERE sh.hold_id = w.hold_id AND sh.status = 'Active')`
+
+	if _, err := btx.tx.Exec(stmt, showID); err != nil {
+		return fmt.Errorf("failed to expire waitlist offers: %w", err)
+	}
+
+	return nil
+}
+
+// LockWaitingEntries locks the entries still waiting for a show, in the order they joined, so
+// concurrent rounds of offers serve the queue one at a time.
+//
+// Parameters:
+//   - showID (int): The ID of the show.
+//
+// Returns:
+//   - []WaitlistEntry: The waiting entries, first come first; empty if nobody is waiting.
+//   - error: A wrapped error if the query fails.
+func (btx *postgresBookingTx) LockWaitingEntries(showID int) ([]WaitlistEntry, error) {
+	stmt := `SELECT waitlist_entry_id, show_id, user_id, number_of_seats, status, created_at FROM waitlist_entry WHERE show_id = $1 AND status = 'Waiting' ORDER BY created_at, waitlist_entry_id FOR UPDATE`
+
+	rows, err := btx.tx.Query(stmt, showID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock waitlist entries: %w", err)
+	}
+
+	// Ensure that rows are closed after processing to avoid resource leaks.
+	defer rows.Close()
+
+	waitlistEntries := []WaitlistEntry{}
+
+	for rows.Next() {
+		var waitlistEntry WaitlistEntry
+		if err := rows.Scan(&waitlistEntry.WaitlistEntryID, &waitlistEntry.ShowID, &waitlistEntry.UserID, &waitlistEntry.NumberOfSeats,
+			&waitlistEntry.Status, &waitlistEntry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan waitlist entry: %w", err)
+		}
+		waitlistEntry.Position = len(waitlistEntries) + 1
+
+		waitlistEntries = append(waitlistEntries, waitlistEntry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over waitlist entries: %w", err)
+	}
+
+	return waitlistEntries, nil
+}
+
+// OfferWaitlistEntry records that seats are held for a waiting customer.
+//
+// Parameters:
+//   - waitlistEntryID (int): The ID of the entry.
+//   - holdID (int): The ID of the hold placed on the offered seats.
+//
+// Returns:
+//   - error: A wrapped error if the update fails.
+func (btx *postgresBookingTx) OfferWaitlistEntry(waitlistEntryID, holdID int) error {
+	stmt := `UPDATE waitlist_entry SET status = 'Offered', hold_id = $2, offered_at = NOW() WHERE waitlist_entry_id = $1`
+
+	if _, err := btx.tx.Exec(stmt, waitlistEntryID, holdID); err != nil {
+		return fmt.Errorf("failed to offer waitlist entry: %w", err)
+	}
+
+	return nil
+}
+
+// FulfillWaitlistEntries closes the open waitlist entry of a customer once they have booked the show,
+// whether or not they were offered seats first.
+//
+// Parameters:
+//   - userID (int): The ID of the customer.
+//   - showID (int): The ID of the show.
+//
+// Returns:
+//   - error: A wrapped error if the update fails.
+func (btx *postgresBookingTx) FulfillWaitlistEntries(userID, showID int) error {
+	stmt := `UPDATE waitlist_entry SET status = 'Fulfilled' WHERE user_id = $1 AND show_id = $2 AND status IN ('Waiting', 'Offered')`
+
+	if _, err := btx.tx.Exec(stmt, userID, showID); err != nil {
+		return fmt.Errorf("failed to fulfill waitlist entries: %w", err)
+	}
+
+	return nil
+}
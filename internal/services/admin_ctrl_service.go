@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -26,7 +27,7 @@ type AdminServiceInterface interface {
 	UpdateActorCrewInfo(fullName, imageURL, occupation, roleDescription string, bornDate time.Time, birthplace, about string, isActor bool, actorCrewID int) error
 	DeleteActorCrew(actorCrewID int) error
 
-	AddNewCinemaHall(hallName, hallType string, capacity int) error
+	AddNewCinemaHall(hallName, hallType, currency string, capacity int) error
 	FetchAllCinemaHalls() ([]models.CinemaHallForAdmin, error)
 	FetchCinemaHallInfo(cinemaHallID int) (models.CinemaHallForAdmin, error)
 
@@ -35,6 +36,10 @@ type AdminServiceInterface interface {
 	AddCinemaSeats(seatRow string, seatNumber int, seatType string, hallID int) error
 	FetchALLCinemaSeatsByHallID(hallID int) ([]models.CinemaSeatForAdmin, error)
 	DeleteCinemaSeat(cinemaSeatID int) error
+	UploadHallLayout(hallLayout models.HallLayout) (int, error)
+	FetchHallLayout(hallID int) (models.HallLayout, error)
+	GenerateHallSeats(spec models.HallSeatSpec) (int, error)
+	CloneHallSeats(fromHallID, toHallID int) (int, error)
 
 	AddNewShow(showDate, startTime time.Time, hallID int, movieID int) error
 	FetchAllShowsForAdmin() ([]models.ShowForAdmin, error)
@@ -42,15 +47,44 @@ type AdminServiceInterface interface {
 	DeleteShow(showID int) error
 
 	FetchAllShowSeats(showID int) ([]models.ShowSeatForAdmin, error)
-	UpdateShowSeat(seatPrice float32, showSeatID int) error
+	UpdateShowSeat(seatPrice models.Money, showSeatID int) error
+	UpdateShowSeatPrices(showID int, seatPrice models.Money, seatType, rowFrom, rowTo string) (int, error)
+	CopyShowSeatPrices(fromShowID, toShowID int) (int, error)
+	ConfigureDynamicPricing(showID int, enabled bool, minPricePercent, maxPricePercent int) error
+	FetchSeatPriceHistory(showSeatID int) ([]models.SeatPriceChange, error)
+
+	AddNewPromoCode(code, description, discountType, discountValue string, validFrom, validUntil time.Time, maxUses, maxUsesPerUser, movieID int, hallType, seatType string) error
+	FetchAllPromoCodes() ([]models.PromoCode, error)
+	FetchPromoCode(promoCodeID int) (models.PromoCode, error)
+	UpdatePromoCode(promoCodeID int, code, description, discountType, discountValue string, validFrom, validUntil time.Time, maxUses, maxUsesPerUser, movieID int, hallType, seatType string) error
+	DeletePromoCode(promoCodeID int) error
+
+	AddNewPriceRule(ruleName, hallType, seatType string, dayOfWeek *int, startTimeFrom, startTimeUntil string, price models.Money) error
+	FetchAllPriceRules() ([]models.PriceRule, error)
+	UpdatePriceRule(priceRuleID int, ruleName, hallType, seatType string, dayOfWeek *int, startTimeFrom, startTimeUntil string, price models.Money) error
+	DeletePriceRule(priceRuleID int) error
+
+	AddNewTicketCategory(name string, pricePercent, minAge, maxAge int) error
+	FetchAllTicketCategories() ([]models.TicketCategory, error)
+	UpdateTicketCategory(ticketCategoryID int, name string, pricePercent, minAge, maxAge int) error
+
+	AddNewConcessionItem(name, description, category string, price models.Money, stock *int, active bool, comboItems map[int]int) error
+	FetchAllConcessionItems() ([]models.ConcessionItem, error)
+	UpdateConcessionItem(concessionItemID int, name, description, category string, price models.Money, stock *int, active bool, comboItems map[int]int) error
+	DeleteConcessionItem(concessionItemID int) error
+
+	FetchExchangeRates() ([]models.ExchangeRate, error)
+	SetExchangeRate(currency, rate string) error
+	DeleteExchangeRate(currency string) error
 }
 
 type AdminService struct {
-	db models.DBContractAdminCtrl
+	db     models.DBContractAdminCtrl
+	events SeatEvents
 }
 
-func NewAdminService(db models.DBContractAdminCtrl) *AdminService {
-	return &AdminService{db: db}
+func NewAdminService(db models.DBContractAdminCtrl, events SeatEvents) *AdminService {
+	return &AdminService{db: db, events: events}
 }
 
 // CreateNewCarouselImage creates a new carousel image by calling the InsertNewCarouselImages method
@@ -308,6 +342,10 @@ func (as *AdminService) DeleteMovie(movieID int) error {
 		if errors.Is(err, models.ErrAdminPageMovieNotFound) {
 			return ErrAdminPageMovieNotFound
 		}
+		// Paid bookings keep their receipts, so movies with sold tickets cannot be deleted.
+		if errors.Is(err, models.ErrMovieHasPaidBookings) {
+			return ErrMovieHasPaidBookings
+		}
 		// Return any other errors, wrapping them with additional context to explain where the error occurred.
 		return fmt.Errorf("error occurred while deleting movie: %w", err)
 	}
@@ -491,13 +529,25 @@ func (as *AdminService) DeleteActorCrew(actorCrewID int) error {
 // Parameters:
 //   - hallName (string): The name of the new cinema hall.
 //   - hallType (string): The type of the cinema hall (e.g., IMAX, 3D, Regular, etc.).
+//   - currency (string): The ISO 4217 code of the currency the hall's seats are priced and paid in, or empty for the default currency.
 //   - capacity (int): The seating capacity of the new cinema hall.
 //
 // Returns:
-//   - error: Returns `nil` if the cinema hall is successfully added. Otherwise, it returns an error explaining the failure.
-func (as *AdminService) AddNewCinemaHall(hallName, hallType string, capacity int) error {
+//   - error: ErrInvalidCurrency if the currency is not a currency code, `nil` if the cinema hall is successfully added,
+//     or an error explaining the failure.
+func (as *AdminService) AddNewCinemaHall(hallName, hallType, currency string, capacity int) error {
+	// Halls settle in the default currency unless told otherwise.
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	currency, err := ParseCurrency(currency)
+	if err != nil {
+		return err
+	}
+
 	// Attempt to insert the new cinema hall into the database using the provided details.
-	err := as.db.InsertNewCinemaHall(hallName, hallType, capacity)
+	err = as.db.InsertNewCinemaHall(hallName, hallType, currency, capacity)
 	if err != nil {
 		if errors.Is(err, models.ErrDuplicatedCinemaHall) {
 			return ErrDuplicatedCinemaHall
@@ -687,7 +737,9 @@ func (as *AdminService) DeleteCinemaSeat(cinemaSeatID int) error {
 //
 // This function first formats the provided `showDate` and `startTime` as strings. Then it tries to insert a new show into
 // the database and, if successful, proceeds to fetch all cinema seats for the specified hall. It inserts a new show seat
-// for each available cinema seat in the hall, marking the status as "Available" and setting the price to 0.
+// for each available cinema seat in the hall, marking the status as "Available" and pricing it with the price rule that
+// matches the hall type, the seat type, the weekday and the start time of the show. A seat no rule matches is priced at 0
+// and stays off sale until an admin prices it.
 //
 // Parameters:
 //   - showDate (time.Time): The date of the show in time format.
@@ -699,6 +751,34 @@ func (as *AdminService) DeleteCinemaSeat(cinemaSeatID int) error {
 //   - error: Returns `nil` if the operation is successful, or an error explaining why the operation failed.
 func (as *AdminService) AddNewShow(showDate, startTime time.Time, hallID int, movieID int) error {
 
+	// Retrieve the hall type and currency, and the price rules the seats are priced with.
+	cinemaHall, err := as.db.RetrieveCinemaHallInfoByID(hallID)
+	if err != nil {
+		if errors.Is(err, models.ErrCinemaHallNotFound) {
+			return ErrCinemaHallNotFound
+		}
+		return fmt.Errorf("error occurred while retrieving cinema hall: %w", err)
+	}
+
+	priceRules, err := as.db.RetrieveAllPriceRules()
+	if err != nil && !errors.Is(err, models.ErrPriceRuleNotFound) {
+		return fmt.Errorf("error occurred while retrieving price rules: %w", err)
+	}
+
+	// Price rules are set in the default currency, so convert them into the currency of the hall
+	// before anything is inserted.
+	rates, err := loadExchangeRates(as.db, cinemaHall.Currency)
+	if err != nil {
+		return err
+	}
+
+	for i := range priceRules {
+		priceRules[i].Price, err = rates.Convert(priceRules[i].Price, cinemaHall.Currency)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Format the provided date and time into strings for database insertion.
 	formattedDate := showDate.Format("2006-01-02")
 	formattedTime := startTime.Format("15:04:05")
@@ -734,9 +814,14 @@ func (as *AdminService) AddNewShow(showDate, startTime time.Time, hallID int, mo
 		return fmt.Errorf("error occurred while retrieving cinema hall seats: %w", err)
 	}
 
-	// Insert a new show seat for each cinema seat in the hall, with initial status "Available" and price 0.
+	// Insert a new show seat for each cinema seat in the hall, with initial status "Available" and the price of its rule.
 	for _, cinemaSeat := range allCinemaSeats {
-		err = as.db.InsertNewShowSeat("Available", 0, cinemaSeat.CinemaSeatID, showID)
+		seatPrice, priceReason := models.Money{}, ""
+		if priceRule, ok := MatchPriceRule(priceRules, cinemaHall.HallType, cinemaSeat.SeatType, showDate, startTime); ok {
+			seatPrice, priceReason = priceRule.Price, "Price rule: "+priceRule.RuleName
+		}
+
+		err = as.db.InsertNewShowSeat("Available", seatPrice, priceReason, cinemaSeat.CinemaSeatID, showID)
 		if err != nil {
 			// : Handle the error if a show seat cannot be found or inserted.
 			if errors.Is(err, models.ErrShowSeatNotFound) {
@@ -843,6 +928,10 @@ func (as *AdminService) DeleteShow(showID int) error {
 		if errors.Is(err, models.ErrShowNotFound) {
 			return ErrShowNotFound
 		}
+		// Paid bookings keep their receipts, so shows with sold tickets cannot be deleted.
+		if errors.Is(err, models.ErrShowHasPaidBookings) {
+			return ErrShowHasPaidBookings
+		}
 		// : Return any other error that might have occurred during the delete operation.
 		return fmt.Errorf("error occurred while deleting show: %w", err)
 	}
@@ -876,34 +965,40 @@ func (as *AdminService) FetchAllShowSeats(showID int) ([]models.ShowSeatForAdmin
 		return nil, fmt.Errorf("error occurred while fetching all show seats: %w", err)
 	}
 
-	for i := range allShowSeats {
-		// Convert integer seat price (in cents) to float32 and divide by 100 to display as a price
-		allShowSeats[i].SeatPrice = float32(allShowSeats[i].SeatPrice) / 100.0
-	}
-
 	// Return the list of show seats if the retrieval was successful.
 	return allShowSeats, nil
 }
 
 // UpdateShowSeat updates the price of a specific show seat identified by showSeatID.
 //
-// This function updates the price of a seat associated with a specific show. It takes the new price as
-// a float (multiplied by 10) and the unique identifier for the show seat. If the seat cannot be found or
+// This function updates the price of a seat associated with a specific show. It takes the new price
+// and the unique identifier for the show seat. If the price is not valid, the seat cannot be found or
 // there is an error during the update, an appropriate error is returned. If successful, the function returns nil.
 //
 // Parameters:
-//   - seatPrice (float32): The new price of the seat to be updated (in the form of float32, multiplied by 10).
+//   - seatPrice (models.Money): The new price of the seat, in the currency of its hall.
 //   - showSeatID (int): The unique identifier for the show seat whose price needs to be updated.
 //
 // Returns:
-//   - error: Returns nil if the update was successful, or an error if the update fails.
-func (as *AdminService) UpdateShowSeat(seatPrice float32, showSeatID int) error {
+//   - error: ErrInvalidSeatPrice if the price is not valid, nil if the update was successful, or an error if the update fails.
+func (as *AdminService) UpdateShowSeat(seatPrice models.Money, showSeatID int) error {
 
-	// Adjust the seat price (multiply by 10 to match the expected data format).
-	seatPriceInCents := int(seatPrice * 100)
+	// Seats are priced in the currency of their hall.
+	currency, err := as.db.RetrieveShowSeatCurrency(showSeatID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return ErrShowSeatNotFound
+		}
+		return fmt.Errorf("error occurred while fetching the currency of the seat: %w", err)
+	}
+
+	// Make sure the price can be charged for a seat.
+	if !isPrice(seatPrice, currency) {
+		return fmt.Errorf("%w: the price must be a positive amount in %s", ErrInvalidSeatPrice, currency)
+	}
 
 	// Attempt to update the show seat's price in the database.
-	err := as.db.UpdateShowSeatByID(seatPriceInCents, showSeatID)
+	showID, err := as.db.UpdateShowSeatByID(seatPrice, showSeatID)
 	if err != nil {
 		// : If no show seat is found for the given showSeatID, return a specific error.
 		if errors.Is(err, models.ErrShowSeatNotFound) {
@@ -913,6 +1008,649 @@ func (as *AdminService) UpdateShowSeat(seatPrice float32, showSeatID int) error
 		return fmt.Errorf("error occurred while updating seat price: %w", err)
 	}
 
+	// Let customers watching the seat map see the new price.
+	as.events.PublishSeatChange(showID, "price_changed")
+
 	// Return nil if the seat price update was successful.
 	return nil
 }
+
+// UpdateShowSeatPrices sets one price for many seats of a show at once: every seat of a type, every
+// seat in a range of rows, both, or the whole show when no filter is given. The seats are repriced in a
+// single transactional update, so a failure never leaves the show half repriced.
+//
+// Parameters:
+//   - showID (int): The ID of the show to reprice.
+//   - seatPrice (models.Money): The new price, in the currency of the show's hall.
+//   - seatType (string): Only reprice seats of this type (e.g., "VIP"), or empty for every type.
+//   - rowFrom, rowTo (string): Only reprice the rows in this inclusive range (e.g., "A" to "D"); either end may be empty.
+//
+// Returns:
+//   - int: The number of seats repriced.
+//   - error: ErrInvalidBulkSeatPrice, ErrShowNotFound, ErrShowSeatNotFound if no seat matches, or a wrapped error.
+func (as *AdminService) UpdateShowSeatPrices(showID int, seatPrice models.Money, seatType, rowFrom, rowTo string) (int, error) {
+	currency, err := as.fetchShowCurrency(showID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !isPrice(seatPrice, currency) {
+		return 0, fmt.Errorf("%w: the price must be a positive amount in %s", ErrInvalidBulkSeatPrice, currency)
+	}
+
+	rowFrom = strings.ToUpper(strings.TrimSpace(rowFrom))
+	rowTo = strings.ToUpper(strings.TrimSpace(rowTo))
+	if rowFrom != "" && rowTo != "" && rowLess(rowTo, rowFrom) {
+		return 0, fmt.Errorf("%w: the first row of the range must come before the last one", ErrInvalidBulkSeatPrice)
+	}
+
+	updated, err := as.db.UpdateShowSeatPrices(showID, seatPrice, strings.TrimSpace(seatType), rowFrom, rowTo)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return 0, ErrShowSeatNotFound
+		}
+		return 0, fmt.Errorf("error occurred while updating seat prices: %w", err)
+	}
+
+	// Let customers watching the seat map see the new prices.
+	as.events.PublishSeatChange(showID, "price_changed")
+
+	return updated, nil
+}
+
+// CopyShowSeatPrices copies the seat prices of one show onto another, matching seats by row and seat
+// number. It is meant for repeating the pricing of a show on its other showtimes, so both shows must
+// play in halls of the same currency.
+//
+// Parameters:
+//   - fromShowID (int): The ID of the show whose prices are copied.
+//   - toShowID (int): The ID of the show that is repriced.
+//
+// Returns:
+//   - int: The number of seats repriced.
+//   - error: ErrInvalidBulkSeatPrice if both shows are the same or their halls charge in different currencies,
+//     ErrShowNotFound, ErrShowSeatNotFound if no seat matches, or a wrapped error.
+func (as *AdminService) CopyShowSeatPrices(fromShowID, toShowID int) (int, error) {
+	if fromShowID == toShowID {
+		return 0, fmt.Errorf("%w: prices must be copied onto another show", ErrInvalidBulkSeatPrice)
+	}
+
+	fromCurrency, err := as.fetchShowCurrency(fromShowID)
+	if err != nil {
+		return 0, err
+	}
+
+	toCurrency, err := as.fetchShowCurrency(toShowID)
+	if err != nil {
+		return 0, err
+	}
+
+	if fromCurrency != toCurrency {
+		return 0, fmt.Errorf("%w: prices in %s cannot be copied onto a show priced in %s", ErrInvalidBulkSeatPrice, fromCurrency, toCurrency)
+	}
+
+	updated, err := as.db.CopyShowSeatPrices(fromShowID, toShowID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return 0, ErrShowSeatNotFound
+		}
+		return 0, fmt.Errorf("error occurred while copying seat prices: %w", err)
+	}
+
+	as.events.PublishSeatChange(toShowID, "price_changed")
+
+	return updated, nil
+}
+
+// ConfigureDynamicPricing turns demand-based pricing of a show on or off and sets how far its seat
+// prices may move from their base price. While it is on, the background pricer moves the prices of the
+// remaining seats between the floor and the ceiling; turning it off puts them back at their base price.
+//
+// Parameters:
+//   - showID (int): The ID of the show.
+//   - enabled (bool): Whether the show's prices follow demand.
+//   - minPricePercent (int): The floor, as a percentage of the base price (1 to 100).
+//   - maxPricePercent (int): The ceiling, as a percentage of the base price (100 or more).
+//
+// Returns:
+//   - error: ErrInvalidDynamicPricing, ErrShowNotFound, or a wrapped error.
+func (as *AdminService) ConfigureDynamicPricing(showID int, enabled bool, minPricePercent, maxPricePercent int) error {
+	if minPricePercent < 1 || minPricePercent > 100 {
+		return fmt.Errorf("%w: the floor must be from 1%% to 100%% of the base price", ErrInvalidDynamicPricing)
+	}
+
+	if maxPricePercent < 100 {
+		return fmt.Errorf("%w: the ceiling must be at least 100%% of the base price", ErrInvalidDynamicPricing)
+	}
+
+	err := as.db.UpsertShowDynamicPricing(showID, enabled, minPricePercent, maxPricePercent)
+	if err != nil {
+		if errors.Is(err, models.ErrShowNotFound) {
+			return ErrShowNotFound
+		}
+		return fmt.Errorf("error occurred while saving dynamic pricing: %w", err)
+	}
+
+	// Prices of an enabled show are moved by the pricer on its next run.
+	if enabled {
+		return nil
+	}
+
+	updated, err := as.db.ApplyDynamicSeatPrices(showID, 0, 100, 100, "Dynamic pricing turned off")
+	if err != nil {
+		return fmt.Errorf("error occurred while resetting seat prices: %w", err)
+	}
+
+	if updated > 0 {
+		as.events.PublishSeatChange(showID, "price_changed")
+	}
+
+	return nil
+}
+
+// FetchSeatPriceHistory fetches every price change of a show seat, oldest first, with the reason
+// for each change.
+//
+// Parameters:
+//   - showSeatID (int): The ID of the show seat.
+//
+// Returns:
+//   - []models.SeatPriceChange: The price changes of the seat.
+//   - error: ErrShowSeatNotFound if the seat has no recorded price, or a wrapped error.
+func (as *AdminService) FetchSeatPriceHistory(showSeatID int) ([]models.SeatPriceChange, error) {
+	priceChanges, err := as.db.RetrieveShowSeatPriceHistory(showSeatID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return nil, ErrShowSeatNotFound
+		}
+		return nil, fmt.Errorf("error occurred while fetching seat price history: %w", err)
+	}
+
+	return priceChanges, nil
+}
+
+// AddNewPromoCode validates a new promo code and stores it.
+//
+// Codes are stored in upper case, so customers can type them in any case. Percentage discounts are
+// whole percents; fixed discounts are amounts of money like seat prices. A zero limit, a zero
+// movie ID or an empty hall or seat type means the code is not limited or restricted in that way.
+//
+// Parameters:
+//   - code (string): The code customers type at checkout.
+//   - description (string): An internal note about the campaign.
+//   - discountType (string): "Percentage" or "Fixed".
+//   - discountValue (string): The percent off, or the decimal amount off, e.g., "25" or "12.50".
+//   - validFrom, validUntil (time.Time): The window in which the code can be used.
+//   - maxUses (int): How many bookings may use the code in total.
+//   - maxUsesPerUser (int): How many bookings each user may use the code for.
+//   - movieID (int): The only movie the code is valid for.
+//   - hallType (string): The only hall type the code is valid in.
+//   - seatType (string): The only seat type the code discounts.
+//
+// Returns:
+//   - error: ErrInvalidPromoCode, ErrPromoCodeAlreadyExists, ErrMovieNotFoundByID, or a wrapped error.
+func (as *AdminService) AddNewPromoCode(code, description, discountType, discountValue string, validFrom, validUntil time.Time, maxUses, maxUsesPerUser, movieID int, hallType, seatType string) error {
+	promoCode, err := newPromoCode(code, description, discountType, discountValue, validFrom, validUntil, maxUses, maxUsesPerUser, movieID, hallType, seatType)
+	if err != nil {
+		return err
+	}
+
+	err = as.db.InsertNewPromoCode(promoCode)
+	if err != nil {
+		if errors.Is(err, models.ErrPromoCodeAlreadyExists) {
+			return ErrPromoCodeAlreadyExists
+		}
+		if errors.Is(err, models.ErrMovieNotFoundByID) {
+			return ErrMovieNotFoundByID
+		}
+		return fmt.Errorf("error occurred while adding new promo code: %w", err)
+	}
+
+	return nil
+}
+
+// FetchAllPromoCodes retrieves every promo code for the admin page, with how many times each is in use.
+//
+// Returns:
+//   - ([]models.PromoCode): All promo codes, newest first.
+//   - (error): ErrPromoCodeNotFound if there is no promo code yet, or a wrapped error.
+func (as *AdminService) FetchAllPromoCodes() ([]models.PromoCode, error) {
+	promoCodes, err := as.db.RetrieveAllPromoCodes()
+	if err != nil {
+		if errors.Is(err, models.ErrPromoCodeNotFound) {
+			return nil, ErrPromoCodeNotFound
+		}
+		return nil, fmt.Errorf("error occurred while fetching promo codes: %w", err)
+	}
+
+	return promoCodes, nil
+}
+
+// FetchPromoCode retrieves a single promo code for the admin page.
+//
+// Parameters:
+//   - promoCodeID (int): The ID of the promo code.
+//
+// Returns:
+//   - (models.PromoCode): The promo code with how many times it is in use.
+//   - (error): ErrPromoCodeNotFound if there is no such code, or a wrapped error.
+func (as *AdminService) FetchPromoCode(promoCodeID int) (models.PromoCode, error) {
+	promoCode, err := as.db.RetrievePromoCodeByID(promoCodeID)
+	if err != nil {
+		if errors.Is(err, models.ErrPromoCodeNotFound) {
+			return models.PromoCode{}, ErrPromoCodeNotFound
+		}
+		return models.PromoCode{}, fmt.Errorf("error occurred while fetching promo code: %w", err)
+	}
+
+	return promoCode, nil
+}
+
+// UpdatePromoCode validates and replaces every field of a promo code. The bookings that already
+// used the code keep the discount they were given.
+//
+// Parameters:
+//   - promoCodeID (int): The ID of the promo code to update.
+//   - The remaining parameters are the same as for AddNewPromoCode.
+//
+// Returns:
+//   - error: ErrInvalidPromoCode, ErrPromoCodeNotFound, ErrPromoCodeAlreadyExists, ErrMovieNotFoundByID, or a wrapped error.
+func (as *AdminService) UpdatePromoCode(promoCodeID int, code, description, discountType, discountValue string, validFrom, validUntil time.Time, maxUses, maxUsesPerUser, movieID int, hallType, seatType string) error {
+	promoCode, err := newPromoCode(code, description, discountType, discountValue, validFrom, validUntil, maxUses, maxUsesPerUser, movieID, hallType, seatType)
+	if err != nil {
+		return err
+	}
+	promoCode.PromoCodeID = promoCodeID
+
+	err = as.db.UpdatePromoCodeByID(promoCode)
+	if err != nil {
+		if errors.Is(err, models.ErrPromoCodeNotFound) {
+			return ErrPromoCodeNotFound
+		}
+		if errors.Is(err, models.ErrPromoCodeAlreadyExists) {
+			return ErrPromoCodeAlreadyExists
+		}
+		if errors.Is(err, models.ErrMovieNotFoundByID) {
+			return ErrMovieNotFoundByID
+		}
+		return fmt.Errorf("error occurred while updating promo code: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePromoCode deletes a promo code. The bookings that already used it keep their discount.
+//
+// Parameters:
+//   - promoCodeID (int): The ID of the promo code to delete.
+//
+// Returns:
+//   - error: ErrPromoCodeNotFound if there is no such code, or a wrapped error.
+func (as *AdminService) DeletePromoCode(promoCodeID int) error {
+	err := as.db.DeletePromoCodeByID(promoCodeID)
+	if err != nil {
+		if errors.Is(err, models.ErrPromoCodeNotFound) {
+			return ErrPromoCodeNotFound
+		}
+		return fmt.Errorf("error occurred while deleting promo code: %w", err)
+	}
+
+	return nil
+}
+
+// AddNewPriceRule validates a new seat price rule and stores it. The rule prices the seats of shows
+// created from now on; existing shows keep their prices.
+//
+// Parameters:
+//   - ruleName (string): A label for admins (e.g., "Matinee").
+//   - hallType (string): The only hall type the rule applies to, or empty for any.
+//   - seatType (string): The only seat type the rule applies to, or empty for any.
+//   - dayOfWeek (*int): The only weekday the rule applies on, 0 being Sunday, or nil for any.
+//   - startTimeFrom, startTimeUntil (string): The "HH:MM" band of start times the rule applies to, or empty for any.
+//   - price (models.Money): The seat price.
+//
+// Returns:
+//   - error: ErrInvalidPriceRule if a field is invalid, or a wrapped error if the insertion fails.
+func (as *AdminService) AddNewPriceRule(ruleName, hallType, seatType string, dayOfWeek *int, startTimeFrom, startTimeUntil string, price models.Money) error {
+	priceRule, err := newPriceRule(ruleName, hallType, seatType, dayOfWeek, startTimeFrom, startTimeUntil, price)
+	if err != nil {
+		return err
+	}
+
+	if err := as.db.InsertNewPriceRule(priceRule); err != nil {
+		return fmt.Errorf("error occurred while adding new price rule: %w", err)
+	}
+
+	return nil
+}
+
+// FetchAllPriceRules retrieves every seat price rule for the admin page.
+//
+// Returns:
+//   - ([]models.PriceRule): All price rules.
+//   - (error): ErrPriceRuleNotFound if there is no rule yet, or a wrapped error.
+func (as *AdminService) FetchAllPriceRules() ([]models.PriceRule, error) {
+	priceRules, err := as.db.RetrieveAllPriceRules()
+	if err != nil {
+		if errors.Is(err, models.ErrPriceRuleNotFound) {
+			return nil, ErrPriceRuleNotFound
+		}
+		return nil, fmt.Errorf("error occurred while fetching price rules: %w", err)
+	}
+
+	return priceRules, nil
+}
+
+// UpdatePriceRule validates and replaces every field of a seat price rule.
+//
+// Parameters:
+//   - priceRuleID (int): The ID of the price rule to update.
+//   - The remaining parameters are the same as for AddNewPriceRule.
+//
+// Returns:
+//   - error: ErrInvalidPriceRule, ErrPriceRuleNotFound, or a wrapped error.
+func (as *AdminService) UpdatePriceRule(priceRuleID int, ruleName, hallType, seatType string, dayOfWeek *int, startTimeFrom, startTimeUntil string, price models.Money) error {
+	priceRule, err := newPriceRule(ruleName, hallType, seatType, dayOfWeek, startTimeFrom, startTimeUntil, price)
+	if err != nil {
+		return err
+	}
+	priceRule.PriceRuleID = priceRuleID
+
+	err = as.db.UpdatePriceRuleByID(priceRule)
+	if err != nil {
+		if errors.Is(err, models.ErrPriceRuleNotFound) {
+			return ErrPriceRuleNotFound
+		}
+		return fmt.Errorf("error occurred while updating price rule: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePriceRule deletes a seat price rule.
+//
+// Parameters:
+//   - priceRuleID (int): The ID of the price rule to delete.
+//
+// Returns:
+//   - error: ErrPriceRuleNotFound if there is no such rule, or a wrapped error.
+func (as *AdminService) DeletePriceRule(priceRuleID int) error {
+	err := as.db.DeletePriceRuleByID(priceRuleID)
+	if err != nil {
+		if errors.Is(err, models.ErrPriceRuleNotFound) {
+			return ErrPriceRuleNotFound
+		}
+		return fmt.Errorf("error occurred while deleting price rule: %w", err)
+	}
+
+	return nil
+}
+
+// AddNewTicketCategory validates a new ticket category and stores it. Customers can pick it for any seat
+// from then on, as long as the movie's age limit allows it.
+//
+// Parameters:
+//   - name (string): The name customers pick (e.g., "Student").
+//   - pricePercent (int): The share of the seat price charged, 100 being the full price.
+//   - minAge, maxAge (int): The ages the category is for, or 0 for no bound.
+//
+// Returns:
+//   - error: ErrInvalidTicketCategoryDefinition, ErrTicketCategoryAlreadyExists, or a wrapped error.
+func (as *AdminService) AddNewTicketCategory(name string, pricePercent, minAge, maxAge int) error {
+	ticketCategory, err := newTicketCategory(name, pricePercent, minAge, maxAge)
+	if err != nil {
+		return err
+	}
+
+	if err := as.db.InsertNewTicketCategory(ticketCategory); err != nil {
+		if errors.Is(err, models.ErrTicketCategoryAlreadyExists) {
+			return ErrTicketCategoryAlreadyExists
+		}
+		return fmt.Errorf("error occurred while adding new ticket category: %w", err)
+	}
+
+	return nil
+}
+
+// FetchAllTicketCategories retrieves every ticket category for the admin page.
+//
+// Returns:
+//   - ([]models.TicketCategory): All ticket categories.
+//   - (error): ErrTicketCategoryNotFound if there is none, or a wrapped error.
+func (as *AdminService) FetchAllTicketCategories() ([]models.TicketCategory, error) {
+	ticketCategories, err := as.db.RetrieveAllTicketCategories()
+	if err != nil {
+		if errors.Is(err, models.ErrTicketCategoryNotFound) {
+			return nil, ErrTicketCategoryNotFound
+		}
+		return nil, fmt.Errorf("error occurred while fetching ticket categories: %w", err)
+	}
+
+	return ticketCategories, nil
+}
+
+// UpdateTicketCategory validates and replaces every field of a ticket category. Bookings already made
+// keep the price they were charged.
+//
+// Parameters:
+//   - ticketCategoryID (int): The ID of the ticket category to update.
+//   - The remaining parameters are the same as for AddNewTicketCategory.
+//
+// Returns:
+//   - error: ErrInvalidTicketCategoryDefinition, ErrTicketCategoryNotFound, ErrTicketCategoryAlreadyExists,
+//     or a wrapped error.
+func (as *AdminService) UpdateTicketCategory(ticketCategoryID int, name string, pricePercent, minAge, maxAge int) error {
+	ticketCategory, err := newTicketCategory(name, pricePercent, minAge, maxAge)
+	if err != nil {
+		return err
+	}
+	ticketCategory.TicketCategoryID = ticketCategoryID
+
+	err = as.db.UpdateTicketCategoryByID(ticketCategory)
+	if err != nil {
+		if errors.Is(err, models.ErrTicketCategoryNotFound) {
+			return ErrTicketCategoryNotFound
+		}
+		if errors.Is(err, models.ErrTicketCategoryAlreadyExists) {
+			return ErrTicketCategoryAlreadyExists
+		}
+		return fmt.Errorf("error occurred while updating ticket category: %w", err)
+	}
+
+	return nil
+}
+
+// AddNewConcessionItem validates and stores a new food or drink item, or a combo of items.
+//
+// Parameters:
+//   - name (string): The name shown to customers, e.g., "Large popcorn".
+//   - description (string): Optional details about the item.
+//   - category (string): "Food", "Drink" or "Combo".
+//   - price (models.Money): The price of the item.
+//   - stock (*int): The number of units left to sell, or nil for unlimited. Combos have no stock of their own.
+//   - active (bool): Whether customers can order the item.
+//   - comboItems (map[int]int): The quantity of each concession item ID a combo contains; empty for other items.
+//
+// Returns:
+//   - error: ErrInvalidConcessionItem, ErrConcessionItemAlreadyExists, or a wrapped error.
+func (as *AdminService) AddNewConcessionItem(name, description, category string, price models.Money, stock *int, active bool, comboItems map[int]int) error {
+	concessionItems, err := as.db.RetrieveConcessionItems(false)
+	if err != nil {
+		return fmt.Errorf("error occurred while fetching concession items: %w", err)
+	}
+
+	concessionItem, err := newConcessionItem(0, name, description, category, price, stock, active, comboItems, concessionItems)
+	if err != nil {
+		return err
+	}
+
+	if err := as.db.InsertNewConcessionItem(concessionItem); err != nil {
+		if errors.Is(err, models.ErrConcessionItemAlreadyExists) {
+			return ErrConcessionItemAlreadyExists
+		}
+		if errors.Is(err, models.ErrConcessionItemNotFound) {
+			return fmt.Errorf("%w: a combo item does not exist", ErrInvalidConcessionItem)
+		}
+		return fmt.Errorf("error occurred while adding new concession item: %w", err)
+	}
+
+	return nil
+}
+
+// FetchAllConcessionItems retrieves every concession item for the admin page, including inactive ones.
+//
+// Returns:
+//   - ([]models.ConcessionItem): All concession items, each combo with the items it contains.
+//   - (error): A wrapped error if the retrieval fails.
+func (as *AdminService) FetchAllConcessionItems() ([]models.ConcessionItem, error) {
+	concessionItems, err := as.db.RetrieveConcessionItems(false)
+	if err != nil {
+		return nil, fmt.Errorf("error occurred while fetching concession items: %w", err)
+	}
+
+	return concessionItems, nil
+}
+
+// UpdateConcessionItem validates and replaces every field of a concession item, including the contents
+// of a combo. Orders already placed keep the price they were charged.
+//
+// Parameters:
+//   - concessionItemID (int): The ID of the concession item to update.
+//   - The remaining parameters are the same as for AddNewConcessionItem.
+//
+// Returns:
+//   - error: ErrInvalidConcessionItem, ErrConcessionItemNotFound, ErrConcessionItemAlreadyExists,
+//     or a wrapped error.
+func (as *AdminService) UpdateConcessionItem(concessionItemID int, name, description, category string, price models.Money, stock *int, active bool, comboItems map[int]int) error {
+	concessionItems, err := as.db.RetrieveConcessionItems(false)
+	if err != nil {
+		return fmt.Errorf("error occurred while fetching concession items: %w", err)
+	}
+
+	concessionItem, err := newConcessionItem(concessionItemID, name, description, category, price, stock, active, comboItems, concessionItems)
+	if err != nil {
+		return err
+	}
+
+	err = as.db.UpdateConcessionItemByID(concessionItem)
+	if err != nil {
+		if errors.Is(err, models.ErrConcessionItemNotFound) {
+			return ErrConcessionItemNotFound
+		}
+		if errors.Is(err, models.ErrConcessionItemAlreadyExists) {
+			return ErrConcessionItemAlreadyExists
+		}
+		return fmt.Errorf("error occurred while updating concession item: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteConcessionItem deletes a concession item that was never ordered and is not part of a combo.
+//
+// Parameters:
+//   - concessionItemID (int): The ID of the concession item to delete.
+//
+// Returns:
+//   - error: ErrConcessionItemNotFound, ErrConcessionItemInUse if the item should be deactivated instead,
+//     or a wrapped error.
+func (as *AdminService) DeleteConcessionItem(concessionItemID int) error {
+	err := as.db.DeleteConcessionItemByID(concessionItemID)
+	if err != nil {
+		if errors.Is(err, models.ErrConcessionItemNotFound) {
+			return ErrConcessionItemNotFound
+		}
+		if errors.Is(err, models.ErrConcessionItemInUse) {
+			return ErrConcessionItemInUse
+		}
+		return fmt.Errorf("error occurred while deleting concession item: %w", err)
+	}
+
+	return nil
+}
+
+// FetchExchangeRates retrieves the exchange rates used to convert prices between currencies.
+//
+// Returns:
+//   - []models.ExchangeRate: Every rate, by currency code; empty if there is none.
+//   - error: A wrapped error if the retrieval fails.
+func (as *AdminService) FetchExchangeRates() ([]models.ExchangeRate, error) {
+	exchangeRates, err := as.db.RetrieveExchangeRates()
+	if err != nil {
+		return nil, fmt.Errorf("error occurred while fetching exchange rates: %w", err)
+	}
+
+	return exchangeRates, nil
+}
+
+// SetExchangeRate sets how many units of the default currency one unit of a currency is worth. The rate
+// converts the booking fee, food and drink, promo discounts and price rules into the currency of a hall,
+// and seat prices into the currency customers browse in.
+//
+// Parameters:
+//   - currency (string): The ISO 4217 code of the currency, e.g., "USD".
+//   - rate (string): The rate as a decimal number, e.g., "12650.5".
+//
+// Returns:
+//   - error: ErrInvalidCurrency, ErrInvalidExchangeRate, or a wrapped error.
+func (as *AdminService) SetExchangeRate(currency, rate string) error {
+	currency, err := ParseCurrency(currency)
+	if err != nil {
+		return err
+	}
+
+	// The default currency is what every rate is expressed in, so it is always worth exactly 1.
+	if currency == models.DefaultCurrency {
+		return fmt.Errorf("%w: %s is the currency rates are expressed in", ErrInvalidExchangeRate, currency)
+	}
+
+	rate, err = ParseExchangeRate(rate)
+	if err != nil {
+		return err
+	}
+
+	err = as.db.UpsertExchangeRate(currency, rate)
+	if err != nil {
+		return fmt.Errorf("error occurred while saving exchange rate: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExchangeRate removes the exchange rate of a currency. Halls charging in it can no longer add
+// the booking fee, food and drink or fixed discounts until a new rate is set.
+//
+// Parameters:
+//   - currency (string): The ISO 4217 code of the currency.
+//
+// Returns:
+//   - error: ErrInvalidCurrency, ErrExchangeRateNotFound, or a wrapped error.
+func (as *AdminService) DeleteExchangeRate(currency string) error {
+	currency, err := ParseCurrency(currency)
+	if err != nil {
+		return err
+	}
+
+	err = as.db.DeleteExchangeRate(currency)
+	if err != nil {
+		if errors.Is(err, models.ErrExchangeRateNotFound) {
+			return ErrExchangeRateNotFound
+		}
+		return fmt.Errorf("error occurred while deleting exchange rate: %w", err)
+	}
+
+	return nil
+}
+
+// fetchShowCurrency fetches the currency the seats of a show are priced in.
+func (as *AdminService) fetchShowCurrency(showID int) (string, error) {
+	currency, err := as.db.RetrieveShowCurrency(showID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowNotFound) {
+			return "", ErrShowNotFound
+		}
+		return "", fmt.Errorf("error occurred while fetching the currency of the show: %w", err)
+	}
+
+	return currency, nil
+}
@@ -2,8 +2,11 @@ package services
 
 import (
 	"cinemaGo/backend/internal/models"
+	"context"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 )
 
@@ -11,17 +14,60 @@ type BookingServiceInterface interface {
 	FetchShowMovieInfo(showID int) (models.ShowMovieInfo, error)
 	FetchShowInfo(movieID int) ([]models.ShowInfo, error)
 	FetchShowStartTimes(showDate string) ([]models.ShowStartTime, error)
-	FetchShowSeats(showID int) ([]models.ShowSeat, error)
+	FetchShowSeats(showID int, displayCurrency string) ([]models.ShowSeat, models.ShowSeatsSummary, error)
+	FetchSeatGrid(showID int, showSeats []models.ShowSeat) (models.SeatGrid, error)
+	FetchSeatMapSVG(showID int, bookingReference string, userID int, ticketToken string) ([]byte, error)
 	FetchShowSeatsMovieInfo(showID int) (models.ShowSeatsMovieInfo, error)
-	CreateNewBooking(showID, userID int, showSeatsID []int) error
+	FetchTicketCategories() ([]models.TicketCategory, error)
+	FetchConcessionItems() ([]models.ConcessionItem, error)
+	QuoteBooking(showID int, showSeatsID []int, ticketCategories map[int]string, concessions map[int]int, promoCode string) (models.PriceQuote, error)
+	CreateNewBooking(showID, userID int, showSeatsID []int, ticketCategories map[int]string, concessions map[int]int, promoCode string) (models.CreatedBooking, error)
+	HandlePaymentWebhook(payload []byte, signature string) error
+	CancelBooking(bookingID, userID int) (models.CancelledBooking, error)
+	RefundBooking(bookingID, refundPercent int, reason string) (models.CancelledBooking, error)
+	FetchUserBookings(userID int, when string, page, pageSize int) (models.BookingHistoryPage, error)
+	FetchUserBooking(bookingID, userID int) (models.BookingDetail, error)
+	FetchBookingByReference(bookingReference string) (models.BookingDetail, error)
+	FetchReceipt(bookingID, userID int) (models.Receipt, error)
+	IssueTicket(bookingID, userID int) (models.Ticket, error)
+	CheckInTicket(token string, staffID int) (models.CheckedInBooking, error)
+	CollectConcessions(token string, staffID int, bookingConcessionIDs []int) ([]models.BookingConcession, error)
+	HoldSeats(showID, userID int, showSeatsID []int) (models.SeatHold, error)
+	SuggestBestSeats(showID, count int, seatType, displayCurrency string) ([]models.ShowSeat, error)
+	HoldBestSeats(showID, userID, count int, seatType string) ([]models.ShowSeat, models.SeatHold, error)
+	WatchShowSeats(showID int) (<-chan SeatChange, func())
+	WatchWaitlistOffers(userID int) (<-chan models.WaitlistOffer, func())
+	ReleaseSeatHold(holdID, userID int) error
+	JoinWaitlist(showID, userID, numberOfSeats int) (models.WaitlistEntry, error)
+	FetchWaitlistEntry(showID, userID int) (models.WaitlistEntry, error)
+	LeaveWaitlist(showID, userID int) error
 }
 
+// BookingSettings holds the configurable rules of the booking flow.
+type BookingSettings struct {
+	HoldMinutes         int          // How long a seat hold lasts while the customer pays.
+	WaitlistHoldMinutes int          // How long seats offered to a waitlisted customer are held for them.
+	BookingFeePerSeat   models.Money // Fee charged on top of every seat, converted into the currency of the hall.
+	VATRate             int          // VAT included in every price, in basis points (1200 = 12%).
+	BlockSingleSeatGaps bool         // Whether selections that leave a single empty seat on its own are refused.
+
+	CancellationPolicy CancellationPolicy // Decides how much of a cancelled booking is refunded.
+}
+
+// MaxBookingsPageSize caps how many bookings a single history page may contain.
+const MaxBookingsPageSize = 50
+
 type BookingService struct {
-	db models.DBContractBooking
+	db       models.DBContractBooking
+	payments PaymentProvider
+	tickets  *TicketSigner
+	events   SeatEvents
+	waitlist WaitlistNotifier
+	settings BookingSettings
 }
 
-func NewBookingService(db models.DBContractBooking) *BookingService {
-	return &BookingService{db: db}
+func NewBookingService(db models.DBContractBooking, payments PaymentProvider, tickets *TicketSigner, events SeatEvents, waitlist WaitlistNotifier, settings BookingSettings) *BookingService {
+	return &BookingService{db: db, payments: payments, tickets: tickets, events: events, waitlist: waitlist, settings: settings}
 }
 
 // FetchShowMovieInfo fetches the movie details for a specific show.
@@ -137,29 +183,53 @@ func (bs *BookingService) FetchShowStartTimes(showDate string) ([]models.ShowSta
 // FetchShowSeats retrieves the list of available seats for a specific show.
 //
 // This function fetches the details of all the seats available for a show, such as seat row,
-// seat number, seat type, and the status (whether the seat is booked or available).
-// It returns the list of seats for the specified show or an error if the retrieval fails.
+// seat number, seat type, and the status (whether the seat is available, held or booked).
+// It returns the list of seats for the specified show, together with a count of available,
+// held and booked seats, or an error if the retrieval fails.
+//
+// Seats are priced in the currency of the hall; their display price is converted into the currency
+// the customer picked, when they picked one.
 //
 // Params:
 //   - showID (int): The ID of the show for which seat details are being fetched.
+//   - displayCurrency (string): The ISO 4217 code of the currency to display prices in, or empty for the hall's.
 //
 // Returns:
 //   - []models.ShowSeat: A list of seat details for the specified show.
-//   - error: An error if the retrieval fails, otherwise nil.
-func (bs *BookingService) FetchShowSeats(showID int) ([]models.ShowSeat, error) {
+//   - models.ShowSeatsSummary: The number of available, held and booked seats.
+//   - error: ErrInvalidCurrency or ErrExchangeRateNotFound if the display currency is not a currency code or
+//     has no rate, or an error if the retrieval fails.
+func (bs *BookingService) FetchShowSeats(showID int, displayCurrency string) ([]models.ShowSeat, models.ShowSeatsSummary, error) {
 	// Retrieve seat details for the given show ID from the database.
 	showSeats, err := bs.db.RetrieveShowSeats(showID)
 	if err != nil {
 		// Handle case where no seats were found for the show ID.
 		if errors.Is(err, models.ErrShowSeatNotFound) {
-			return nil, ErrShowSeatNotFound
+			return nil, models.ShowSeatsSummary{}, ErrShowSeatNotFound
 		}
 		// Return a wrapped error if there is any other failure in fetching show seats.
-		return nil, fmt.Errorf("error occurred while fetching show seats in the service section: %w", err)
+		return nil, models.ShowSeatsSummary{}, fmt.Errorf("error occurred while fetching show seats in the service section: %w", err)
+	}
+
+	if err := bs.setDisplayPrices(showSeats, displayCurrency); err != nil {
+		return nil, models.ShowSeatsSummary{}, err
+	}
+
+	// Count held seats separately from booked ones.
+	var summary models.ShowSeatsSummary
+	for _, showSeat := range showSeats {
+		switch showSeat.SeatStatus {
+		case "Available":
+			summary.Available++
+		case "Selected":
+			summary.Held++
+		default:
+			summary.Booked++
+		}
 	}
 
 	// Return the list of show seats if the fetch was successful.
-	return showSeats, nil
+	return showSeats, summary, nil
 }
 
 // FetchShowSeatsMovieInfo retrieves movie details, show date, and show start time for a specific show ID.
@@ -209,78 +279,1611 @@ func (bs *BookingService) FetchShowSeatsMovieInfo(showID int) (models.ShowSeatsM
 	return showSeatsMovieInfo, nil
 }
 
+// FetchTicketCategories fetches the ticket categories customers can pick for each seat.
+//
+// Returns:
+//   - []models.TicketCategory: Every ticket category with its price percentage and ages.
+//   - error: ErrTicketCategoryNotFound if there is none, or a wrapped error.
+func (bs *BookingService) FetchTicketCategories() ([]models.TicketCategory, error) {
+	ticketCategories, err := bs.db.RetrieveAllTicketCategories()
+	if err != nil {
+		if errors.Is(err, models.ErrTicketCategoryNotFound) {
+			return nil, ErrTicketCategoryNotFound
+		}
+		return nil, fmt.Errorf("error occurred while fetching ticket categories in the service section: %w", err)
+	}
+
+	return ticketCategories, nil
+}
+
+// QuoteBooking previews the price of a seat selection before the customer pays.
+//
+// The quote is built from the current show_seat prices, adjusted for the ticket category of each
+// seat, plus the configured booking fee and any food and drink ordered, minus the discount of the promo
+// code when one is given. It is in the currency of the hall; the booking fee, food and drink and fixed
+// discounts are converted into it with the admins' exchange rates. It does not reserve the seats, the
+// concession stock or the code, so the final price is confirmed again when the booking is created.
+// The per-user cap of a code is only checked then, since quotes are available to guests.
+//
+// Params:
+//   - showID (int): The ID of the show the seats belong to.
+//   - showSeatsID ([]int): The IDs of the selected show seats.
+//   - ticketCategories (map[int]string): The ticket category chosen for each show seat ID; seats left out are "Adult".
+//   - concessions (map[int]int): The number of units ordered of each concession item ID, or nil for none.
+//   - promoCode (string): The promo code entered by the customer, or empty for none.
+//
+// Returns:
+//   - models.PriceQuote: The itemised quote with its subtotal, concessions, fees, discount and total.
+//   - error: An error if a seat does not belong to the show, has no price, a ticket category is not allowed,
+//     the concession order cannot be served, the promo code cannot be used, an amount cannot be converted
+//     into the currency of the hall or the retrieval fails.
+func (bs *BookingService) QuoteBooking(showID int, showSeatsID []int, ticketCategories map[int]string, concessions map[int]int, promoCode string) (models.PriceQuote, error) {
+	// If more than 5 seats are selected, return an error before touching the database.
+	if len(showSeatsID) > 5 {
+		return models.PriceQuote{}, ErrTooManySeats
+	}
+
+	showSeats, err := bs.db.RetrieveShowSeatsByIDs(showID, showSeatsID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return models.PriceQuote{}, ErrShowSeatNotFound
+		}
+		return models.PriceQuote{}, fmt.Errorf("error occurred while fetching the selected seats in the service section: %w", err)
+	}
+
+	show, tickets, err := bs.assignTicketCategories(showID, showSeatsID, ticketCategories)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+
+	// The booking is paid in the currency of the hall, so the amounts set for the whole chain are converted into it.
+	rates, err := bs.exchangeRates(show.Currency)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+
+	feePerSeat, err := rates.Convert(bs.settings.BookingFeePerSeat, show.Currency)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+
+	quote, err := buildPriceQuote(showID, showSeats, tickets, feePerSeat)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+
+	if len(concessions) > 0 {
+		concessionItems, err := bs.db.RetrieveConcessionItems(false)
+		if err != nil {
+			return models.PriceQuote{}, fmt.Errorf("error occurred while fetching concession items in the service section: %w", err)
+		}
+
+		concessionItems, err = convertConcessionPrices(concessionItems, rates, show.Currency)
+		if err != nil {
+			return models.PriceQuote{}, err
+		}
+
+		concessionLines, _, err := PriceConcessions(concessions, concessionItems)
+		if err != nil {
+			return models.PriceQuote{}, err
+		}
+		quote = addConcessionsToQuote(quote, concessionLines)
+	}
+
+	if promoCode == "" {
+		return quote, nil
+	}
+
+	promo, err := bs.db.RetrievePromoCode(NormalizePromoCode(promoCode))
+	if err != nil {
+		if errors.Is(err, models.ErrPromoCodeNotFound) {
+			return models.PriceQuote{}, ErrPromoCodeNotFound
+		}
+		return models.PriceQuote{}, fmt.Errorf("error occurred while fetching the promo code in the service section: %w", err)
+	}
+
+	promo, err = convertPromoCode(promo, rates, show.Currency)
+	if err != nil {
+		return models.PriceQuote{}, err
+	}
+
+	return ApplyPromoCode(quote, showSeats, promo, show, time.Now())
+}
+
 // CreateNewBooking handles the creation of a new booking for a user by selecting seats and processing the booking.
 //
-// This function verifies the availability of the selected seats, updates their status, creates a new booking,
-// updates the booking's seat status, and finally inserts the payment details into the database. It ensures that
-// no more than five seats can be selected at once and handles any errors encountered during these operations.
+// The whole booking is a single unit of work: the selected show seats are locked, checked for availability,
+// priced, one booking is created with its itemised quote, every seat is linked to it and a pending payment
+// is recorded. Either all of these writes are committed together or none of them is, so two users can never
+// buy the same seat and a failure halfway never leaves orphaned rows behind. No more than five seats can be
+// selected at once.
+//
+// The quoted total is authorized with the payment provider only once the booking is committed, so a slow
+// provider never keeps the seats locked. A payment the provider declines or fails to authorize fails the
+// booking and gives its seats back, as a failed payment webhook does.
+//
+// Every seat is sold as the ticket category chosen for it, or as "Adult" when none is chosen; categories
+// the movie's age limit does not allow, such as child tickets for an "18+" film, are rejected before
+// anything is written.
+//
+// Food and drink ordered with the booking is locked, charged on top of the seats and taken off the stock,
+// and waits at the counter until the customer collects it. Promo codes only discount the seats.
+//
+// A promo code is locked, checked against its caps and restrictions and applied to the quote before the
+// payment is authorized, and its use is recorded with the booking.
+//
+// Every amount is charged in the currency of the hall, as QuoteBooking explains.
+//
+// The booking stays "Pending" until the provider's webhook confirms or fails the payment. A booking whose
+// discount covers the whole price has nothing to authorize and is confirmed right away.
 //
 // Params:
 //   - showID (int): The ID of the show that the user is booking seats for.
 //   - userID (int): The ID of the user who is making the booking.
 //   - showSeatsID ([]int): A slice of seat IDs that the user is selecting for the booking.
+//   - ticketCategories (map[int]string): The ticket category chosen for each show seat ID; seats left out are "Adult".
+//   - concessions (map[int]int): The number of units ordered of each concession item ID, or nil for none.
+//   - promoCode (string): The promo code entered by the customer, or empty for none.
 //
 // Returns:
+//   - models.CreatedBooking: The new booking, its status and the quote it was charged.
 //   - error: Returns nil if the booking was created successfully, or an error if any part of the process fails.
-func (bs *BookingService) CreateNewBooking(showID, userID int, showSeatsID []int) error {
-	var numberOfSeats int
+func (bs *BookingService) CreateNewBooking(showID, userID int, showSeatsID []int, ticketCategories map[int]string, concessions map[int]int, promoCode string) (models.CreatedBooking, error) {
+	// If more than 5 seats are selected, return an error before touching the database.
+	if len(showSeatsID) > 5 {
+		return models.CreatedBooking{}, ErrTooManySeats
+	}
+
+	// Check the ticket categories against the movie before locking anything.
+	show, tickets, err := bs.assignTicketCategories(showID, showSeatsID, ticketCategories)
+	if err != nil {
+		return models.CreatedBooking{}, err
+	}
+
+	// The booking is paid in the currency of the hall, so the amounts set for the whole chain are converted into it.
+	rates, err := bs.exchangeRates(show.Currency)
+	if err != nil {
+		return models.CreatedBooking{}, err
+	}
+
+	feePerSeat, err := rates.Convert(bs.settings.BookingFeePerSeat, show.Currency)
+	if err != nil {
+		return models.CreatedBooking{}, err
+	}
+
+	// Start the booking unit of work.
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return models.CreatedBooking{}, fmt.Errorf("error occurred while starting the booking transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	// Lock the selected seats so no concurrent booking can take them until we are done.
+	lockedSeats, err := tx.LockShowSeats(showID, showSeatsID)
+	if err != nil {
+		// If any of the seats does not belong to the show, return the error.
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return models.CreatedBooking{}, ErrShowSeatNotFound
+		}
+		return models.CreatedBooking{}, fmt.Errorf("error occurred while locking the selected seats in the service section: %w", err)
+	}
+
+	// Every selected seat must still be available, or held by this user.
+	showSeats := make([]models.ShowSeat, 0, len(lockedSeats))
+	unheld := false
+	for _, lockedSeat := range lockedSeats {
+		if !isShowSeatBookable(lockedSeat, userID) {
+			return models.CreatedBooking{}, ErrShowSeatHasSelected
+		}
+		if lockedSeat.SeatStatus == "Available" {
+			unheld = true
+		}
+		showSeats = append(showSeats, lockedSeat.ShowSeat)
+	}
+
+	// Held seats were checked for gaps when they were held, or were offered from the waitlist.
+	if unheld {
+		if err := bs.checkSingleSeatGaps(showID, showSeatsID); err != nil {
+			return models.CreatedBooking{}, err
+		}
+	}
+
+	// Price the locked seats; the prices cannot change until the transaction ends.
+	quote, err := buildPriceQuote(showID, showSeats, tickets, feePerSeat)
+	if err != nil {
+		return models.CreatedBooking{}, err
+	}
+
+	// Remember which price change set each seat's price, so the booking can explain what it was charged.
+	priceHistoryIDs := make(map[int]int, len(lockedSeats))
+	for _, lockedSeat := range lockedSeats {
+		priceHistoryIDs[lockedSeat.ShowSeatID] = lockedSeat.PriceHistoryID
+	}
+	for i, item := range quote.Items {
+		if item.ItemType == "Seat" {
+			quote.Items[i].SeatPriceHistoryID = priceHistoryIDs[item.ShowSeatID]
+		}
+	}
+
+	// Lock the ordered food and drink, so its stock cannot be sold twice, and take it off the stock.
+	var concessionLines []models.BookingConcession
+	if len(concessions) > 0 {
+		concessionItems, err := tx.LockConcessionItems(concessionOrderIDs(concessions))
+		if err != nil {
+			if errors.Is(err, models.ErrConcessionItemNotFound) {
+				return models.CreatedBooking{}, fmt.Errorf("%w: an ordered item does not exist", ErrInvalidConcessionOrder)
+			}
+			return models.CreatedBooking{}, fmt.Errorf("error occurred while locking concession items in the service section: %w", err)
+		}
+
+		concessionItems, err = convertConcessionPrices(concessionItems, rates, show.Currency)
+		if err != nil {
+			return models.CreatedBooking{}, err
+		}
+
+		var units map[int]int
+		concessionLines, units, err = PriceConcessions(concessions, concessionItems)
+		if err != nil {
+			return models.CreatedBooking{}, err
+		}
+
+		err = tx.DecrementConcessionStock(units)
+		if err != nil {
+			if errors.Is(err, models.ErrConcessionOutOfStock) {
+				return models.CreatedBooking{}, ErrConcessionOutOfStock
+			}
+			return models.CreatedBooking{}, fmt.Errorf("error occurred while updating concession stock in the service section: %w", err)
+		}
+
+		quote = addConcessionsToQuote(quote, concessionLines)
+	}
 
-	// Loop through the selected show seats to check if they are available.
-	for _, showSeatID := range showSeatsID {
-		// Retrieve the current status of the seat.
-		showSeatStatus, err := bs.db.RetrieveShowSeatStatus(showSeatID, showID)
+	// Apply the promo code, locked so that concurrent bookings cannot exceed its caps.
+	var promo models.PromoCode
+	if promoCode != "" {
+		promo, err = tx.LockPromoCode(NormalizePromoCode(promoCode))
 		if err != nil {
-			// If an error occurs during status retrieval (show not found), return the error.
-			if errors.Is(err, models.ErrShowNotFound) {
-				return ErrShowNotFound
+			if errors.Is(err, models.ErrPromoCodeNotFound) {
+				return models.CreatedBooking{}, ErrPromoCodeNotFound
 			}
-			return fmt.Errorf("error occurred while fetching the status of the seat in the service section: %w", err)
+			return models.CreatedBooking{}, fmt.Errorf("error occurred while locking the promo code in the service section: %w", err)
 		}
 
-		// If the seat is not available, set the number of seats to 0 and return an error.
-		if showSeatStatus != "Available" {
-			numberOfSeats = 0
-			return ErrShowSeatHasSelected
+		chargedPromo, err := convertPromoCode(promo, rates, show.Currency)
+		if err != nil {
+			return models.CreatedBooking{}, err
+		}
+
+		quote, err = ApplyPromoCode(quote, showSeats, chargedPromo, show, time.Now())
+		if err != nil {
+			return models.CreatedBooking{}, err
+		}
+
+		if promo.MaxUsesPerUser > 0 {
+			uses, err := tx.CountUserPromoCodeUses(promo.PromoCodeID, userID)
+			if err != nil {
+				return models.CreatedBooking{}, fmt.Errorf("error occurred while counting promo code uses in the service section: %w", err)
+			}
+			if uses >= promo.MaxUsesPerUser {
+				return models.CreatedBooking{}, ErrPromoCodeUsageLimitReached
+			}
 		}
-		// Increment the number of seats selected.
-		numberOfSeats++
 	}
 
-	// If more than 5 seats are selected, return an error.
-	if numberOfSeats > 5 {
-		return ErrTooManySeats
+	// A booking with nothing to pay is confirmed at once; any other waits for the payment.
+	bookingStatus := "Pending"
+	if quote.Total.IsZero() {
+		bookingStatus = "Confirmed"
+	}
+
+	// Create exactly one booking for all of the selected seats, under a reference no other booking has.
+	bookingID, bookingReference, err := insertNewBooking(tx, len(lockedSeats), bookingStatus, quote, userID)
+	if err != nil {
+		return models.CreatedBooking{}, fmt.Errorf("error occurred while creating new booking in the service section: %w", err)
 	}
 
-	// Loop through the selected seats again to update their statuses and create the booking.
-	for _, showSeatID := range showSeatsID {
-		// Mark the seat as "Selected" in the database.
-		err := bs.db.UpdateShowSeatStatus("Selected", showSeatID, showID)
+	// Count the booking against the promo code's caps.
+	if quote.PromoCode != "" {
+		err = tx.InsertPromoCodeRedemption(promo.PromoCodeID, userID, bookingID, quote.Discount)
 		if err != nil {
-			return fmt.Errorf("failed to update status of the show seat in the service section: %w", err)
+			return models.CreatedBooking{}, fmt.Errorf("error occurred while recording the promo code use in the service section: %w", err)
 		}
+	}
+
+	// Store the itemised quote next to the booking.
+	err = tx.InsertBookingItems(bookingID, quote.Items)
+	if err != nil {
+		return models.CreatedBooking{}, fmt.Errorf("error occurred while storing booking items in the service section: %w", err)
+	}
 
-		// Create a new booking in the database with "Pending" payment status.
-		bookingID, err := bs.db.InsertNewBooking(numberOfSeats, "Pending", userID, showID)
+	// Queue the food and drink for pickup at the counter.
+	if len(concessionLines) > 0 {
+		err = tx.InsertBookingConcessions(bookingID, concessionLines)
 		if err != nil {
-			return fmt.Errorf("error occurred while creating new booking in the service section: %w", err)
+			return models.CreatedBooking{}, fmt.Errorf("error occurred while storing booking concessions in the service section: %w", err)
 		}
+	}
+
+	// Mark every seat as "Booked" and link it to the new booking.
+	err = tx.LinkShowSeatsToBooking("Booked", bookingID, showID, showSeatsID)
+	if err != nil {
+		return models.CreatedBooking{}, fmt.Errorf("failed to link the show seats to the booking in the service section: %w", err)
+	}
+
+	// Close the user's holds on this show whose seats have now all been booked.
+	err = tx.ConvertSeatHolds(userID, showID)
+	if err != nil {
+		return models.CreatedBooking{}, fmt.Errorf("error occurred while converting seat holds in the service section: %w", err)
+	}
+
+	// A customer who books the show no longer waits for it, whether or not they were offered seats.
+	err = tx.FulfillWaitlistEntries(userID, showID)
+	if err != nil {
+		return models.CreatedBooking{}, fmt.Errorf("error occurred while fulfilling waitlist entries in the service section: %w", err)
+	}
 
-		// Mark the seat as "Booked" after the booking is created.
-		err = bs.db.UpdateShowSeatStatus("Booked", showSeatID, showID)
+	if bookingStatus == "Confirmed" {
+		// Record an empty payment, so the free booking can be cancelled like any other.
+		err = tx.InsertPaymentDetails(quote.Total, "", "PromoCode", "Captured", bookingID)
 		if err != nil {
-			return fmt.Errorf("failed to update status of the show seat in the service section: %w", err)
+			return models.CreatedBooking{}, fmt.Errorf("error occurred while inserting payment details in the service section: %w", err)
 		}
 
-		// Insert payment details with a "Pending" status (with a default amount of 100).
-		err = bs.db.InsertPaymentDetails(100, 0, "", bookingID)
+		// The booking is paid in full by the promo code, so its receipt is issued straight away.
+		err = tx.InsertReceipt(bookingID, bs.settings.VATRate)
+		if err != nil {
+			return models.CreatedBooking{}, fmt.Errorf("error occurred while issuing the receipt in the service section: %w", err)
+		}
+	} else {
+		// Record the payment for the quoted total; it is authorized once the seats are no longer locked.
+		err = tx.InsertPaymentDetails(quote.Total, "", bs.payments.Name(), "Pending", bookingID)
 		if err != nil {
-			return fmt.Errorf("error occurred while inserting payment details in the service section: %w", err)
+			return models.CreatedBooking{}, fmt.Errorf("error occurred while inserting payment details in the service section: %w", err)
+		}
+	}
+
+	// Commit every write of the booking at once.
+	if err := tx.Commit(); err != nil {
+		return models.CreatedBooking{}, fmt.Errorf("error occurred while committing the booking in the service section: %w", err)
+	}
+
+	bs.events.PublishSeatChange(showID, "booked")
+
+	if bookingStatus == "Pending" {
+		if err := bs.authorizePayment(bookingID, userID, quote.Total); err != nil {
+			return models.CreatedBooking{}, err
+		}
+	}
+
+	// Return the new booking with the quote it was charged.
+	return models.CreatedBooking{
+		BookingID:        bookingID,
+		BookingReference: bookingReference,
+		BookingStatus:    bookingStatus,
+		Quote:            quote,
+	}, nil
+}
+
+// HandlePaymentWebhook applies a payment outcome reported by the provider's webhook.
+//
+// The webhook signature is verified first. A successful payment confirms its "Pending" booking, and the
+// confirmation is committed before the payment is captured, so money is never collected for a booking
+// that did not get confirmed. The captured payment is then recorded and its receipt issued. If the
+// capture fails, the authorization is voided and the booking fails.
+//
+// A failed payment moves the booking to "Failed", returns its seats to "Available" and its food and drink
+// to the stock. Bookings that are no longer "Pending" are left untouched, so redelivered webhooks are harmless,
+// except for a confirmed booking whose payment is still "Authorized": its capture was interrupted, e.g., the
+// capture was not recorded after the money was collected, so a redelivered success runs the capture again.
+//
+// Params:
+//   - payload ([]byte): The raw webhook request body.
+//   - signature (string): The signature sent with the webhook.
+//
+// Returns:
+//   - error: ErrInvalidWebhookSignature, ErrInvalidWebhookPayload or ErrPaymentNotFound for bad deliveries,
+//     or a wrapped error if processing fails.
+func (bs *BookingService) HandlePaymentWebhook(payload []byte, signature string) error {
+	// Make sure the webhook really comes from the provider.
+	event, err := bs.payments.VerifyWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, ErrInvalidWebhookSignature) || errors.Is(err, ErrInvalidWebhookPayload) {
+			return err
+		}
+		return fmt.Errorf("error occurred while verifying the payment webhook in the service section: %w", err)
+	}
+
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return fmt.Errorf("error occurred while starting the payment webhook transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	// Lock the payment and its booking so concurrent deliveries are applied one at a time.
+	bookingPayment, err := tx.LockBookingPayment(event.RemoteTransactionID)
+	if err != nil {
+		if errors.Is(err, models.ErrPaymentNotFound) {
+			return ErrPaymentNotFound
+		}
+		return fmt.Errorf("error occurred while locking the booking payment in the service section: %w", err)
+	}
+
+	// The capture of a confirmed booking did not complete; finish it now. Captures are idempotent, so
+	// money already collected is not collected twice.
+	if event.Status == "succeeded" && bookingPayment.BookingStatus == "Confirmed" && bookingPayment.PaymentStatus == "Authorized" {
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("error occurred while committing the payment webhook in the service section: %w", err)
+		}
+
+		return bs.capturePayment(bookingPayment)
+	}
+
+	// The outcome has already been applied.
+	if bookingPayment.BookingStatus != "Pending" {
+		return nil
+	}
+
+	if event.Status == "succeeded" {
+		// Confirm the booking before any money is collected for it.
+		if err := tx.UpdateBookingStatus(bookingPayment.BookingID, "Confirmed"); err != nil {
+			return fmt.Errorf("error occurred while confirming the booking in the service section: %w", err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("error occurred while committing the payment webhook in the service section: %w", err)
+		}
+
+		return bs.capturePayment(bookingPayment)
+	}
+
+	if err := failBooking(tx, bookingPayment); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error occurred while committing the payment webhook in the service section: %w", err)
+	}
+
+	// A failed payment gives the seats back.
+	bs.events.PublishSeatChange(bookingPayment.ShowID, "released")
+	bs.offerWaitlistSeats(bookingPayment.ShowID)
+
+	return nil
+}
+
+// authorizePayment authorizes the total of a booking that has just been committed and records the
+// provider's transaction ID, so the webhook can find the payment. A booking whose payment cannot be
+// authorized fails and gives its seats back.
+func (bs *BookingService) authorizePayment(bookingID, userID int, total models.Money) error {
+	remoteTransactionID, err := bs.payments.Authorize(bookingID, total)
+	if err != nil {
+		if failErr := bs.failPendingBooking(bookingID, userID); failErr != nil {
+			log.Printf("error occurred while failing booking %d after its payment was not authorized: %v", bookingID, failErr)
+		}
+		if errors.Is(err, ErrPaymentDeclined) {
+			return ErrPaymentDeclined
+		}
+		return fmt.Errorf("error occurred while authorizing the payment in the service section: %w", err)
+	}
+
+	if err := bs.recordAuthorization(bookingID, userID, remoteTransactionID); err != nil {
+		// Nobody can confirm a payment that was not recorded, so the authorization is given up.
+		if voidErr := bs.payments.Void(remoteTransactionID); voidErr != nil {
+			log.Printf("error occurred while voiding payment %s of booking %d: %v", remoteTransactionID, bookingID, voidErr)
+		}
+		if failErr := bs.failPendingBooking(bookingID, userID); failErr != nil {
+			log.Printf("error occurred while failing booking %d after its payment was not recorded: %v", bookingID, failErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// recordAuthorization stores the provider's transaction ID on the pending payment of a booking.
+func (bs *BookingService) recordAuthorization(bookingID, userID int, remoteTransactionID string) error {
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return fmt.Errorf("error occurred while starting the payment authorization transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	bookingPayment, err := tx.LockUserBookingPayment(bookingID, userID)
+	if err != nil {
+		return fmt.Errorf("error occurred while locking the booking payment in the service section: %w", err)
+	}
+
+	if err := tx.UpdatePaymentAuthorization(bookingPayment.PaymentID, remoteTransactionID); err != nil {
+		return fmt.Errorf("error occurred while recording the payment authorization in the service section: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error occurred while committing the payment authorization in the service section: %w", err)
+	}
+
+	return nil
+}
+
+// failPendingBooking fails a booking that is still "Pending" and gives its seats back.
+func (bs *BookingService) failPendingBooking(bookingID, userID int) error {
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return fmt.Errorf("error occurred while starting the failed booking transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	bookingPayment, err := tx.LockUserBookingPayment(bookingID, userID)
+	if err != nil {
+		return fmt.Errorf("error occurred while locking the booking payment in the service section: %w", err)
+	}
+
+	if bookingPayment.BookingStatus != "Pending" {
+		return nil
+	}
+
+	if err := failBooking(tx, bookingPayment); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error occurred while committing the failed booking in the service section: %w", err)
+	}
+
+	bs.events.PublishSeatChange(bookingPayment.ShowID, "released")
+	bs.offerWaitlistSeats(bookingPayment.ShowID)
+
+	return nil
+}
+
+// capturePayment collects the authorized amount of a booking that has just been confirmed, then records
+// the captured payment and issues its receipt. If the capture fails, the authorization is voided and the
+// booking fails and gives its seats back. It is safe to run again for a payment whose capture was not
+// recorded: the provider does not capture twice, and the receipt is only issued once.
+func (bs *BookingService) capturePayment(bookingPayment models.BookingPayment) error {
+	if err := bs.payments.Capture(bookingPayment.RemoteTransactionID, bookingPayment.Amount); err != nil {
+		log.Printf("error occurred while capturing payment %s of booking %d: %v", bookingPayment.RemoteTransactionID, bookingPayment.BookingID, err)
+
+		if voidErr := bs.payments.Void(bookingPayment.RemoteTransactionID); voidErr != nil {
+			log.Printf("error occurred while voiding payment %s of booking %d: %v", bookingPayment.RemoteTransactionID, bookingPayment.BookingID, voidErr)
 		}
+
+		return bs.failConfirmedBooking(bookingPayment)
+	}
+
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return fmt.Errorf("error occurred while starting the payment capture transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	lockedPayment, err := tx.LockBookingPayment(bookingPayment.RemoteTransactionID)
+	if err != nil {
+		return fmt.Errorf("error occurred while locking the booking payment in the service section: %w", err)
+	}
+
+	// A concurrent delivery of the webhook has already recorded the capture.
+	if lockedPayment.PaymentStatus != "Authorized" {
+		return nil
+	}
+
+	if err := tx.UpdatePaymentStatus(bookingPayment.PaymentID, "Captured"); err != nil {
+		return fmt.Errorf("error occurred while updating the payment status in the service section: %w", err)
+	}
+
+	// Issue the receipt of the captured payment with the next receipt number.
+	if err := tx.InsertReceipt(bookingPayment.BookingID, bs.settings.VATRate); err != nil {
+		return fmt.Errorf("error occurred while issuing the receipt in the service section: %w", err)
 	}
 
-	// Reset the number of seats after completing the booking process.
-	numberOfSeats = 0
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error occurred while committing the captured payment in the service section: %w", err)
+	}
 
-	// Return nil indicating the successful creation of the booking.
 	return nil
 }
+
+// failConfirmedBooking fails a booking whose payment could not be captured after it was confirmed.
+func (bs *BookingService) failConfirmedBooking(bookingPayment models.BookingPayment) error {
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return fmt.Errorf("error occurred while starting the failed booking transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	bookingPayment, err = tx.LockBookingPayment(bookingPayment.RemoteTransactionID)
+	if err != nil {
+		return fmt.Errorf("error occurred while locking the booking payment in the service section: %w", err)
+	}
+
+	// A concurrent delivery of the webhook has captured the payment in the meantime.
+	if bookingPayment.PaymentStatus != "Authorized" {
+		return nil
+	}
+
+	if err := failBooking(tx, bookingPayment); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error occurred while committing the failed booking in the service section: %w", err)
+	}
+
+	bs.events.PublishSeatChange(bookingPayment.ShowID, "released")
+	bs.offerWaitlistSeats(bookingPayment.ShowID)
+
+	return nil
+}
+
+// failBooking marks a booking and its payment as "Failed", gives its seats back to other customers and
+// puts its food and drink back in stock.
+func failBooking(tx models.BookingTx, bookingPayment models.BookingPayment) error {
+	if err := tx.UpdatePaymentStatus(bookingPayment.PaymentID, "Failed"); err != nil {
+		return fmt.Errorf("error occurred while updating the payment status in the service section: %w", err)
+	}
+
+	if err := tx.UpdateBookingStatus(bookingPayment.BookingID, "Failed"); err != nil {
+		return fmt.Errorf("error occurred while failing the booking in the service section: %w", err)
+	}
+
+	if err := tx.ReleaseBookingSeats(bookingPayment.BookingID); err != nil {
+		return fmt.Errorf("error occurred while releasing the booking seats in the service section: %w", err)
+	}
+
+	if err := tx.RestockBookingConcessions(bookingPayment.BookingID); err != nil {
+		return fmt.Errorf("error occurred while restocking the booking concessions in the service section: %w", err)
+	}
+
+	return nil
+}
+
+// CancelBooking cancels a confirmed booking on behalf of its owner and refunds it according to the
+// cancellation policy.
+//
+// The booking and its payment are locked, the refund percentage is chosen from the time left until the
+// show starts, the refund is recorded next to the payment as pending, the booking's seats go back to
+// "Available", its uncollected food and drink goes back in stock and the booking becomes "Cancelled" —
+// all in one transaction. The freed seats are then offered to the waitlist of the show and the refund is
+// paid out with the payment provider; a refund the provider does not pay out is retried by
+// RunRefundSettler.
+//
+// Params:
+//   - bookingID (int): The ID of the booking to cancel.
+//   - userID (int): The ID of the user who owns the booking.
+//
+// Returns:
+//   - models.CancelledBooking: The refunded percentage and amount.
+//   - error: ErrBookingNotFound, ErrBookingNotCancellable, ErrCancellationWindowClosed, or a wrapped error.
+func (bs *BookingService) CancelBooking(bookingID, userID int) (models.CancelledBooking, error) {
+	lockBooking := func(tx models.BookingTx) (models.BookingPayment, error) {
+		return tx.LockUserBookingPayment(bookingID, userID)
+	}
+
+	// Pick the refund percentage from the time left until the show.
+	refundPercent := func(bookingPayment models.BookingPayment) (int, error) {
+		refundPercent, allowed := bs.settings.CancellationPolicy.RefundPercent(time.Duration(bookingPayment.SecondsUntilShow) * time.Second)
+		if !allowed {
+			return 0, ErrCancellationWindowClosed
+		}
+		return refundPercent, nil
+	}
+
+	return bs.cancelBooking(lockBooking, refundPercent, "Cancelled by customer")
+}
+
+// RefundBooking cancels a confirmed booking on behalf of an admin, e.g., when a show is called off, and
+// refunds the given share of its payment. It works like CancelBooking, except that the booking may belong
+// to any customer and the cancellation policy does not apply, so a booking can be refunded at any time
+// before its seats are admitted.
+//
+// Params:
+//   - bookingID (int): The ID of the booking to cancel.
+//   - refundPercent (int): The share of the payment to refund, from 0 to 100.
+//   - reason (string): Why the booking is refunded, recorded with the refund; "Cancelled by admin" if empty.
+//
+// Returns:
+//   - models.CancelledBooking: The refunded percentage and amount.
+//   - error: ErrInvalidRefundPercent, ErrBookingNotFound, ErrBookingNotCancellable, or a wrapped error.
+func (bs *BookingService) RefundBooking(bookingID, refundPercent int, reason string) (models.CancelledBooking, error) {
+	if refundPercent < 0 || refundPercent > 100 {
+		return models.CancelledBooking{}, ErrInvalidRefundPercent
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = "Cancelled by admin"
+	}
+
+	lockBooking := func(tx models.BookingTx) (models.BookingPayment, error) {
+		return tx.LockBookingPaymentByID(bookingID)
+	}
+
+	return bs.cancelBooking(lockBooking, func(models.BookingPayment) (int, error) { return refundPercent, nil }, reason)
+}
+
+// cancelBooking locks a booking with lockBooking, records a pending refund of the percentage refundPercent
+// picks for it, gives its seats and its food and drink back and cancels it in one transaction, then offers
+// the freed seats to the waitlist of the show and pays the refund out. The provider is only asked for the
+// money once the cancellation is committed, so a failed cancellation never pays out.
+func (bs *BookingService) cancelBooking(lockBooking func(models.BookingTx) (models.BookingPayment, error), refundPercent func(models.BookingPayment) (int, error), reason string) (models.CancelledBooking, error) {
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return models.CancelledBooking{}, fmt.Errorf("error occurred while starting the cancellation transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	// Lock the booking and its payment so it cannot be cancelled or confirmed twice.
+	bookingPayment, err := lockBooking(tx)
+	if err != nil {
+		if errors.Is(err, models.ErrBookingNotFound) {
+			return models.CancelledBooking{}, ErrBookingNotFound
+		}
+		return models.CancelledBooking{}, fmt.Errorf("error occurred while locking the booking in the service section: %w", err)
+	}
+
+	// Only paid bookings can be cancelled; pending ones are still waiting for the provider, confirmed
+	// ones whose payment is being captured are not paid yet, and admitted ones have already been used.
+	if bookingPayment.BookingStatus != "Confirmed" || bookingPayment.PaymentStatus != "Captured" || bookingPayment.Admitted {
+		return models.CancelledBooking{}, ErrBookingNotCancellable
+	}
+
+	percent, err := refundPercent(bookingPayment)
+	if err != nil {
+		return models.CancelledBooking{}, err
+	}
+
+	refundAmount := bookingPayment.Amount.Percent(percent)
+
+	// Record the refund next to the original payment; it is paid out once the cancellation is committed.
+	if refundAmount.IsPositive() {
+		err = tx.InsertRefund(bookingPayment.PaymentID, refundAmount, "", reason)
+		if err != nil {
+			return models.CancelledBooking{}, fmt.Errorf("error occurred while recording the refund in the service section: %w", err)
+		}
+
+		if err := tx.UpdatePaymentStatus(bookingPayment.PaymentID, "RefundPending"); err != nil {
+			return models.CancelledBooking{}, fmt.Errorf("error occurred while updating the payment status in the service section: %w", err)
+		}
+	}
+
+	// Give the seats back to other customers.
+	if err := tx.ReleaseBookingSeats(bookingPayment.BookingID); err != nil {
+		return models.CancelledBooking{}, fmt.Errorf("error occurred while releasing the booking seats in the service section: %w", err)
+	}
+
+	// Put the food and drink that has not been collected yet back in stock.
+	if err := tx.RestockBookingConcessions(bookingPayment.BookingID); err != nil {
+		return models.CancelledBooking{}, fmt.Errorf("error occurred while restocking the booking concessions in the service section: %w", err)
+	}
+
+	if err := tx.CancelBooking(bookingPayment.BookingID); err != nil {
+		return models.CancelledBooking{}, fmt.Errorf("error occurred while cancelling the booking in the service section: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.CancelledBooking{}, fmt.Errorf("error occurred while committing the cancellation in the service section: %w", err)
+	}
+
+	bs.events.PublishSeatChange(bookingPayment.ShowID, "cancelled")
+	bs.offerWaitlistSeats(bookingPayment.ShowID)
+
+	// The booking is cancelled either way; a refund that is not paid out now is retried by RunRefundSettler.
+	if refundAmount.IsPositive() {
+		pendingRefund := models.PendingRefund{
+			BookingID:           bookingPayment.BookingID,
+			RemoteTransactionID: bookingPayment.RemoteTransactionID,
+			Amount:              refundAmount,
+		}
+		if err := bs.settleRefund(pendingRefund); err != nil {
+			log.Printf("error occurred while paying out the refund of booking %d: %v", bookingPayment.BookingID, err)
+		}
+	}
+
+	return models.CancelledBooking{
+		BookingID:     bookingPayment.BookingID,
+		RefundPercent: percent,
+		RefundAmount:  refundAmount,
+	}, nil
+}
+
+// FetchUserBookings retrieves one page of a user's booking history.
+//
+// Params:
+//   - userID (int): The ID of the user whose bookings are listed.
+//   - when (string): "upcoming" or "past" to only list bookings for shows that have not started or
+//     have already started, or "" for every booking.
+//   - page (int): The 1-based page number.
+//   - pageSize (int): The number of bookings per page, capped at MaxBookingsPageSize.
+//
+// Returns:
+//   - models.BookingHistoryPage: The bookings of the page along with the total number of matching bookings.
+//   - error: ErrInvalidBookingFilter for an unknown filter, or a wrapped error.
+func (bs *BookingService) FetchUserBookings(userID int, when string, page, pageSize int) (models.BookingHistoryPage, error) {
+	if when != "" && when != "upcoming" && when != "past" {
+		return models.BookingHistoryPage{}, ErrInvalidBookingFilter
+	}
+
+	// Keep the page within sane bounds instead of rejecting the request.
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > MaxBookingsPageSize {
+		pageSize = MaxBookingsPageSize
+	}
+
+	bookings, err := bs.db.RetrieveUserBookings(userID, when, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return models.BookingHistoryPage{}, fmt.Errorf("error occurred while fetching user bookings in the service section: %w", err)
+	}
+
+	total, err := bs.db.CountUserBookings(userID, when)
+	if err != nil {
+		return models.BookingHistoryPage{}, fmt.Errorf("error occurred while counting user bookings in the service section: %w", err)
+	}
+
+	return models.BookingHistoryPage{
+		Bookings:      bookings,
+		Page:          page,
+		PageSize:      pageSize,
+		TotalBookings: total,
+	}, nil
+}
+
+// FetchUserBooking retrieves the full details of one of a user's bookings.
+//
+// Params:
+//   - bookingID (int): The ID of the booking.
+//   - userID (int): The ID of the user who must own the booking.
+//
+// Returns:
+//   - models.BookingDetail: The booking with its seats, itemised lines, payment and status.
+//   - error: ErrBookingNotFound if the user has no booking with that ID, or a wrapped error.
+func (bs *BookingService) FetchUserBooking(bookingID, userID int) (models.BookingDetail, error) {
+	booking, err := bs.db.RetrieveUserBooking(bookingID, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrBookingNotFound) {
+			return models.BookingDetail{}, ErrBookingNotFound
+		}
+		return models.BookingDetail{}, fmt.Errorf("error occurred while fetching user booking in the service section: %w", err)
+	}
+
+	return booking, nil
+}
+
+// FetchBookingByReference looks any booking up by the reference the customer quotes at the helpdesk.
+// The reference may be typed in any case and without its "CG-" prefix.
+//
+// Params:
+//   - bookingReference (string): The booking reference, e.g., "CG-7KQ2MX".
+//
+// Returns:
+//   - models.BookingDetail: The booking with its seats, itemised lines, payment and status.
+//   - error: ErrInvalidBookingReference, ErrBookingNotFound, or a wrapped error.
+func (bs *BookingService) FetchBookingByReference(bookingReference string) (models.BookingDetail, error) {
+	bookingReference, err := NormalizeBookingReference(bookingReference)
+	if err != nil {
+		return models.BookingDetail{}, err
+	}
+
+	booking, err := bs.db.RetrieveBookingByReference(bookingReference)
+	if err != nil {
+		if errors.Is(err, models.ErrBookingNotFound) {
+			return models.BookingDetail{}, ErrBookingNotFound
+		}
+		return models.BookingDetail{}, fmt.Errorf("error occurred while fetching booking by reference in the service section: %w", err)
+	}
+
+	return booking, nil
+}
+
+// FetchReceipt retrieves the receipt of one of a user's paid bookings, with every line charged,
+// the totals and the VAT included in them.
+//
+// A receipt is issued when the payment is captured. Bookings paid before receipts existed are issued
+// one the first time it is asked for, under the booking's lock so two requests cannot both number it.
+// Refunded bookings keep their receipt, which then shows the amount refunded.
+//
+// Params:
+//   - bookingID (int): The ID of the booking.
+//   - userID (int): The ID of the user who must own the booking.
+//
+// Returns:
+//   - models.Receipt: The receipt with its line-item breakdown, VAT and total.
+//   - error: ErrBookingNotFound, ErrReceiptNotAvailable if the booking has not been paid, or a wrapped error.
+func (bs *BookingService) FetchReceipt(bookingID, userID int) (models.Receipt, error) {
+	booking, err := bs.FetchUserBooking(bookingID, userID)
+	if err != nil {
+		return models.Receipt{}, err
+	}
+
+	// Pending and failed payments have not been collected, so there is nothing to give a receipt for.
+	if booking.PaymentStatus != "Captured" && booking.PaymentStatus != "RefundPending" && booking.PaymentStatus != "Refunded" {
+		return models.Receipt{}, ErrReceiptNotAvailable
+	}
+
+	receipt, err := bs.db.RetrieveReceipt(bookingID)
+	if errors.Is(err, models.ErrReceiptNotFound) {
+		receipt, err = bs.issueReceipt(bookingID, userID)
+	}
+	if err != nil {
+		return models.Receipt{}, fmt.Errorf("error occurred while fetching the receipt in the service section: %w", err)
+	}
+
+	return BuildReceipt(booking, receipt), nil
+}
+
+// issueReceipt issues the receipt of a booking paid before receipts existed and retrieves it.
+// Locking the booking first makes concurrent requests wait for each other; InsertReceipt leaves
+// a booking that has been issued a receipt in the meantime untouched.
+func (bs *BookingService) issueReceipt(bookingID, userID int) (models.ReceiptRecord, error) {
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return models.ReceiptRecord{}, err
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	if _, err := tx.LockUserBookingPayment(bookingID, userID); err != nil {
+		return models.ReceiptRecord{}, err
+	}
+
+	if err := tx.InsertReceipt(bookingID, bs.settings.VATRate); err != nil {
+		return models.ReceiptRecord{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.ReceiptRecord{}, err
+	}
+
+	return bs.db.RetrieveReceipt(bookingID)
+}
+
+// IssueTicket issues the e-ticket of a confirmed booking. The ticket carries a signed token that
+// the helpdesk scans at the door; the same token is rendered as a QR code by TicketQRCode.
+//
+// Params:
+//   - bookingID (int): The ID of the booking.
+//   - userID (int): The ID of the user who must own the booking.
+//
+// Returns:
+//   - models.Ticket: The signed token along with what is printed on the ticket.
+//   - error: ErrBookingNotFound, ErrTicketNotAvailable if the booking is not confirmed, or a wrapped error.
+func (bs *BookingService) IssueTicket(bookingID, userID int) (models.Ticket, error) {
+	booking, err := bs.FetchUserBooking(bookingID, userID)
+	if err != nil {
+		return models.Ticket{}, err
+	}
+
+	// Pending, failed and cancelled bookings never admit anyone.
+	if booking.BookingStatus != "Confirmed" {
+		return models.Ticket{}, ErrTicketNotAvailable
+	}
+
+	token, err := bs.tickets.Sign(booking.BookingID, booking.ShowID, userID)
+	if err != nil {
+		return models.Ticket{}, err
+	}
+
+	return models.Ticket{
+		BookingID:        booking.BookingID,
+		BookingReference: booking.BookingReference,
+		Token:            token,
+		MovieTitle:       booking.MovieTitle,
+		HallName:         booking.HallName,
+		ShowDate:         booking.ShowDate,
+		ShowStartTime:    booking.ShowStartTime,
+		Seats:            booking.Seats,
+		Concessions:      booking.Concessions,
+	}, nil
+}
+
+// CheckInTicket admits the holder of a scanned e-ticket.
+//
+// The token's signature is verified, the booking is locked so that simultaneous scans cannot both
+// succeed, and every seat of the booking is marked as admitted by the helpdesk user. A ticket whose
+// seats have already been admitted is rejected.
+//
+// Params:
+//   - token (string): The ticket token read from the QR code.
+//   - staffID (int): The ID of the helpdesk user scanning the ticket.
+//
+// Returns:
+//   - models.CheckedInBooking: The admitted booking and how many seats were admitted.
+//   - error: ErrInvalidTicket, ErrTicketNotAvailable, ErrTicketAlreadyUsed, or a wrapped error.
+func (bs *BookingService) CheckInTicket(token string, staffID int) (models.CheckedInBooking, error) {
+	claims, err := bs.tickets.Verify(token)
+	if err != nil {
+		return models.CheckedInBooking{}, err
+	}
+
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return models.CheckedInBooking{}, fmt.Errorf("error occurred while starting the check-in transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	checkIn, err := tx.LockBookingForCheckIn(claims.BookingID)
+	if err != nil {
+		if errors.Is(err, models.ErrBookingNotFound) {
+			return models.CheckedInBooking{}, ErrInvalidTicket
+		}
+		return models.CheckedInBooking{}, fmt.Errorf("error occurred while locking the booking for check-in in the service section: %w", err)
+	}
+
+	// A validly signed ticket must still describe the booking as it is stored.
+	if checkIn.ShowID != claims.ShowID || checkIn.UserID != claims.UserID {
+		return models.CheckedInBooking{}, ErrInvalidTicket
+	}
+
+	// The booking may have been cancelled after its ticket was issued.
+	if checkIn.BookingStatus != "Confirmed" {
+		return models.CheckedInBooking{}, ErrTicketNotAvailable
+	}
+
+	if checkIn.AdmittedAt != nil {
+		return models.CheckedInBooking{}, ErrTicketAlreadyUsed
+	}
+
+	seatsAdmitted, err := tx.AdmitBookingSeats(checkIn.BookingID, staffID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return models.CheckedInBooking{}, ErrTicketNotAvailable
+		}
+		return models.CheckedInBooking{}, fmt.Errorf("error occurred while admitting the booking seats in the service section: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.CheckedInBooking{}, fmt.Errorf("error occurred while committing the check-in in the service section: %w", err)
+	}
+
+	// Show the helpdesk what the ticket is for.
+	booking, err := bs.FetchUserBooking(checkIn.BookingID, checkIn.UserID)
+	if err != nil {
+		return models.CheckedInBooking{}, err
+	}
+
+	return models.CheckedInBooking{
+		BookingHistoryEntry: booking.BookingHistoryEntry,
+		SeatsAdmitted:       seatsAdmitted,
+		Concessions:         booking.Concessions,
+	}, nil
+}
+
+// CollectConcessions hands the food and drink of a booking over at the counter.
+//
+// The customer shows the same e-ticket as at the door. Its signature is verified and the booking is
+// locked, so two members of staff cannot hand the same order over twice. Only confirmed bookings can
+// collect their order, before or after the customer has been admitted.
+//
+// Params:
+//   - token (string): The ticket token read from the QR code.
+//   - staffID (int): The ID of the helpdesk user handing the order over.
+//   - bookingConcessionIDs ([]int): The ordered lines being handed over, or empty for every pending line.
+//
+// Returns:
+//   - []models.BookingConcession: Every line of the order with its pickup status after the handover.
+//   - error: ErrInvalidTicket, ErrTicketNotAvailable, ErrConcessionsAlreadyCollected if there is nothing
+//     left to collect, or a wrapped error.
+func (bs *BookingService) CollectConcessions(token string, staffID int, bookingConcessionIDs []int) ([]models.BookingConcession, error) {
+	claims, err := bs.tickets.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return nil, fmt.Errorf("error occurred while starting the concession pickup transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	checkIn, err := tx.LockBookingForCheckIn(claims.BookingID)
+	if err != nil {
+		if errors.Is(err, models.ErrBookingNotFound) {
+			return nil, ErrInvalidTicket
+		}
+		return nil, fmt.Errorf("error occurred while locking the booking for concession pickup in the service section: %w", err)
+	}
+
+	// A validly signed ticket must still describe the booking as it is stored.
+	if checkIn.ShowID != claims.ShowID || checkIn.UserID != claims.UserID {
+		return nil, ErrInvalidTicket
+	}
+
+	// Orders of unpaid or cancelled bookings are never handed over.
+	if checkIn.BookingStatus != "Confirmed" {
+		return nil, ErrTicketNotAvailable
+	}
+
+	collected, err := tx.CollectBookingConcessions(checkIn.BookingID, staffID, bookingConcessionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error occurred while collecting the booking concessions in the service section: %w", err)
+	}
+
+	if collected == 0 {
+		return nil, ErrConcessionsAlreadyCollected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error occurred while committing the concession pickup in the service section: %w", err)
+	}
+
+	// Show the helpdesk what is left to hand over.
+	booking, err := bs.FetchUserBooking(checkIn.BookingID, checkIn.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return booking.Concessions, nil
+}
+
+// FetchConcessionItems fetches the food and drink customers can order with their tickets.
+//
+// Returns:
+//   - []models.ConcessionItem: Every active concession item, each combo with the items it contains.
+//   - error: A wrapped error if the retrieval fails.
+func (bs *BookingService) FetchConcessionItems() ([]models.ConcessionItem, error) {
+	concessionItems, err := bs.db.RetrieveConcessionItems(true)
+	if err != nil {
+		return nil, fmt.Errorf("error occurred while fetching concession items in the service section: %w", err)
+	}
+
+	return concessionItems, nil
+}
+
+// HoldSeats reserves the selected seats of a show for a user while they pay.
+//
+// The seats are locked, checked for availability and marked as "Selected" under a new hold in a
+// single transaction. The hold lasts for the configured number of minutes; afterwards the background
+// expirer returns its seats to "Available". No more than five seats can be held at once, and, when the
+// rule is switched on, they must not leave a single empty seat on its own.
+//
+// Params:
+//   - showID (int): The ID of the show the seats belong to.
+//   - userID (int): The ID of the user placing the hold.
+//   - showSeatsID ([]int): The IDs of the show seats to hold.
+//
+// Returns:
+//   - models.SeatHold: The new hold, including the moment it expires.
+//   - error: An error if any seat is unavailable or any part of the process fails.
+func (bs *BookingService) HoldSeats(showID, userID int, showSeatsID []int) (models.SeatHold, error) {
+	// If more than 5 seats are selected, return an error before touching the database.
+	if len(showSeatsID) > 5 {
+		return models.SeatHold{}, ErrTooManySeats
+	}
+
+	// Start the hold unit of work.
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return models.SeatHold{}, fmt.Errorf("error occurred while starting the seat hold transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	// Lock the selected seats so no concurrent booking or hold can take them.
+	lockedSeats, err := tx.LockShowSeats(showID, showSeatsID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return models.SeatHold{}, ErrShowSeatNotFound
+		}
+		return models.SeatHold{}, fmt.Errorf("error occurred while locking the selected seats in the service section: %w", err)
+	}
+
+	// A seat can only be held if nobody else holds or has booked it.
+	for _, lockedSeat := range lockedSeats {
+		if !isShowSeatBookable(lockedSeat, 0) {
+			return models.SeatHold{}, ErrShowSeatHasSelected
+		}
+	}
+
+	if err := bs.checkSingleSeatGaps(showID, showSeatsID); err != nil {
+		return models.SeatHold{}, err
+	}
+
+	// Create the hold with its expiry.
+	holdID, expiresAt, err := tx.InsertSeatHold(userID, showID, bs.settings.HoldMinutes)
+	if err != nil {
+		return models.SeatHold{}, fmt.Errorf("error occurred while creating new seat hold in the service section: %w", err)
+	}
+
+	// Mark every seat as "Selected" under the new hold.
+	err = tx.HoldShowSeats(holdID, showID, showSeatsID)
+	if err != nil {
+		return models.SeatHold{}, fmt.Errorf("error occurred while holding the show seats in the service section: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.SeatHold{}, fmt.Errorf("error occurred while committing the seat hold in the service section: %w", err)
+	}
+
+	bs.events.PublishSeatChange(showID, "held")
+
+	return models.SeatHold{
+		HoldID:      holdID,
+		ShowID:      showID,
+		ShowSeatsID: showSeatsID,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// SuggestBestSeats suggests the best block of adjacent available seats of a show for a group.
+//
+// Params:
+//   - showID (int): The ID of the show.
+//   - count (int): How many adjacent seats the group needs (at most 5).
+//   - seatType (string): Only suggest seats of this type (e.g., "VIP"); empty for any type.
+//   - displayCurrency (string): The ISO 4217 code of the currency to display prices in, or empty for the hall's.
+//
+// Returns:
+//   - []models.ShowSeat: The suggested seats, ordered by seat number.
+//   - error: ErrTooManySeats, ErrShowSeatNotFound, ErrNoAdjacentSeats, ErrExchangeRateNotFound, or a wrapped error.
+func (bs *BookingService) SuggestBestSeats(showID, count int, seatType, displayCurrency string) ([]models.ShowSeat, error) {
+	// A group can only book as many seats as a single booking allows.
+	if count > 5 {
+		return nil, ErrTooManySeats
+	}
+
+	showSeats, err := bs.db.RetrieveShowSeats(showID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return nil, ErrShowSeatNotFound
+		}
+		return nil, fmt.Errorf("error occurred while fetching show seats for suggestion in the service section: %w", err)
+	}
+
+	bestSeats, err := suggestAdjacentSeats(showSeats, count, seatType, bs.settings.BlockSingleSeatGaps)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bs.setDisplayPrices(bestSeats, displayCurrency); err != nil {
+		return nil, err
+	}
+
+	return bestSeats, nil
+}
+
+// HoldBestSeats suggests the best block of adjacent seats and immediately places a hold on it
+// for the user, so the suggestion cannot be taken while they pay.
+//
+// Params:
+//   - showID (int): The ID of the show.
+//   - userID (int): The ID of the user placing the hold.
+//   - count (int): How many adjacent seats the group needs (at most 5).
+//   - seatType (string): Only suggest seats of this type (e.g., "VIP"); empty for any type.
+//
+// Returns:
+//   - []models.ShowSeat: The held seats.
+//   - models.SeatHold: The hold placed on them.
+//   - error: The errors of SuggestBestSeats and HoldSeats.
+func (bs *BookingService) HoldBestSeats(showID, userID, count int, seatType string) ([]models.ShowSeat, models.SeatHold, error) {
+	bestSeats, err := bs.SuggestBestSeats(showID, count, seatType, "")
+	if err != nil {
+		return nil, models.SeatHold{}, err
+	}
+
+	showSeatsID := make([]int, 0, len(bestSeats))
+	for _, showSeat := range bestSeats {
+		showSeatsID = append(showSeatsID, showSeat.ShowSeatID)
+	}
+
+	seatHold, err := bs.HoldSeats(showID, userID, showSeatsID)
+	if err != nil {
+		return nil, models.SeatHold{}, err
+	}
+
+	return bestSeats, seatHold, nil
+}
+
+// ReleaseSeatHold releases a user's active hold and returns its seats to "Available", offering them
+// to the waitlist of the show first.
+//
+// Params:
+//   - holdID (int): The ID of the hold to release.
+//   - userID (int): The ID of the user who owns the hold.
+//
+// Returns:
+//   - error: ErrSeatHoldNotFound if the user has no active hold with that ID, or a wrapped error.
+func (bs *BookingService) ReleaseSeatHold(holdID, userID int) error {
+	showID, err := bs.db.ReleaseSeatHold(holdID, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrSeatHoldNotFound) {
+			return ErrSeatHoldNotFound
+		}
+		return fmt.Errorf("error occurred while releasing seat hold in the service section: %w", err)
+	}
+
+	bs.events.PublishSeatChange(showID, "released")
+	bs.offerWaitlistSeats(showID)
+
+	return nil
+}
+
+// WatchShowSeats subscribes to the seat changes of a show, wherever they happen. The returned
+// function must be called once the caller stops watching.
+//
+// Params:
+//   - showID (int): The ID of the show to watch.
+//
+// Returns:
+//   - <-chan SeatChange: Receives a change every time the seat map of the show must be refreshed.
+//   - func(): Stops watching and closes the channel.
+func (bs *BookingService) WatchShowSeats(showID int) (<-chan SeatChange, func()) {
+	return bs.events.SubscribeSeatChanges(showID)
+}
+
+// WatchWaitlistOffers subscribes to the waitlist offers made to a customer, on whichever instance
+// the seats were freed. The returned function must be called once the caller stops watching.
+//
+// Params:
+//   - userID (int): The ID of the customer.
+//
+// Returns:
+//   - <-chan models.WaitlistOffer: Receives every offer made to the customer, in the order they were made.
+//   - func(): Stops watching and closes the channel.
+func (bs *BookingService) WatchWaitlistOffers(userID int) (<-chan models.WaitlistOffer, func()) {
+	return bs.waitlist.SubscribeWaitlistOffers(userID)
+}
+
+// RunSeatHoldExpirer periodically returns the seats of abandoned holds to "Available" and offers
+// them to the waitlist of their show.
+// It blocks until the context is cancelled, so it is meant to be started in its own goroutine.
+//
+// Params:
+//   - ctx (context.Context): Stops the expirer when cancelled.
+//   - interval (time.Duration): How often expired holds are swept.
+func (bs *BookingService) RunSeatHoldExpirer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A failed sweep is retried on the next tick, so only log it.
+			showIDs, err := bs.db.ExpireSeatHolds()
+			if err != nil {
+				log.Printf("error occurred while expiring seat holds: %v", err)
+				continue
+			}
+
+			for _, showID := range showIDs {
+				bs.events.PublishSeatChange(showID, "expired")
+				bs.offerWaitlistSeats(showID)
+			}
+		}
+	}
+}
+
+// RunRefundSettler periodically pays out the refunds of cancelled bookings that the payment provider
+// has not paid out yet.
+// It blocks until the context is cancelled, so it is meant to be started in its own goroutine.
+//
+// Params:
+//   - ctx (context.Context): Stops the settler when cancelled.
+//   - interval (time.Duration): How often pending refunds are retried.
+func (bs *BookingService) RunRefundSettler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A failed sweep is retried on the next tick, so only log it.
+			pendingRefunds, err := bs.db.RetrievePendingRefunds()
+			if err != nil {
+				log.Printf("error occurred while retrieving pending refunds: %v", err)
+				continue
+			}
+
+			for _, pendingRefund := range pendingRefunds {
+				if err := bs.settleRefund(pendingRefund); err != nil {
+					log.Printf("error occurred while paying out the refund of booking %d: %v", pendingRefund.BookingID, err)
+				}
+			}
+		}
+	}
+}
+
+// settleRefund pays a pending refund out with the payment provider and records the provider's refund ID.
+// The refund key is derived from the booking, which is refunded at most once, so the provider never pays
+// a retried refund out twice. A payment that has been returned in full is marked as refunded; a partly
+// refunded payment goes back to "Captured".
+func (bs *BookingService) settleRefund(pendingRefund models.PendingRefund) error {
+	refundKey := fmt.Sprintf("booking-%d-refund", pendingRefund.BookingID)
+	remoteRefundID, err := bs.payments.Refund(pendingRefund.RemoteTransactionID, pendingRefund.Amount, refundKey)
+	if err != nil {
+		return fmt.Errorf("error occurred while refunding the payment in the service section: %w", err)
+	}
+
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return fmt.Errorf("error occurred while starting the refund transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	bookingPayment, err := tx.LockBookingPaymentByID(pendingRefund.BookingID)
+	if err != nil {
+		return fmt.Errorf("error occurred while locking the booking in the service section: %w", err)
+	}
+
+	// Another attempt has already recorded the refund.
+	if bookingPayment.PaymentStatus != "RefundPending" {
+		return nil
+	}
+
+	if err := tx.CompleteRefund(bookingPayment.PaymentID, remoteRefundID); err != nil {
+		return fmt.Errorf("error occurred while recording the refund in the service section: %w", err)
+	}
+
+	paymentStatus := "Captured"
+	if pendingRefund.Amount == bookingPayment.Amount {
+		paymentStatus = "Refunded"
+	}
+
+	if err := tx.UpdatePaymentStatus(bookingPayment.PaymentID, paymentStatus); err != nil {
+		return fmt.Errorf("error occurred while updating the payment status in the service section: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error occurred while committing the refund in the service section: %w", err)
+	}
+
+	return nil
+}
+
+// assignTicketCategories fetches the show being booked and the ticket categories, and picks the category
+// of every selected seat with AssignTicketCategories.
+func (bs *BookingService) assignTicketCategories(showID int, showSeatsID []int, choices map[int]string) (models.ShowMovieHall, map[int]models.TicketCategory, error) {
+	show, err := bs.fetchShowMovieHall(showID)
+	if err != nil {
+		return models.ShowMovieHall{}, nil, err
+	}
+
+	ticketCategories, err := bs.FetchTicketCategories()
+	if err != nil {
+		return models.ShowMovieHall{}, nil, err
+	}
+
+	tickets, err := AssignTicketCategories(showSeatsID, choices, ticketCategories, show.MovieAgeLimit)
+	if err != nil {
+		return models.ShowMovieHall{}, nil, err
+	}
+
+	return show, tickets, nil
+}
+
+// exchangeRates loads the exchange rates needed to convert amounts between the given currencies.
+func (bs *BookingService) exchangeRates(currencies ...string) (ExchangeRates, error) {
+	return loadExchangeRates(bs.db, currencies...)
+}
+
+// setDisplayPrices sets the display price of every seat: its price converted into the display currency,
+// or its own price when no display currency is given. Display prices are only indicative; seats are
+// always charged in the currency of their hall.
+func (bs *BookingService) setDisplayPrices(showSeats []models.ShowSeat, displayCurrency string) error {
+	if displayCurrency == "" {
+		for i := range showSeats {
+			showSeats[i].DisplayPrice = showSeats[i].SeatPrice
+		}
+		return nil
+	}
+
+	displayCurrency, err := ParseCurrency(displayCurrency)
+	if err != nil {
+		return err
+	}
+
+	currencies := []string{displayCurrency}
+	for _, showSeat := range showSeats {
+		currencies = append(currencies, showSeat.SeatPrice.Currency)
+	}
+
+	rates, err := bs.exchangeRates(currencies...)
+	if err != nil {
+		return err
+	}
+
+	for i := range showSeats {
+		displayPrice, err := rates.Convert(showSeats[i].SeatPrice, displayCurrency)
+		if err != nil {
+			return err
+		}
+		showSeats[i].DisplayPrice = displayPrice
+	}
+
+	return nil
+}
+
+// fetchShowMovieHall fetches the movie, hall type, currency and age limit of a show, which promo codes may be
+// restricted to and ticket categories are checked against.
+func (bs *BookingService) fetchShowMovieHall(showID int) (models.ShowMovieHall, error) {
+	show, err := bs.db.RetrieveShowMovieHall(showID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowNotFound) {
+			return models.ShowMovieHall{}, ErrShowNotFound
+		}
+		return models.ShowMovieHall{}, fmt.Errorf("error occurred while fetching the show's movie and hall in the service section: %w", err)
+	}
+
+	return show, nil
+}
+
+// insertNewBooking creates the booking under a fresh booking reference, drawing another one whenever the
+// reference is already taken by an existing booking.
+func insertNewBooking(tx models.BookingTx, numberOfSeats int, bookingStatus string, quote models.PriceQuote, userID int) (int, string, error) {
+	for attempt := 0; attempt < maxBookingReferenceAttempts; attempt++ {
+		bookingReference, err := NewBookingReference()
+		if err != nil {
+			return 0, "", err
+		}
+
+		bookingID, err := tx.InsertNewBooking(numberOfSeats, bookingStatus, bookingReference, quote, userID)
+		if errors.Is(err, models.ErrBookingReferenceTaken) {
+			continue
+		}
+		if err != nil {
+			return 0, "", err
+		}
+
+		return bookingID, bookingReference, nil
+	}
+
+	return 0, "", fmt.Errorf("no free booking reference after %d attempts", maxBookingReferenceAttempts)
+}
+
+// checkSingleSeatGaps refuses a selection that would leave a single empty seat on its own, when the
+// rule is switched on. The seat map is read outside the caller's transaction: the selected seats are
+// locked by then, and a neighbour changing in the meantime can only make a gap appear or close.
+func (bs *BookingService) checkSingleSeatGaps(showID int, showSeatsID []int) error {
+	if !bs.settings.BlockSingleSeatGaps {
+		return nil
+	}
+
+	showSeats, err := bs.db.RetrieveShowSeats(showID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return ErrShowSeatNotFound
+		}
+		return fmt.Errorf("error occurred while fetching show seats for the gap check in the service section: %w", err)
+	}
+
+	return CheckSingleSeatGaps(showSeats, showSeatsID)
+}
+
+// isShowSeatBookable reports whether a locked show seat can be taken by the given user.
+// A seat is bookable when it is available, when its hold has lapsed, or when it is held
+// by the user themselves. Pass a userID of 0 to only accept seats nobody holds.
+func isShowSeatBookable(lockedSeat models.LockedShowSeat, userID int) bool {
+	switch lockedSeat.SeatStatus {
+	case "Available":
+		return true
+	case "Selected":
+		return lockedSeat.HoldUserID == 0 || lockedSeat.HoldUserID == userID
+	default:
+		return false
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// bookingReferencePrefix starts every booking reference, so it is recognisable on the phone and in emails.
+const bookingReferencePrefix = "CG-"
+
+// bookingReferenceLength is the number of random characters after the prefix.
+const bookingReferenceLength = 6
+
+// bookingReferenceAlphabet leaves out 0, 1, I and O, which are easily confused when read aloud or handwritten.
+// Six characters out of 32 give about a billion references, so they cannot be guessed one after another.
+const bookingReferenceAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// maxBookingReferenceAttempts is how many references are drawn before giving up on a booking whose
+// references keep colliding with existing ones.
+const maxBookingReferenceAttempts = 5
+
+// NewBookingReference draws a random booking reference, e.g., "CG-7KQ2MX", from a cryptographically
+// secure source. Uniqueness is enforced by the database; callers draw again when the reference is taken.
+//
+// Returns:
+//   - string: The new booking reference.
+//   - error: A wrapped error if the random source fails.
+func NewBookingReference() (string, error) {
+	var reference strings.Builder
+	reference.WriteString(bookingReferencePrefix)
+
+	alphabetSize := big.NewInt(int64(len(bookingReferenceAlphabet)))
+
+	for i := 0; i < bookingReferenceLength; i++ {
+		index, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate booking reference: %w", err)
+		}
+		reference.WriteByte(bookingReferenceAlphabet[index.Int64()])
+	}
+
+	return reference.String(), nil
+}
+
+// NormalizeBookingReference turns a booking reference as typed or read out by a customer into its stored
+// form. Case, spaces, dashes and the "CG-" prefix are optional, so "7kq2mx" and "cg 7KQ2MX" both give
+// "CG-7KQ2MX". Codes may themselves start with "CG", so the prefix is only dropped from longer input:
+// "CGXYZ2" gives "CG-CGXYZ2".
+//
+// Parameters:
+//   - reference (string): The booking reference entered at the helpdesk.
+//
+// Returns:
+//   - string: The booking reference in its stored form.
+//   - error: ErrInvalidBookingReference if it cannot be a booking reference.
+func NormalizeBookingReference(reference string) (string, error) {
+	code := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(reference)))
+	if len(code) > bookingReferenceLength {
+		code = strings.TrimPrefix(code, "CG")
+	}
+
+	if len(code) != bookingReferenceLength {
+		return "", ErrInvalidBookingReference
+	}
+
+	for _, char := range code {
+		if !strings.ContainsRune(bookingReferenceAlphabet, char) {
+			return "", ErrInvalidBookingReference
+		}
+	}
+
+	return bookingReferencePrefix + code, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RefundRule grants a refund percentage to cancellations made at least MinHoursBeforeShow
+// hours before the show starts.
+type RefundRule struct {
+	MinHoursBeforeShow int
+	RefundPercent      int
+}
+
+// CancellationPolicy decides how much of a booking is refunded when it is cancelled.
+// Its rules are kept sorted from the earliest cancellation window to the latest.
+type CancellationPolicy struct {
+	Rules []RefundRule
+}
+
+// ParseCancellationPolicy builds a policy from a comma-separated list of
+// "<min hours before show>:<refund percent>" rules.
+//
+// For example, "24:100,0:50" gives a full refund up to 24 hours before the show and a 50% refund
+// after that until the show starts. Cancellations that match no rule are not allowed.
+//
+// Parameters:
+//   - spec (string): The policy specification.
+//
+// Returns:
+//   - CancellationPolicy: The parsed policy.
+//   - error: ErrInvalidCancellationPolicy if the specification is malformed.
+func ParseCancellationPolicy(spec string) (CancellationPolicy, error) {
+	var policy CancellationPolicy
+
+	for _, part := range strings.Split(spec, ",") {
+		hours, percent, found := strings.Cut(strings.TrimSpace(part), ":")
+		if !found {
+			return CancellationPolicy{}, fmt.Errorf("%w: rule %q must look like <hours>:<percent>", ErrInvalidCancellationPolicy, part)
+		}
+
+		minHours, err := strconv.Atoi(hours)
+		if err != nil || minHours < 0 {
+			return CancellationPolicy{}, fmt.Errorf("%w: invalid hours in rule %q", ErrInvalidCancellationPolicy, part)
+		}
+
+		refundPercent, err := strconv.Atoi(percent)
+		if err != nil || refundPercent < 0 || refundPercent > 100 {
+			return CancellationPolicy{}, fmt.Errorf("%w: invalid refund percent in rule %q", ErrInvalidCancellationPolicy, part)
+		}
+
+		policy.Rules = append(policy.Rules, RefundRule{MinHoursBeforeShow: minHours, RefundPercent: refundPercent})
+	}
+
+	// Check the most generous windows first.
+	sort.Slice(policy.Rules, func(i, j int) bool {
+		return policy.Rules[i].MinHoursBeforeShow > policy.Rules[j].MinHoursBeforeShow
+	})
+
+	return policy, nil
+}
+
+// RefundPercent returns the share of the booking to refund for a cancellation made
+// timeUntilShow before the show starts.
+//
+// Parameters:
+//   - timeUntilShow (time.Duration): How long before the show the cancellation happens;
+//     negative once the show has started.
+//
+// Returns:
+//   - int: The refund percentage.
+//   - bool: false if the policy does not allow cancelling at that time.
+func (cp CancellationPolicy) RefundPercent(timeUntilShow time.Duration) (int, bool) {
+	// A show that has already started can never be cancelled.
+	if timeUntilShow < 0 {
+		return 0, false
+	}
+
+	for _, rule := range cp.Rules {
+		if timeUntilShow >= time.Duration(rule.MinHoursBeforeShow)*time.Hour {
+			return rule.RefundPercent, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxConcessionQuantity is the most units of a single concession item one booking may order.
+const MaxConcessionQuantity = 10
+
+// PriceConcessions checks a food and drink order against the catalogue and prices every ordered item.
+//
+// Only active items can be ordered, between 1 and MaxConcessionQuantity units each. A combo uses up the
+// stock of the items it contains, so the order is checked against the stock of every item it needs,
+// combos included. Items with unlimited stock are never short.
+//
+// Parameters:
+//   - order (map[int]int): The number of units ordered of each concession item ID.
+//   - concessionItems ([]models.ConcessionItem): The catalogue, holding at least the ordered items and
+//     the items inside the ordered combos.
+//
+// Returns:
+//   - []models.BookingConcession: One line per ordered item at its current price, in concession item ID order.
+//   - map[int]int: The units taken from the stock of every item with a stock count.
+//   - error: ErrInvalidConcessionOrder or ErrConcessionOutOfStock, wrapped with the reason.
+func PriceConcessions(order map[int]int, concessionItems []models.ConcessionItem) ([]models.BookingConcession, map[int]int, error) {
+	catalogue := make(map[int]models.ConcessionItem, len(concessionItems))
+	for _, concessionItem := range concessionItems {
+		catalogue[concessionItem.ConcessionItemID] = concessionItem
+	}
+
+	var concessions []models.BookingConcession
+	units := make(map[int]int)
+
+	for _, concessionItemID := range concessionOrderIDs(order) {
+		quantity := order[concessionItemID]
+
+		concessionItem, found := catalogue[concessionItemID]
+		if !found || !concessionItem.Active {
+			return nil, nil, fmt.Errorf("%w: item %d is not on sale", ErrInvalidConcessionOrder, concessionItemID)
+		}
+
+		if quantity < 1 || quantity > MaxConcessionQuantity {
+			return nil, nil, fmt.Errorf("%w: between 1 and %d of %s can be ordered", ErrInvalidConcessionOrder, MaxConcessionQuantity, concessionItem.Name)
+		}
+
+		concessions = append(concessions, models.BookingConcession{
+			ConcessionItemID: concessionItemID,
+			Name:             concessionItem.Name,
+			Quantity:         quantity,
+			UnitPrice:        concessionItem.Price,
+			PickupStatus:     "Pending",
+		})
+
+		if concessionItem.Category != "Combo" {
+			units[concessionItemID] += quantity
+			continue
+		}
+
+		for _, comboItem := range concessionItem.ComboItems {
+			units[comboItem.ConcessionItemID] += quantity * comboItem.Quantity
+		}
+	}
+
+	// Keep only the items whose stock is counted, and make sure there is enough of each.
+	for concessionItemID, needed := range units {
+		concessionItem, found := catalogue[concessionItemID]
+		if !found {
+			return nil, nil, fmt.Errorf("%w: item %d is not on sale", ErrInvalidConcessionOrder, concessionItemID)
+		}
+
+		if concessionItem.Stock == nil {
+			delete(units, concessionItemID)
+			continue
+		}
+
+		if *concessionItem.Stock < needed {
+			return nil, nil, fmt.Errorf("%w: only %d x %s left", ErrConcessionOutOfStock, *concessionItem.Stock, concessionItem.Name)
+		}
+	}
+
+	return concessions, units, nil
+}
+
+// addConcessionsToQuote adds one "Concession" line per ordered item to a price quote, e.g., "2 x Large popcorn",
+// and charges them on top of the seats.
+func addConcessionsToQuote(quote models.PriceQuote, concessions []models.BookingConcession) models.PriceQuote {
+	for _, concession := range concessions {
+		amount := concession.UnitPrice.Mul(concession.Quantity)
+
+		quote.Items = append(quote.Items, models.PriceQuoteItem{
+			ItemType:    "Concession",
+			Description: fmt.Sprintf("%d x %s", concession.Quantity, concession.Name),
+			Amount:      amount,
+		})
+		quote.Concessions = quote.Concessions.Add(amount)
+		quote.Total = quote.Total.Add(amount)
+	}
+
+	return quote
+}
+
+// concessionOrderIDs returns the concession item IDs of an order in ascending order, so an order is
+// always processed the same way.
+func concessionOrderIDs(order map[int]int) []int {
+	concessionItemIDs := make([]int, 0, len(order))
+	for concessionItemID := range order {
+		concessionItemIDs = append(concessionItemIDs, concessionItemID)
+	}
+	sort.Ints(concessionItemIDs)
+
+	return concessionItemIDs
+}
+
+// newConcessionItem validates the fields of a concession item entered on the admin page.
+//
+// A combo must contain at least one item, none of which may be a combo itself, and has no stock of its
+// own: it is sold while every item inside it is. Other items cannot contain anything.
+//
+// Parameters:
+//   - concessionItemID (int): The ID of the item being edited, or 0 for a new item.
+//   - comboItems (map[int]int): The quantity of each concession item ID a combo contains.
+//   - concessionItems ([]models.ConcessionItem): Every concession item, to check the combo contents against.
+//
+// Returns:
+//   - models.ConcessionItem: The concession item ready to be stored.
+//   - error: ErrInvalidConcessionItem, wrapped with the reason, if any field is invalid.
+func newConcessionItem(concessionItemID int, name, description, category string, price models.Money, stock *int, active bool, comboItems map[int]int, concessionItems []models.ConcessionItem) (models.ConcessionItem, error) {
+	concessionItem := models.ConcessionItem{
+		ConcessionItemID: concessionItemID,
+		Name:             strings.TrimSpace(name),
+		Description:      strings.TrimSpace(description),
+		Category:         category,
+		Price:            price,
+		Stock:            stock,
+		Active:           active,
+	}
+
+	if concessionItem.Name == "" || len(concessionItem.Name) > 100 {
+		return models.ConcessionItem{}, fmt.Errorf("%w: the name must be 1 to 100 characters", ErrInvalidConcessionItem)
+	}
+
+	if category != "Food" && category != "Drink" && category != "Combo" {
+		return models.ConcessionItem{}, fmt.Errorf("%w: the category must be Food, Drink or Combo", ErrInvalidConcessionItem)
+	}
+
+	if !isPrice(concessionItem.Price, models.DefaultCurrency) {
+		return models.ConcessionItem{}, fmt.Errorf("%w: the price must be a positive amount in %s", ErrInvalidConcessionItem, models.DefaultCurrency)
+	}
+
+	if stock != nil && *stock < 0 {
+		return models.ConcessionItem{}, fmt.Errorf("%w: the stock cannot be negative", ErrInvalidConcessionItem)
+	}
+
+	if category != "Combo" {
+		if len(comboItems) > 0 {
+			return models.ConcessionItem{}, fmt.Errorf("%w: only combos can contain other items", ErrInvalidConcessionItem)
+		}
+		return concessionItem, nil
+	}
+
+	if stock != nil {
+		return models.ConcessionItem{}, fmt.Errorf("%w: a combo has no stock of its own", ErrInvalidConcessionItem)
+	}
+
+	if len(comboItems) == 0 {
+		return models.ConcessionItem{}, fmt.Errorf("%w: a combo must contain at least one item", ErrInvalidConcessionItem)
+	}
+
+	catalogue := make(map[int]models.ConcessionItem, len(concessionItems))
+	for _, item := range concessionItems {
+		catalogue[item.ConcessionItemID] = item
+	}
+
+	for _, componentID := range concessionOrderIDs(comboItems) {
+		component, found := catalogue[componentID]
+		if !found || componentID == concessionItemID {
+			return models.ConcessionItem{}, fmt.Errorf("%w: item %d cannot be part of the combo", ErrInvalidConcessionItem, componentID)
+		}
+
+		if component.Category == "Combo" {
+			return models.ConcessionItem{}, fmt.Errorf("%w: a combo cannot contain another combo", ErrInvalidConcessionItem)
+		}
+
+		if comboItems[componentID] < 1 {
+			return models.ConcessionItem{}, fmt.Errorf("%w: the quantity of %s must be at least 1", ErrInvalidConcessionItem, component.Name)
+		}
+
+		concessionItem.ComboItems = append(concessionItem.ComboItems, models.ConcessionComboItem{
+			ConcessionItemID: componentID,
+			Name:             component.Name,
+			Quantity:         comboItems[componentID],
+		})
+	}
+
+	return concessionItem, nil
+}
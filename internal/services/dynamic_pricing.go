@@ -0,0 +1,219 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OccupancyRule moves seat prices by AdjustmentPercent once at least MinOccupancyPercent
+// of the seats of a show are booked.
+type OccupancyRule struct {
+	MinOccupancyPercent int
+	AdjustmentPercent   int
+}
+
+// ShowTimeRule moves seat prices by AdjustmentPercent once the show starts within
+// MaxHoursBeforeShow hours.
+type ShowTimeRule struct {
+	MaxHoursBeforeShow int
+	AdjustmentPercent  int
+}
+
+// DynamicPricingPolicy decides how far the remaining seats of a show move from their base price.
+// Occupancy rules are kept sorted from the fullest show to the emptiest and show-time rules from
+// the closest showtime to the furthest, so the first matching rule of each kind is the one that applies.
+type DynamicPricingPolicy struct {
+	OccupancyRules []OccupancyRule
+	ShowTimeRules  []ShowTimeRule
+}
+
+// ParseDynamicPricingPolicy builds a policy from two comma-separated lists of rules:
+// "<min occupancy percent>:<adjustment percent>" and "<max hours before show>:<adjustment percent>".
+//
+// For example, "50:10,80:25" and "6:15" raise prices by 10% once half of the show is booked, by 25%
+// once 80% is booked, and by a further 15% in the last six hours before the show. Adjustments may be
+// negative to discount slow shows. Either list may be empty to price on the other alone.
+//
+// Parameters:
+//   - occupancySpec (string): The occupancy rules.
+//   - showTimeSpec (string): The show-time rules.
+//
+// Returns:
+//   - DynamicPricingPolicy: The parsed policy.
+//   - error: ErrInvalidDynamicPricingPolicy if a specification is malformed.
+func ParseDynamicPricingPolicy(occupancySpec, showTimeSpec string) (DynamicPricingPolicy, error) {
+	var policy DynamicPricingPolicy
+
+	occupancyRules, err := parsePricingRules(occupancySpec, 100)
+	if err != nil {
+		return DynamicPricingPolicy{}, err
+	}
+	for _, rule := range occupancyRules {
+		policy.OccupancyRules = append(policy.OccupancyRules, OccupancyRule{MinOccupancyPercent: rule[0], AdjustmentPercent: rule[1]})
+	}
+
+	showTimeRules, err := parsePricingRules(showTimeSpec, -1)
+	if err != nil {
+		return DynamicPricingPolicy{}, err
+	}
+	for _, rule := range showTimeRules {
+		policy.ShowTimeRules = append(policy.ShowTimeRules, ShowTimeRule{MaxHoursBeforeShow: rule[0], AdjustmentPercent: rule[1]})
+	}
+
+	sort.Slice(policy.OccupancyRules, func(i, j int) bool {
+		return policy.OccupancyRules[i].MinOccupancyPercent > policy.OccupancyRules[j].MinOccupancyPercent
+	})
+	sort.Slice(policy.ShowTimeRules, func(i, j int) bool {
+		return policy.ShowTimeRules[i].MaxHoursBeforeShow < policy.ShowTimeRules[j].MaxHoursBeforeShow
+	})
+
+	return policy, nil
+}
+
+// parsePricingRules parses a comma-separated list of "<threshold>:<adjustment percent>" pairs.
+// Thresholds must not be negative nor, when maxThreshold is not -1, exceed maxThreshold.
+// Adjustments must leave a positive price, so they must be greater than -100.
+func parsePricingRules(spec string, maxThreshold int) ([][2]int, error) {
+	var rules [][2]int
+
+	if strings.TrimSpace(spec) == "" {
+		return rules, nil
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		thresholdText, adjustmentText, found := strings.Cut(strings.TrimSpace(part), ":")
+		if !found {
+			return nil, fmt.Errorf("%w: rule %q must look like <threshold>:<percent>", ErrInvalidDynamicPricingPolicy, part)
+		}
+
+		threshold, err := strconv.Atoi(thresholdText)
+		if err != nil || threshold < 0 || (maxThreshold != -1 && threshold > maxThreshold) {
+			return nil, fmt.Errorf("%w: invalid threshold in rule %q", ErrInvalidDynamicPricingPolicy, part)
+		}
+
+		adjustment, err := strconv.Atoi(adjustmentText)
+		if err != nil || adjustment <= -100 {
+			return nil, fmt.Errorf("%w: invalid adjustment percent in rule %q", ErrInvalidDynamicPricingPolicy, part)
+		}
+
+		rules = append(rules, [2]int{threshold, adjustment})
+	}
+
+	return rules, nil
+}
+
+// Adjustment returns how far the remaining seats of a show move from their base price, and a
+// human-readable reason that is stored with every price change it causes.
+//
+// Parameters:
+//   - occupancyPercent (int): The share of the seats of the show that are booked.
+//   - timeUntilShow (time.Duration): How long before the show starts.
+//
+// Returns:
+//   - int: The adjustment percentage; 0 keeps the base price.
+//   - string: Why the prices move, e.g., "Dynamic pricing: 80% booked (+25%), within 6h of the show (+15%)".
+func (dp DynamicPricingPolicy) Adjustment(occupancyPercent int, timeUntilShow time.Duration) (int, string) {
+	adjustment := 0
+	var reasons []string
+
+	for _, rule := range dp.OccupancyRules {
+		if occupancyPercent >= rule.MinOccupancyPercent {
+			adjustment += rule.AdjustmentPercent
+			reasons = append(reasons, fmt.Sprintf("%d%% booked (%+d%%)", rule.MinOccupancyPercent, rule.AdjustmentPercent))
+			break
+		}
+	}
+
+	for _, rule := range dp.ShowTimeRules {
+		if timeUntilShow <= time.Duration(rule.MaxHoursBeforeShow)*time.Hour {
+			adjustment += rule.AdjustmentPercent
+			reasons = append(reasons, fmt.Sprintf("within %dh of the show (%+d%%)", rule.MaxHoursBeforeShow, rule.AdjustmentPercent))
+			break
+		}
+	}
+
+	if len(reasons) == 0 {
+		return 0, "Dynamic pricing: base price"
+	}
+
+	return adjustment, "Dynamic pricing: " + strings.Join(reasons, ", ")
+}
+
+// DynamicPricer keeps the remaining seats of the shows that opted in to dynamic pricing priced
+// according to their occupancy and how soon they start.
+type DynamicPricer struct {
+	db     models.DBContractPricing
+	events SeatEvents
+	policy DynamicPricingPolicy
+}
+
+func NewDynamicPricer(db models.DBContractPricing, events SeatEvents, policy DynamicPricingPolicy) *DynamicPricer {
+	return &DynamicPricer{
+		db:     db,
+		events: events,
+		policy: policy,
+	}
+}
+
+// RepriceShows reprices the available seats of every upcoming show with dynamic pricing enabled,
+// within the floor and ceiling set for the show. Seats that are held or booked keep their price.
+//
+// Returns:
+//   - error: A wrapped error if the shows cannot be retrieved. A show that fails to be repriced
+//     is logged and skipped, so one show never blocks the others.
+func (dp *DynamicPricer) RepriceShows() error {
+	shows, err := dp.db.RetrieveDynamicPricingShows()
+	if err != nil {
+		return fmt.Errorf("error occurred while retrieving dynamic pricing shows: %w", err)
+	}
+
+	for _, show := range shows {
+		occupancyPercent := 0
+		if show.TotalSeats > 0 {
+			occupancyPercent = show.BookedSeats * 100 / show.TotalSeats
+		}
+
+		adjustment, reason := dp.policy.Adjustment(occupancyPercent, time.Duration(show.SecondsUntilShow)*time.Second)
+
+		updated, err := dp.db.ApplyDynamicSeatPrices(show.ShowID, adjustment, show.MinPricePercent, show.MaxPricePercent, reason)
+		if err != nil {
+			log.Printf("error occurred while repricing show %d: %v", show.ShowID, err)
+			continue
+		}
+
+		if updated > 0 {
+			dp.events.PublishSeatChange(show.ShowID, "price_changed")
+		}
+	}
+
+	return nil
+}
+
+// Run periodically reprices the shows with dynamic pricing enabled.
+// It blocks until the context is cancelled, so it is meant to be started in its own goroutine.
+//
+// Params:
+//   - ctx (context.Context): Stops the pricer when cancelled.
+//   - interval (time.Duration): How often prices are recomputed.
+func (dp *DynamicPricer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A failed run is retried on the next tick, so only log it.
+			if err := dp.RepriceShows(); err != nil {
+				log.Printf("error occurred while repricing shows: %v", err)
+			}
+		}
+	}
+}
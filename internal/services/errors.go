@@ -16,6 +16,47 @@ var ErrShowSeatNotFound = errors.New("show seat not found")
 
 var ErrShowSeatHasSelected = errors.New("show seat has just selected or booked")
 var ErrTooManySeats = errors.New("too many seats selected")
+var ErrNoAdjacentSeats = errors.New("no adjacent seats available")
+var ErrSeatHoldNotFound = errors.New("seat hold not found")
+var ErrShowSeatNotPriced = errors.New("show seat has no price yet")
+var ErrSingleSeatGap = errors.New("selection leaves a single empty seat")
+var ErrShowNotSoldOut = errors.New("show still has seats available")
+var ErrAlreadyWaitlisted = errors.New("already on the waitlist of this show")
+var ErrWaitlistEntryNotFound = errors.New("not on the waitlist of this show")
+
+var ErrPaymentDeclined = errors.New("payment declined by the provider")
+var ErrPaymentNotFound = errors.New("payment not found")
+var ErrUnknownPaymentProvider = errors.New("unknown payment provider")
+var ErrInvalidWebhookSignature = errors.New("invalid payment webhook signature")
+var ErrInvalidWebhookPayload = errors.New("invalid payment webhook payload")
+var ErrBookingNotFound = errors.New("booking not found")
+var ErrInvalidBookingReference = errors.New("invalid booking reference, expected a code like CG-7KQ2MX")
+var ErrBookingNotCancellable = errors.New("booking cannot be cancelled in its current status")
+var ErrCancellationWindowClosed = errors.New("booking can no longer be cancelled")
+var ErrInvalidRefundPercent = errors.New("invalid refund percentage, expected a percentage between 0 and 100")
+var ErrInvalidCancellationPolicy = errors.New("invalid cancellation policy")
+var ErrInvalidDynamicPricingPolicy = errors.New("invalid dynamic pricing policy")
+var ErrInvalidTicket = errors.New("invalid ticket")
+var ErrTicketNotAvailable = errors.New("ticket is only available for confirmed bookings")
+var ErrTicketAlreadyUsed = errors.New("ticket has already been used")
+var ErrReceiptNotAvailable = errors.New("receipt is only available for paid bookings")
+var ErrInvalidVATRate = errors.New("invalid VAT rate, expected a percentage between 0 and 100")
+var ErrInvalidCurrency = errors.New("invalid currency, expected a three-letter ISO 4217 code")
+var ErrExchangeRateNotFound = errors.New("no exchange rate for the currency")
+var ErrInvalidIdempotencyKey = errors.New("idempotency key must be between 1 and 255 characters")
+var ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+var ErrPromoCodeNotFound = errors.New("promo code not found")
+var ErrPromoCodeNotActive = errors.New("promo code is not valid at this time")
+var ErrPromoCodeUsageLimitReached = errors.New("promo code has reached its usage limit")
+var ErrPromoCodeNotApplicable = errors.New("promo code does not apply to this booking")
+var ErrTicketCategoryNotFound = errors.New("ticket category not found")
+var ErrInvalidTicketCategory = errors.New("invalid ticket category")
+var ErrTicketCategoryAgeRestricted = errors.New("ticket category is not allowed for this movie's age limit")
+var ErrInvalidConcessionOrder = errors.New("invalid concession order")
+var ErrConcessionOutOfStock = errors.New("concession item is out of stock")
+var ErrConcessionsAlreadyCollected = errors.New("concessions have already been collected")
+var ErrInvalidBookingFilter = errors.New("invalid booking filter, expected 'upcoming' or 'past'")
 
 var ErrAdminPageCarouselImagesNotFound = errors.New("admin Page, Carousel Images Not Found")
 var ErrAdminPageMovieNotFound = errors.New("admin Page, Movie Not Found")
@@ -24,4 +65,26 @@ var ErrDuplicatedCinemaHall = errors.New("admin page, cinema hall with this name
 var ErrCinemaHallNotFound = errors.New("admin page, cinema hall not found")
 var ErrCinemaSeatNotFound = errors.New("admin page, cinema seat not found")
 var ErrCinemaSeatAlreadyExists = errors.New("admin page, cinema seat with hall_id, seat_row, seat_number already exists")
+var ErrCinemaHallCapacityExceeded = errors.New("admin page, cinema hall has more seats than its capacity")
+var ErrCinemaHallNotEmpty = errors.New("admin page, cinema hall already has seats")
+var ErrInvalidHallSeatSpec = errors.New("admin page, invalid hall seat spec")
+var ErrInvalidHallLayout = errors.New("admin page, invalid hall layout")
+var ErrHallLayoutNotFound = errors.New("hall layout not found")
+var ErrHallLayoutIncomplete = errors.New("admin page, hall layout leaves existing seats without a place")
 var ErrShowAlreadyExists = errors.New("admin page, a show already exists at the given hall, date, and time")
+var ErrPromoCodeAlreadyExists = errors.New("admin page, a promo code with this code already exists")
+var ErrInvalidPromoCode = errors.New("admin page, invalid promo code")
+var ErrPriceRuleNotFound = errors.New("admin page, price rule not found")
+var ErrInvalidPriceRule = errors.New("admin page, invalid price rule")
+var ErrInvalidSeatPrice = errors.New("admin page, invalid seat price")
+var ErrInvalidBulkSeatPrice = errors.New("admin page, invalid bulk seat price edit")
+var ErrInvalidDynamicPricing = errors.New("admin page, invalid dynamic pricing settings")
+var ErrTicketCategoryAlreadyExists = errors.New("admin page, a ticket category with this name already exists")
+var ErrInvalidTicketCategoryDefinition = errors.New("admin page, invalid ticket category")
+var ErrConcessionItemNotFound = errors.New("admin page, concession item not found")
+var ErrConcessionItemAlreadyExists = errors.New("admin page, a concession item with this name already exists")
+var ErrConcessionItemInUse = errors.New("admin page, concession item is part of a combo or an order and can only be deactivated")
+var ErrShowHasPaidBookings = errors.New("admin page, show has paid bookings whose receipts must be kept")
+var ErrMovieHasPaidBookings = errors.New("admin page, movie has shows with paid bookings whose receipts must be kept")
+var ErrInvalidConcessionItem = errors.New("admin page, invalid concession item")
+var ErrInvalidExchangeRate = errors.New("admin page, invalid exchange rate, expected a positive decimal number")
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// currencyCode matches an ISO 4217 currency code such as "UZS" or "KZT".
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// maxExchangeRateDecimals is the number of decimals exchange rates are stored with.
+const maxExchangeRateDecimals = 10
+
+// ParseCurrency checks that code is an ISO 4217 currency code, ignoring case and surrounding spaces.
+//
+// Params:
+//   - code (string): The currency code, e.g., "usd".
+//
+// Returns:
+//   - string: The code in upper case, e.g., "USD".
+//   - error: ErrInvalidCurrency if the code is not three letters.
+func ParseCurrency(code string) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(code))
+	if !currencyCode.MatchString(currency) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+	}
+
+	return currency, nil
+}
+
+// ParseExchangeRate reads an exchange rate entered on the admin page: the number of units of the
+// default currency one unit of another currency is worth, e.g., "12650.5" for the US dollar in sums.
+//
+// Params:
+//   - rate (string): The rate as a decimal number.
+//
+// Returns:
+//   - string: The rate in the form it is stored in.
+//   - error: ErrInvalidExchangeRate if the rate is not a positive decimal number with at most 10 decimals.
+func ParseExchangeRate(rate string) (string, error) {
+	rate = strings.TrimSpace(rate)
+
+	whole, fraction, _ := strings.Cut(rate, ".")
+	if whole == "" || !isDecimalDigits(whole) || !isDecimalDigits(fraction) || len(fraction) > maxExchangeRateDecimals {
+		return "", ErrInvalidExchangeRate
+	}
+
+	value, ok := new(big.Rat).SetString(rate)
+	if !ok || value.Sign() <= 0 {
+		return "", ErrInvalidExchangeRate
+	}
+
+	return rate, nil
+}
+
+// isDecimalDigits reports whether text only contains ASCII digits.
+func isDecimalDigits(text string) bool {
+	for _, char := range text {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// exchangeRateSource is the part of a database exchange rates are read from.
+type exchangeRateSource interface {
+	RetrieveExchangeRates() ([]models.ExchangeRate, error)
+}
+
+// loadExchangeRates loads the admins' exchange rates when amounts have to be converted between any of the
+// given currencies. Nothing is loaded when they are all the default currency, which every amount set for
+// the whole chain is already in.
+func loadExchangeRates(db exchangeRateSource, currencies ...string) (ExchangeRates, error) {
+	needed := false
+	for _, currency := range currencies {
+		if currency != models.DefaultCurrency {
+			needed = true
+		}
+	}
+
+	if !needed {
+		return NewExchangeRates(nil)
+	}
+
+	exchangeRates, err := db.RetrieveExchangeRates()
+	if err != nil {
+		return nil, fmt.Errorf("error occurred while fetching exchange rates in the service section: %w", err)
+	}
+
+	return NewExchangeRates(exchangeRates)
+}
+
+// ExchangeRates converts amounts between currencies. Each currency is worth a number of units of
+// models.DefaultCurrency, so any two currencies with a rate can be converted through it.
+type ExchangeRates map[string]*big.Rat
+
+// NewExchangeRates reads the rates maintained by the admins.
+//
+// Params:
+//   - exchangeRates ([]models.ExchangeRate): The stored rates.
+//
+// Returns:
+//   - ExchangeRates: The rates by currency code; the default currency is always worth 1.
+//   - error: ErrInvalidExchangeRate, wrapped with the currency, if a stored rate is malformed.
+func NewExchangeRates(exchangeRates []models.ExchangeRate) (ExchangeRates, error) {
+	rates := ExchangeRates{models.DefaultCurrency: big.NewRat(1, 1)}
+
+	for _, exchangeRate := range exchangeRates {
+		rate, ok := new(big.Rat).SetString(exchangeRate.Rate)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidExchangeRate, exchangeRate.Currency)
+		}
+		rates[exchangeRate.Currency] = rate
+	}
+
+	return rates, nil
+}
+
+// Convert converts an amount into another currency, rounding half away from zero to the minor unit
+// of that currency. Amounts already in the currency are returned unchanged, and zero is zero in any
+// currency, so neither needs a rate.
+//
+// Params:
+//   - amount (models.Money): The amount to convert.
+//   - currency (string): The ISO 4217 code of the currency to convert to.
+//
+// Returns:
+//   - models.Money: The converted amount.
+//   - error: ErrExchangeRateNotFound, wrapped with the currency, if either currency has no rate.
+func (er ExchangeRates) Convert(amount models.Money, currency string) (models.Money, error) {
+	if amount.Currency == currency {
+		return amount, nil
+	}
+
+	if amount.IsZero() {
+		return models.NewMoney(0, currency), nil
+	}
+
+	from, found := er[amount.Currency]
+	if !found {
+		return models.Money{}, fmt.Errorf("%w: %s", ErrExchangeRateNotFound, amount.Currency)
+	}
+
+	to, found := er[currency]
+	if !found {
+		return models.Money{}, fmt.Errorf("%w: %s", ErrExchangeRateNotFound, currency)
+	}
+
+	// Go through major units: minor units of one currency, to its major units, to the default
+	// currency, to major units of the other currency, to its minor units.
+	value := new(big.Rat).SetInt64(amount.Amount)
+	value.Mul(value, from)
+	value.Quo(value, to)
+	value.Mul(value, new(big.Rat).SetFrac(pow10(models.CurrencyDecimals(currency)), pow10(models.CurrencyDecimals(amount.Currency))))
+
+	return models.NewMoney(roundRat(value), currency), nil
+}
+
+// pow10 returns 10 to the power of n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundRat rounds a fraction to the nearest integer, halves away from zero.
+func roundRat(value *big.Rat) int64 {
+	numerator := new(big.Int).Abs(value.Num())
+	quotient, remainder := new(big.Int).QuoRem(numerator, value.Denom(), new(big.Int))
+
+	if new(big.Int).Mul(remainder, big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+
+	if value.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+
+	return quotient.Int64()
+}
@@ -0,0 +1,443 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxLayoutCells caps the width and depth of a hall layout, in grid cells.
+const maxLayoutCells = 100
+
+// screenPositions are the sides of the seat map the screen can be drawn on.
+var screenPositions = []string{"Top", "Bottom"}
+
+// markerTypes are what the cells of a hall layout that hold no seat can be marked as.
+var markerTypes = []string{"Aisle", "Stairs", "Exit"}
+
+// ValidateHallLayout checks a hall layout uploaded by an admin: the grid must hold every seat and marker,
+// no two of them may share a cell, and no seat may appear twice.
+//
+// Params:
+//   - hallLayout (models.HallLayout): The uploaded layout.
+//
+// Returns:
+//   - models.HallLayout: The layout with the screen position ("Top" if left empty) and the marker types
+//     spelled the way they are stored, and the seat rows trimmed.
+//   - error: ErrInvalidHallLayout, wrapped with the first problem found.
+func ValidateHallLayout(hallLayout models.HallLayout) (models.HallLayout, error) {
+	if hallLayout.Columns < 1 || hallLayout.Columns > maxLayoutCells || hallLayout.Rows < 1 || hallLayout.Rows > maxLayoutCells {
+		return models.HallLayout{}, fmt.Errorf("%w: the grid must be between 1 and %d cells wide and deep", ErrInvalidHallLayout, maxLayoutCells)
+	}
+
+	if strings.TrimSpace(hallLayout.ScreenPosition) == "" {
+		hallLayout.ScreenPosition = screenPositions[0]
+	}
+	screenPosition, ok := canonicalName(hallLayout.ScreenPosition, screenPositions)
+	if !ok {
+		return models.HallLayout{}, fmt.Errorf("%w: the screen position must be one of %s", ErrInvalidHallLayout, strings.Join(screenPositions, ", "))
+	}
+	hallLayout.ScreenPosition = screenPosition
+
+	if len(hallLayout.Seats) == 0 {
+		return models.HallLayout{}, fmt.Errorf("%w: the layout has no seats", ErrInvalidHallLayout)
+	}
+
+	// What each taken cell holds, to report which two things overlap.
+	cells := make(map[[2]int]string)
+	take := func(x, y int, what string) error {
+		if x < 1 || x > hallLayout.Columns || y < 1 || y > hallLayout.Rows {
+			return fmt.Errorf("%w: %s is outside the grid", ErrInvalidHallLayout, what)
+		}
+		if taken, found := cells[[2]int{x, y}]; found {
+			return fmt.Errorf("%w: %s overlaps %s", ErrInvalidHallLayout, what, taken)
+		}
+		cells[[2]int{x, y}] = what
+		return nil
+	}
+
+	seats := make([]models.HallLayoutSeat, 0, len(hallLayout.Seats))
+	seen := make(map[string]bool, len(hallLayout.Seats))
+	for _, seat := range hallLayout.Seats {
+		seat.SeatRow = strings.TrimSpace(seat.SeatRow)
+		seat.SeatType = strings.TrimSpace(seat.SeatType)
+
+		label := fmt.Sprintf("seat %s%d", seat.SeatRow, seat.SeatNumber)
+		if seat.SeatRow == "" || len(seat.SeatRow) > 10 || seat.SeatNumber < 1 || seat.SeatType == "" {
+			return models.HallLayout{}, fmt.Errorf("%w: %s needs a row of at most 10 characters, a positive number and a type", ErrInvalidHallLayout, label)
+		}
+		if seat.Angle < -90 || seat.Angle > 90 {
+			return models.HallLayout{}, fmt.Errorf("%w: %s must be turned between -90 and 90 degrees", ErrInvalidHallLayout, label)
+		}
+		if seen[label] {
+			return models.HallLayout{}, fmt.Errorf("%w: %s appears twice", ErrInvalidHallLayout, label)
+		}
+		seen[label] = true
+
+		if err := take(seat.GridX, seat.GridY, label); err != nil {
+			return models.HallLayout{}, err
+		}
+		seats = append(seats, seat)
+	}
+	hallLayout.Seats = seats
+
+	markers := make([]models.HallLayoutMarker, 0, len(hallLayout.Markers))
+	for _, marker := range hallLayout.Markers {
+		markerType, ok := canonicalName(marker.MarkerType, markerTypes)
+		if !ok {
+			return models.HallLayout{}, fmt.Errorf("%w: a marker must be one of %s", ErrInvalidHallLayout, strings.Join(markerTypes, ", "))
+		}
+		marker.MarkerType = markerType
+
+		// A marker covers a single cell unless it says otherwise.
+		if marker.Width == 0 {
+			marker.Width = 1
+		}
+		if marker.Height == 0 {
+			marker.Height = 1
+		}
+		if marker.Width < 0 || marker.Height < 0 {
+			return models.HallLayout{}, fmt.Errorf("%w: a marker cannot have a negative size", ErrInvalidHallLayout)
+		}
+
+		what := fmt.Sprintf("the %s at %d,%d", strings.ToLower(markerType), marker.GridX, marker.GridY)
+		for y := marker.GridY; y < marker.GridY+marker.Height; y++ {
+			for x := marker.GridX; x < marker.GridX+marker.Width; x++ {
+				if err := take(x, y, what); err != nil {
+					return models.HallLayout{}, err
+				}
+			}
+		}
+		markers = append(markers, marker)
+	}
+	hallLayout.Markers = markers
+
+	return hallLayout, nil
+}
+
+// canonicalName finds name among names, ignoring case and surrounding spaces.
+func canonicalName(name string, names []string) (string, bool) {
+	for _, candidate := range names {
+		if strings.EqualFold(strings.TrimSpace(name), candidate) {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+// BuildSeatGrid lays the seats of a show out on the grid of their hall, so clients can draw the seat map
+// cell by cell. A hall without an uploaded layout gets one derived from its seats: a grid row per seat
+// row, front row first, and a column per seat number, so a jump in the numbering shows as an empty column.
+//
+// Params:
+//   - hallLayout (models.HallLayout): The layout of the hall, or the zero value if it has none.
+//   - showSeats ([]models.ShowSeat): Every seat of the show.
+//
+// Returns:
+//   - models.SeatGrid: The grid; seats without a place on an uploaded layout are left out.
+func BuildSeatGrid(hallLayout models.HallLayout, showSeats []models.ShowSeat) models.SeatGrid {
+	if hallLayout.Columns == 0 {
+		hallLayout, showSeats = deriveHallLayout(showSeats)
+	}
+
+	grid := models.SeatGrid{
+		Columns:        hallLayout.Columns,
+		Rows:           hallLayout.Rows,
+		ScreenPosition: hallLayout.ScreenPosition,
+		Cells:          make([][]models.SeatGridCell, hallLayout.Rows),
+	}
+
+	for y := range grid.Cells {
+		grid.Cells[y] = make([]models.SeatGridCell, hallLayout.Columns)
+		for x := range grid.Cells[y] {
+			grid.Cells[y][x].Kind = "Empty"
+		}
+	}
+
+	inGrid := func(x, y int) bool {
+		return x >= 1 && x <= grid.Columns && y >= 1 && y <= grid.Rows
+	}
+
+	for _, marker := range hallLayout.Markers {
+		for y := marker.GridY; y < marker.GridY+marker.Height; y++ {
+			for x := marker.GridX; x < marker.GridX+marker.Width; x++ {
+				if inGrid(x, y) {
+					grid.Cells[y-1][x-1].Kind = marker.MarkerType
+				}
+			}
+		}
+	}
+
+	for i := range showSeats {
+		if inGrid(showSeats[i].GridX, showSeats[i].GridY) {
+			grid.Cells[showSeats[i].GridY-1][showSeats[i].GridX-1] = models.SeatGridCell{Kind: "Seat", Seat: &showSeats[i]}
+		}
+	}
+
+	return grid
+}
+
+// deriveHallLayout places the seats of a hall without an uploaded layout, returning a copy of the seats.
+func deriveHallLayout(showSeats []models.ShowSeat) (models.HallLayout, []models.ShowSeat) {
+	hallLayout := models.HallLayout{ScreenPosition: screenPositions[0]}
+	if len(showSeats) == 0 {
+		return hallLayout, nil
+	}
+
+	rowIndex := make(map[string]int)
+	var rows []string
+	minNumber, maxNumber := showSeats[0].SeatNumber, showSeats[0].SeatNumber
+	for _, showSeat := range showSeats {
+		if _, found := rowIndex[showSeat.SeatRow]; !found {
+			rowIndex[showSeat.SeatRow] = 0
+			rows = append(rows, showSeat.SeatRow)
+		}
+		minNumber = min(minNumber, showSeat.SeatNumber)
+		maxNumber = max(maxNumber, showSeat.SeatNumber)
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rowLess(rows[i], rows[j]) })
+	for i, row := range rows {
+		rowIndex[row] = i + 1
+	}
+
+	placed := make([]models.ShowSeat, len(showSeats))
+	for i, showSeat := range showSeats {
+		showSeat.GridX = showSeat.SeatNumber - minNumber + 1
+		showSeat.GridY = rowIndex[showSeat.SeatRow]
+		placed[i] = showSeat
+	}
+
+	hallLayout.Columns = maxNumber - minNumber + 1
+	hallLayout.Rows = len(rows)
+
+	return hallLayout, placed
+}
+
+// UploadHallLayout uploads the seat map of a hall, creating all its seats in one go. Existing seats are
+// moved to their new place and keep their show seats and bookings; every one of them must be placed.
+//
+// Params:
+//   - hallLayout (models.HallLayout): The layout, identified by HallID.
+//
+// Returns:
+//   - int: The number of seats created.
+//   - error: ErrInvalidHallLayout, ErrCinemaHallNotFound, ErrHallLayoutIncomplete, ErrCinemaHallCapacityExceeded,
+//     or a wrapped error.
+func (as *AdminService) UploadHallLayout(hallLayout models.HallLayout) (int, error) {
+	hallLayout, err := ValidateHallLayout(hallLayout)
+	if err != nil {
+		return 0, err
+	}
+
+	created, err := as.db.ReplaceHallLayout(hallLayout)
+	if err != nil {
+		if errors.Is(err, models.ErrCinemaHallNotFound) {
+			return 0, ErrCinemaHallNotFound
+		}
+		if errors.Is(err, models.ErrHallLayoutIncomplete) {
+			return 0, ErrHallLayoutIncomplete
+		}
+		if errors.Is(err, models.ErrCinemaHallCapacityExceeded) {
+			return 0, ErrCinemaHallCapacityExceeded
+		}
+		return 0, fmt.Errorf("error occurred while uploading the hall layout: %w", err)
+	}
+
+	return created, nil
+}
+
+// FetchHallLayout retrieves the seat map of a hall as it was uploaded.
+//
+// Params:
+//   - hallID (int): The ID of the cinema hall.
+//
+// Returns:
+//   - models.HallLayout: The layout with every seat of the hall.
+//   - error: ErrHallLayoutNotFound if no layout has been uploaded for the hall, or a wrapped error.
+func (as *AdminService) FetchHallLayout(hallID int) (models.HallLayout, error) {
+	hallLayout, err := as.db.RetrieveHallLayout(hallID)
+	if err != nil {
+		if errors.Is(err, models.ErrHallLayoutNotFound) {
+			return models.HallLayout{}, ErrHallLayoutNotFound
+		}
+		return models.HallLayout{}, fmt.Errorf("error occurred while fetching the hall layout: %w", err)
+	}
+
+	return hallLayout, nil
+}
+
+// FetchSeatGrid lays the seats of a show out on the seat map of its hall.
+//
+// Params:
+//   - showID (int): The ID of the show.
+//   - showSeats ([]models.ShowSeat): The seats of the show, as returned by FetchShowSeats.
+//
+// Returns:
+//   - models.SeatGrid: The grid to draw; derived from the seats if the hall has no layout.
+//   - error: A wrapped error if the layout cannot be retrieved.
+func (bs *BookingService) FetchSeatGrid(showID int, showSeats []models.ShowSeat) (models.SeatGrid, error) {
+	hallLayout, err := bs.db.RetrieveShowHallLayout(showID)
+	if err != nil && !errors.Is(err, models.ErrHallLayoutNotFound) {
+		return models.SeatGrid{}, fmt.Errorf("error occurred while fetching the hall layout in the service section: %w", err)
+	}
+
+	return BuildSeatGrid(hallLayout, showSeats), nil
+}
+
+// GenerateHallLayout lays out a rectangular hall from a spec: one row of seats per letter from RowFrom to
+// RowTo, front row first, numbered from SeatFrom to SeatTo. Seats are "Standard", apart from the VIP rows
+// and the accessible seats; an accessible seat in a VIP row is accessible.
+//
+// Params:
+//   - spec (models.HallSeatSpec): What to generate.
+//
+// Returns:
+//   - models.HallLayout: The layout with every seat placed, the screen at the top.
+//   - error: ErrInvalidHallSeatSpec, wrapped with the problem, if the rows are not single letters in
+//     order, the seat numbers are not a range of positive numbers, the hall would be over 100 cells wide
+//     or deep, or a VIP row or an accessible seat lies outside the hall.
+func GenerateHallLayout(spec models.HallSeatSpec) (models.HallLayout, error) {
+	rowFrom, rowTo := strings.ToUpper(strings.TrimSpace(spec.RowFrom)), strings.ToUpper(strings.TrimSpace(spec.RowTo))
+	if !isRowLetter(rowFrom) || !isRowLetter(rowTo) || rowFrom > rowTo {
+		return models.HallLayout{}, fmt.Errorf("%w: rows must run from one letter to a later one, e.g., A to M", ErrInvalidHallSeatSpec)
+	}
+
+	if spec.SeatFrom < 1 || spec.SeatTo < spec.SeatFrom || spec.SeatTo-spec.SeatFrom+1 > maxLayoutCells {
+		return models.HallLayout{}, fmt.Errorf("%w: seat numbers must run from a positive number up to at most %d seats", ErrInvalidHallSeatSpec, maxLayoutCells)
+	}
+
+	inHall := func(row string) bool {
+		return isRowLetter(row) && row >= rowFrom && row <= rowTo
+	}
+
+	vipRows := make(map[string]bool, len(spec.VIPRows))
+	for _, row := range spec.VIPRows {
+		row = strings.ToUpper(strings.TrimSpace(row))
+		if !inHall(row) {
+			return models.HallLayout{}, fmt.Errorf("%w: VIP row %q is not in the hall", ErrInvalidHallSeatSpec, row)
+		}
+		vipRows[row] = true
+	}
+
+	accessibleSeats := make(map[string]bool, len(spec.AccessibleSeats))
+	for _, label := range spec.AccessibleSeats {
+		label = strings.ToUpper(strings.TrimSpace(label))
+
+		if len(label) < 2 || !inHall(label[:1]) {
+			return models.HallLayout{}, fmt.Errorf("%w: accessible seat %q is not in the hall", ErrInvalidHallSeatSpec, label)
+		}
+		number, err := strconv.Atoi(label[1:])
+		if err != nil || number < spec.SeatFrom || number > spec.SeatTo {
+			return models.HallLayout{}, fmt.Errorf("%w: accessible seat %q is not in the hall", ErrInvalidHallSeatSpec, label)
+		}
+		accessibleSeats[fmt.Sprintf("%s%d", label[:1], number)] = true
+	}
+
+	hallLayout := models.HallLayout{
+		HallID:         spec.HallID,
+		Columns:        spec.SeatTo - spec.SeatFrom + 1,
+		Rows:           int(rowTo[0]-rowFrom[0]) + 1,
+		ScreenPosition: screenPositions[0],
+	}
+
+	for y := 1; y <= hallLayout.Rows; y++ {
+		row := string(rowFrom[0] + byte(y-1))
+
+		for number := spec.SeatFrom; number <= spec.SeatTo; number++ {
+			seatType := "Standard"
+			if accessibleSeats[fmt.Sprintf("%s%d", row, number)] {
+				seatType = "Accessible"
+			} else if vipRows[row] {
+				seatType = "VIP"
+			}
+
+			hallLayout.Seats = append(hallLayout.Seats, models.HallLayoutSeat{
+				SeatRow:    row,
+				SeatNumber: number,
+				SeatType:   seatType,
+				GridX:      number - spec.SeatFrom + 1,
+				GridY:      y,
+			})
+		}
+	}
+
+	return hallLayout, nil
+}
+
+// isRowLetter reports whether row is a single letter from A to Z.
+func isRowLetter(row string) bool {
+	return len(row) == 1 && row[0] >= 'A' && row[0] <= 'Z'
+}
+
+// GenerateHallSeats fills an empty hall with the seats of a spec, in one go, and gives it the matching layout.
+//
+// Params:
+//   - spec (models.HallSeatSpec): What to generate, identified by HallID.
+//
+// Returns:
+//   - int: The number of seats created.
+//   - error: ErrInvalidHallSeatSpec, ErrCinemaHallNotFound, ErrCinemaHallNotEmpty, ErrCinemaHallCapacityExceeded,
+//     or a wrapped error.
+func (as *AdminService) GenerateHallSeats(spec models.HallSeatSpec) (int, error) {
+	hallLayout, err := GenerateHallLayout(spec)
+	if err != nil {
+		return 0, err
+	}
+
+	err = as.db.InsertHallSeats(hallLayout)
+	if err != nil {
+		if errors.Is(err, models.ErrCinemaHallNotFound) {
+			return 0, ErrCinemaHallNotFound
+		}
+		if errors.Is(err, models.ErrCinemaHallNotEmpty) {
+			return 0, ErrCinemaHallNotEmpty
+		}
+		if errors.Is(err, models.ErrCinemaHallCapacityExceeded) {
+			return 0, ErrCinemaHallCapacityExceeded
+		}
+		return 0, fmt.Errorf("error occurred while generating hall seats: %w", err)
+	}
+
+	return len(hallLayout.Seats), nil
+}
+
+// CloneHallSeats uses a hall as a template: its seats and layout are copied into an empty hall, in one go.
+//
+// Params:
+//   - fromHallID (int): The ID of the hall to copy.
+//   - toHallID (int): The ID of the empty hall to fill.
+//
+// Returns:
+//   - int: The number of seats created.
+//   - error: ErrCinemaHallNotEmpty if both IDs are the same hall or the other hall has seats, ErrCinemaHallNotFound,
+//     ErrCinemaSeatNotFound if the hall to copy has no seats, ErrCinemaHallCapacityExceeded, or a wrapped error.
+func (as *AdminService) CloneHallSeats(fromHallID, toHallID int) (int, error) {
+	// A hall cannot be copied into itself; it is not empty, or has nothing to copy.
+	if fromHallID == toHallID {
+		return 0, ErrCinemaHallNotEmpty
+	}
+
+	created, err := as.db.CloneHallSeats(fromHallID, toHallID)
+	if err != nil {
+		if errors.Is(err, models.ErrCinemaHallNotFound) {
+			return 0, ErrCinemaHallNotFound
+		}
+		if errors.Is(err, models.ErrCinemaSeatNotFound) {
+			return 0, ErrCinemaSeatNotFound
+		}
+		if errors.Is(err, models.ErrCinemaHallNotEmpty) {
+			return 0, ErrCinemaHallNotEmpty
+		}
+		if errors.Is(err, models.ErrCinemaHallCapacityExceeded) {
+			return 0, ErrCinemaHallCapacityExceeded
+		}
+		return 0, fmt.Errorf("error occurred while cloning hall seats: %w", err)
+	}
+
+	return created, nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// maxIdempotencyKeyLength matches the size of the idempotency_key column.
+const maxIdempotencyKeyLength = 255
+
+type IdempotencyServiceInterface interface {
+	BeginRequest(userID int, key string, requestBody []byte) (models.IdempotentResponse, bool, error)
+	CompleteRequest(userID int, key, claimToken string, statusCode int, responseBody []byte) error
+	AbandonRequest(userID int, key, claimToken string) error
+}
+
+// IdempotencyService remembers the response given to each Idempotency-Key a user sends, so that a
+// retried request is answered with the original response instead of being processed again.
+//
+// A key is remembered for ttlHours once its request has finished. While the request runs, the key is
+// only leased for leaseSeconds: a request that dies without storing a response, e.g., when the server
+// is restarted, frees its key once the lease runs out instead of blocking every retry for ttlHours.
+type IdempotencyService struct {
+	db           models.DBContractIdempotency
+	ttlHours     int
+	leaseSeconds int
+}
+
+func NewIdempotencyService(db models.DBContractIdempotency, ttlHours, leaseSeconds int) *IdempotencyService {
+	return &IdempotencyService{db: db, ttlHours: ttlHours, leaseSeconds: leaseSeconds}
+}
+
+// BeginRequest is called before a request carrying an idempotency key is processed.
+//
+// The first request with a key claims it and should be processed normally. A retry of a finished
+// request gets the stored response back to replay. A retry that arrives while the first request is
+// still running, or a key reused with a different request body, is rejected.
+//
+// Params:
+//   - userID (int): The ID of the user sending the request.
+//   - key (string): The value of the Idempotency-Key header.
+//   - requestBody ([]byte): The raw request body.
+//
+// Returns:
+//   - models.IdempotentResponse: The stored response when the request is a retry, or the claim token
+//     the response must be stored with when the request must be processed.
+//   - bool: true if the stored response must be replayed, false if the request must be processed.
+//   - error: ErrInvalidIdempotencyKey, ErrIdempotencyKeyInProgress, ErrIdempotencyKeyReused, or a wrapped error.
+func (is *IdempotencyService) BeginRequest(userID int, key string, requestBody []byte) (models.IdempotentResponse, bool, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return models.IdempotentResponse{}, false, ErrInvalidIdempotencyKey
+	}
+
+	// Fingerprint the body so a key cannot be reused for a different booking.
+	sum := sha256.Sum256(requestBody)
+	requestHash := hex.EncodeToString(sum[:])
+
+	claimToken, err := is.db.ClaimIdempotencyKey(userID, key, requestHash, is.ttlHours, is.leaseSeconds)
+	if err != nil {
+		return models.IdempotentResponse{}, false, fmt.Errorf("error occurred while claiming idempotency key in the service section: %w", err)
+	}
+
+	// This is the first request with the key.
+	if claimToken != "" {
+		return models.IdempotentResponse{ClaimToken: claimToken}, false, nil
+	}
+
+	stored, err := is.db.RetrieveIdempotentResponse(userID, key)
+	if err != nil {
+		// The first request failed and released the key in the meantime; let the client retry.
+		if errors.Is(err, models.ErrIdempotencyKeyNotFound) {
+			return models.IdempotentResponse{}, false, ErrIdempotencyKeyInProgress
+		}
+		return models.IdempotentResponse{}, false, fmt.Errorf("error occurred while retrieving idempotent response in the service section: %w", err)
+	}
+
+	if stored.RequestHash != requestHash {
+		return models.IdempotentResponse{}, false, ErrIdempotencyKeyReused
+	}
+
+	// The first request has not produced a response yet.
+	if stored.StatusCode == 0 {
+		return models.IdempotentResponse{}, false, ErrIdempotencyKeyInProgress
+	}
+
+	return stored, true, nil
+}
+
+// CompleteRequest stores the response of a request that claimed an idempotency key. A request that
+// outlived its lease and lost the key to a retry stores nothing, so it never overwrites the retry's response.
+//
+// Params:
+//   - userID (int): The ID of the user who sent the request.
+//   - key (string): The value of the Idempotency-Key header.
+//   - claimToken (string): The claim token returned by BeginRequest.
+//   - statusCode (int): The HTTP status of the response.
+//   - responseBody ([]byte): The body of the response.
+//
+// Returns:
+//   - error: A wrapped error if the response cannot be stored, wrapping models.ErrIdempotencyKeyNotFound
+//     if the key is no longer held by this request.
+func (is *IdempotencyService) CompleteRequest(userID int, key, claimToken string, statusCode int, responseBody []byte) error {
+	err := is.db.SaveIdempotentResponse(userID, key, claimToken, statusCode, responseBody)
+	if err != nil {
+		return fmt.Errorf("error occurred while saving idempotent response in the service section: %w", err)
+	}
+
+	return nil
+}
+
+// AbandonRequest releases an idempotency key whose request failed unexpectedly, so the client
+// can retry it instead of being replayed the failure. A key a retry has claimed since is left alone.
+//
+// Params:
+//   - userID (int): The ID of the user who sent the request.
+//   - key (string): The value of the Idempotency-Key header.
+//   - claimToken (string): The claim token returned by BeginRequest.
+//
+// Returns:
+//   - error: A wrapped error if the key cannot be released.
+func (is *IdempotencyService) AbandonRequest(userID int, key, claimToken string) error {
+	err := is.db.DeleteIdempotencyKey(userID, key, claimToken)
+	if err != nil {
+		return fmt.Errorf("error occurred while releasing idempotency key in the service section: %w", err)
+	}
+
+	return nil
+}
+
+// RunIdempotencyKeySweeper periodically deletes the idempotency keys that are no longer remembered.
+// It blocks until the context is cancelled, so it is meant to be started in its own goroutine.
+//
+// Params:
+//   - ctx (context.Context): Stops the sweeper when cancelled.
+//   - interval (time.Duration): How often expired keys are swept.
+func (is *IdempotencyService) RunIdempotencyKeySweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A failed sweep is retried on the next tick, so only log it.
+			if _, err := is.db.DeleteExpiredIdempotencyKeys(is.ttlHours, is.leaseSeconds); err != nil {
+				log.Printf("error occurred while sweeping expired idempotency keys: %v", err)
+			}
+		}
+	}
+}
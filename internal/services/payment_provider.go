@@ -0,0 +1,143 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PaymentWebhookEvent is the outcome of a payment reported by the provider through a webhook.
+type PaymentWebhookEvent struct {
+	RemoteTransactionID string `json:"transaction_id"`
+	Status              string `json:"status"` // "succeeded" or "failed"
+}
+
+// PaymentProvider is the contract every payment gateway integration must satisfy.
+type PaymentProvider interface {
+	// Name identifies the provider; it is stored as the payment method.
+	Name() string
+	// Authorize reserves the amount on the customer's payment method and returns the
+	// provider's transaction ID.
+	Authorize(bookingID int, amount models.Money) (string, error)
+	// Capture collects a previously authorized amount. Capturing a transaction that was already
+	// captured must succeed without collecting it again, so an interrupted capture can be retried.
+	Capture(remoteTransactionID string, amount models.Money) error
+	// Void releases an authorization that will not be captured.
+	Void(remoteTransactionID string) error
+	// Refund returns part or all of a captured amount and returns the provider's refund ID. The
+	// provider pays each refund key out at most once, so a refund can be retried safely.
+	Refund(remoteTransactionID string, amount models.Money, refundKey string) (string, error)
+	// VerifyWebhook checks the signature of a webhook delivery and decodes its event.
+	VerifyWebhook(payload []byte, signature string) (PaymentWebhookEvent, error)
+}
+
+// NewPaymentProvider builds the payment provider selected in the configuration. Only the fake provider
+// is integrated so far; it has to be asked for by name, so a deployment never takes payments through it
+// by accident.
+//
+// Params:
+//   - name (string): The configured provider, e.g., "fake".
+//   - webhookSecret (string): The secret the provider signs its webhooks with.
+//
+// Returns:
+//   - PaymentProvider: The provider.
+//   - error: ErrUnknownPaymentProvider if no provider by that name is integrated.
+func NewPaymentProvider(name, webhookSecret string) (PaymentProvider, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "fake":
+		return NewFakePaymentProvider(webhookSecret), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPaymentProvider, name)
+	}
+}
+
+// FakePaymentProvider is a deterministic PaymentProvider for local development and tests.
+// It never talks to the network: every call succeeds or fails based only on its arguments,
+// and webhooks are signed with an HMAC-SHA256 of the payload using a shared secret.
+type FakePaymentProvider struct {
+	webhookSecret []byte
+}
+
+func NewFakePaymentProvider(webhookSecret string) *FakePaymentProvider {
+	return &FakePaymentProvider{webhookSecret: []byte(webhookSecret)}
+}
+
+// Name returns the payment method name stored for payments made through the fake provider.
+func (fp *FakePaymentProvider) Name() string {
+	return "Fake"
+}
+
+// Authorize returns a transaction ID derived from the booking ID and amount.
+// Amounts that are not positive are declined.
+func (fp *FakePaymentProvider) Authorize(bookingID int, amount models.Money) (string, error) {
+	if !amount.IsPositive() {
+		return "", ErrPaymentDeclined
+	}
+
+	return fmt.Sprintf("fake_txn_%d_%d", bookingID, amount.Amount), nil
+}
+
+// Capture succeeds for every transaction ID issued by the fake provider.
+func (fp *FakePaymentProvider) Capture(remoteTransactionID string, amount models.Money) error {
+	if !strings.HasPrefix(remoteTransactionID, "fake_txn_") {
+		return ErrPaymentNotFound
+	}
+
+	return nil
+}
+
+// Void succeeds for every transaction ID issued by the fake provider.
+func (fp *FakePaymentProvider) Void(remoteTransactionID string) error {
+	if !strings.HasPrefix(remoteTransactionID, "fake_txn_") {
+		return ErrPaymentNotFound
+	}
+
+	return nil
+}
+
+// Refund returns a refund ID derived from the transaction ID and the refunded amount, so a retried
+// refund key gets the same refund ID back.
+func (fp *FakePaymentProvider) Refund(remoteTransactionID string, amount models.Money, refundKey string) (string, error) {
+	if !strings.HasPrefix(remoteTransactionID, "fake_txn_") {
+		return "", ErrPaymentNotFound
+	}
+
+	return fmt.Sprintf("fake_refund_%s_%d", strings.TrimPrefix(remoteTransactionID, "fake_txn_"), amount.Amount), nil
+}
+
+// VerifyWebhook checks that the signature is the hex-encoded HMAC-SHA256 of the payload and
+// decodes the payload as a PaymentWebhookEvent.
+func (fp *FakePaymentProvider) VerifyWebhook(payload []byte, signature string) (PaymentWebhookEvent, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, fp.sign(payload)) {
+		return PaymentWebhookEvent{}, ErrInvalidWebhookSignature
+	}
+
+	var event PaymentWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return PaymentWebhookEvent{}, ErrInvalidWebhookPayload
+	}
+
+	if event.RemoteTransactionID == "" || (event.Status != "succeeded" && event.Status != "failed") {
+		return PaymentWebhookEvent{}, ErrInvalidWebhookPayload
+	}
+
+	return event, nil
+}
+
+// SignWebhook returns the signature the fake provider expects for a webhook payload.
+// It lets local tools and tests simulate webhook deliveries.
+func (fp *FakePaymentProvider) SignWebhook(payload []byte) string {
+	return hex.EncodeToString(fp.sign(payload))
+}
+
+// sign computes the HMAC-SHA256 of the payload with the webhook secret.
+func (fp *FakePaymentProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, fp.webhookSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// newPriceRule validates the fields of a price rule entered on the admin page and converts them
+// into the form they are stored in.
+//
+// Start times are given as "HH:MM" and must be given together; a band whose end is earlier than its
+// start wraps past midnight (e.g., "22:00" to "02:00" for late-night shows). The price must be in the
+// currency prices are kept in, like seat prices.
+//
+// Returns:
+//   - models.PriceRule: The price rule ready to be stored.
+//   - error: ErrInvalidPriceRule, wrapped with the reason, if any field is invalid.
+func newPriceRule(ruleName, hallType, seatType string, dayOfWeek *int, startTimeFrom, startTimeUntil string, price models.Money) (models.PriceRule, error) {
+	priceRule := models.PriceRule{
+		RuleName:       strings.TrimSpace(ruleName),
+		HallType:       strings.TrimSpace(hallType),
+		SeatType:       strings.TrimSpace(seatType),
+		DayOfWeek:      dayOfWeek,
+		StartTimeFrom:  strings.TrimSpace(startTimeFrom),
+		StartTimeUntil: strings.TrimSpace(startTimeUntil),
+		Price:          price,
+	}
+
+	if priceRule.RuleName == "" {
+		return models.PriceRule{}, fmt.Errorf("%w: the rule needs a name", ErrInvalidPriceRule)
+	}
+
+	if dayOfWeek != nil && (*dayOfWeek < 0 || *dayOfWeek > 6) {
+		return models.PriceRule{}, fmt.Errorf("%w: the day of the week must be from 0 (Sunday) to 6 (Saturday)", ErrInvalidPriceRule)
+	}
+
+	if (priceRule.StartTimeFrom == "") != (priceRule.StartTimeUntil == "") {
+		return models.PriceRule{}, fmt.Errorf("%w: a start time band needs both a start and an end", ErrInvalidPriceRule)
+	}
+
+	for _, clock := range []string{priceRule.StartTimeFrom, priceRule.StartTimeUntil} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return models.PriceRule{}, fmt.Errorf("%w: start times must be given as HH:MM", ErrInvalidPriceRule)
+		}
+	}
+
+	if priceRule.StartTimeFrom != "" && priceRule.StartTimeFrom == priceRule.StartTimeUntil {
+		return models.PriceRule{}, fmt.Errorf("%w: a start time band cannot be empty", ErrInvalidPriceRule)
+	}
+
+	if !isPrice(priceRule.Price, models.DefaultCurrency) {
+		return models.PriceRule{}, fmt.Errorf("%w: the price must be a positive amount in %s", ErrInvalidPriceRule, models.DefaultCurrency)
+	}
+
+	return priceRule, nil
+}
+
+// MatchPriceRule finds the price rule that prices a seat of a show.
+//
+// A rule matches when every criterion it sets agrees with the seat: the hall type, the seat type, the
+// weekday of the show and the band its start time falls in. When several rules match, the one that
+// sets the most criteria wins, so a "VIP on Friday night" rule beats a plain "VIP" rule. Ties go to
+// the newest rule.
+//
+// Parameters:
+//   - priceRules ([]models.PriceRule): Every price rule, as returned by RetrieveAllPriceRules.
+//   - hallType (string): The type of the hall the show plays in.
+//   - seatType (string): The type of the seat.
+//   - showDate (time.Time): The date of the show.
+//   - startTime (time.Time): The start time of the show.
+//
+// Returns:
+//   - models.PriceRule: The matching rule.
+//   - bool: false if no rule matches, in which case the seat is left unpriced.
+func MatchPriceRule(priceRules []models.PriceRule, hallType, seatType string, showDate, startTime time.Time) (models.PriceRule, bool) {
+	weekday := int(showDate.Weekday())
+	clock := startTime.Format("15:04")
+
+	var best models.PriceRule
+	bestScore := -1
+
+	for _, priceRule := range priceRules {
+		score := 0
+
+		if priceRule.HallType != "" {
+			if !strings.EqualFold(priceRule.HallType, hallType) {
+				continue
+			}
+			score++
+		}
+
+		if priceRule.SeatType != "" {
+			if !strings.EqualFold(priceRule.SeatType, seatType) {
+				continue
+			}
+			score++
+		}
+
+		if priceRule.DayOfWeek != nil {
+			if *priceRule.DayOfWeek != weekday {
+				continue
+			}
+			score++
+		}
+
+		if priceRule.StartTimeFrom != "" {
+			if !inStartTimeBand(clock, priceRule.StartTimeFrom, priceRule.StartTimeUntil) {
+				continue
+			}
+			score++
+		}
+
+		if score > bestScore || (score == bestScore && priceRule.PriceRuleID > best.PriceRuleID) {
+			best = priceRule
+			bestScore = score
+		}
+	}
+
+	return best, bestScore >= 0
+}
+
+// inStartTimeBand reports whether an "HH:MM" clock falls in the band [from, until), wrapping past
+// midnight when until is earlier than from. Zero-padded clocks compare correctly as strings.
+func inStartTimeBand(clock, from, until string) bool {
+	if from < until {
+		return clock >= from && clock < until
+	}
+
+	return clock >= from || clock < until
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"fmt"
+)
+
+// buildPriceQuote turns the selected show seats into an itemised price quote.
+//
+// Every seat becomes its own line at the price stored in show_seat.price, scaled by the price
+// percentage of the ticket category it is sold as, and the booking fee is added as a single line
+// for all seats.
+//
+// Parameters:
+//   - showID (int): The ID of the show the seats belong to.
+//   - showSeats ([]models.ShowSeat): The seats selected by the customer.
+//   - tickets (map[int]models.TicketCategory): The ticket category of every show seat ID, as returned
+//     by AssignTicketCategories.
+//   - feePerSeat (models.Money): The booking fee charged for every seat.
+//
+// Returns:
+//   - models.PriceQuote: The itemised quote with its subtotal, fees and total.
+//   - error: ErrShowSeatNotPriced if any seat has not been given a price yet.
+func buildPriceQuote(showID int, showSeats []models.ShowSeat, tickets map[int]models.TicketCategory, feePerSeat models.Money) (models.PriceQuote, error) {
+	quote := models.PriceQuote{ShowID: showID}
+
+	// Add one line per seat at its stored price, adjusted for its ticket category.
+	for _, showSeat := range showSeats {
+		// A seat that has not been priced yet is not on sale.
+		if !showSeat.SeatPrice.IsPositive() {
+			return models.PriceQuote{}, ErrShowSeatNotPriced
+		}
+
+		ticket := tickets[showSeat.ShowSeatID]
+		amount := showSeat.SeatPrice.Percent(ticket.PricePercent)
+
+		quote.Items = append(quote.Items, models.PriceQuoteItem{
+			ItemType:         "Seat",
+			ShowSeatID:       showSeat.ShowSeatID,
+			Description:      fmt.Sprintf("Seat %s%d (%s, %s)", showSeat.SeatRow, showSeat.SeatNumber, showSeat.SeatType, ticket.Name),
+			Amount:           amount,
+			TicketCategoryID: ticket.TicketCategoryID,
+			TicketCategory:   ticket.Name,
+		})
+		quote.Subtotal = quote.Subtotal.Add(amount)
+	}
+
+	// Add the booking fee for all seats as a single line.
+	if feePerSeat.IsPositive() && len(showSeats) > 0 {
+		fee := feePerSeat.Mul(len(showSeats))
+
+		quote.Items = append(quote.Items, models.PriceQuoteItem{
+			ItemType:    "Fee",
+			Description: fmt.Sprintf("Booking fee (%d x %s)", len(showSeats), feePerSeat.Decimal()),
+			Amount:      fee,
+		})
+		quote.Fees = quote.Fees.Add(fee)
+	}
+
+	quote.Total = quote.Subtotal.Add(quote.Fees)
+
+	return quote, nil
+}
+
+// isPrice reports whether an amount entered on the admin page can be charged: greater than zero and
+// in the currency it is kept in. Seat prices are kept in the currency of their hall; the booking fee,
+// food and drink, promo discounts and price rules are set for the whole chain in the default currency.
+func isPrice(amount models.Money, currency string) bool {
+	return amount.IsPositive() && amount.Currency == currency
+}
+
+// convertConcessionPrices converts the prices of concession items, kept in the default currency, into
+// the currency a booking is paid in.
+func convertConcessionPrices(concessionItems []models.ConcessionItem, rates ExchangeRates, currency string) ([]models.ConcessionItem, error) {
+	converted := make([]models.ConcessionItem, 0, len(concessionItems))
+
+	for _, concessionItem := range concessionItems {
+		price, err := rates.Convert(concessionItem.Price, currency)
+		if err != nil {
+			return nil, err
+		}
+		concessionItem.Price = price
+
+		converted = append(converted, concessionItem)
+	}
+
+	return converted, nil
+}
+
+// convertPromoCode converts the discount of a fixed amount promo code, kept in the default currency,
+// into the currency a booking is paid in.
+func convertPromoCode(promoCode models.PromoCode, rates ExchangeRates, currency string) (models.PromoCode, error) {
+	discountAmount, err := rates.Convert(promoCode.DiscountAmount, currency)
+	if err != nil {
+		return models.PromoCode{}, err
+	}
+	promoCode.DiscountAmount = discountAmount
+
+	return promoCode, nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPromoCodeLength matches the size of the promo_code.code column.
+const maxPromoCodeLength = 50
+
+// NormalizePromoCode turns a code typed by a customer or an admin into the form it is stored in:
+// trimmed and in upper case, so "summer25 " and "SUMMER25" are the same code.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// newPromoCode validates the fields of a promo code entered on the admin page and converts them
+// into the form they are stored in.
+//
+// Percentage discounts are whole percents from 1 to 100; fixed discounts are decimal amounts of money,
+// like seat prices. Zero limits and empty restrictions mean "no limit" and "any".
+//
+// Returns:
+//   - models.PromoCode: The promo code ready to be stored.
+//   - error: ErrInvalidPromoCode, wrapped with the reason, if any field is invalid.
+func newPromoCode(code, description, discountType, discountValue string, validFrom, validUntil time.Time, maxUses, maxUsesPerUser, movieID int, hallType, seatType string) (models.PromoCode, error) {
+	promoCode := models.PromoCode{
+		Code:           NormalizePromoCode(code),
+		Description:    strings.TrimSpace(description),
+		DiscountType:   discountType,
+		ValidFrom:      validFrom,
+		ValidUntil:     validUntil,
+		MaxUses:        maxUses,
+		MaxUsesPerUser: maxUsesPerUser,
+		MovieID:        movieID,
+		HallType:       strings.TrimSpace(hallType),
+		SeatType:       strings.TrimSpace(seatType),
+	}
+
+	if promoCode.Code == "" || len(promoCode.Code) > maxPromoCodeLength || strings.ContainsAny(promoCode.Code, " \t\n") {
+		return models.PromoCode{}, fmt.Errorf("%w: the code must be 1 to %d characters without spaces", ErrInvalidPromoCode, maxPromoCodeLength)
+	}
+
+	switch discountType {
+	case "Percentage":
+		percent, err := strconv.Atoi(strings.TrimSpace(discountValue))
+		if err != nil || percent < 1 || percent > 100 {
+			return models.PromoCode{}, fmt.Errorf("%w: a percentage discount must be a whole number from 1 to 100", ErrInvalidPromoCode)
+		}
+		promoCode.DiscountPercent = percent
+	case "Fixed":
+		// Read the amount the same way seat prices are read.
+		amount, err := models.ParseMoney(discountValue, models.DefaultCurrency)
+		if err != nil || !isPrice(amount, models.DefaultCurrency) {
+			return models.PromoCode{}, fmt.Errorf("%w: a fixed discount must be a positive amount in %s", ErrInvalidPromoCode, models.DefaultCurrency)
+		}
+		promoCode.DiscountAmount = amount
+	default:
+		return models.PromoCode{}, fmt.Errorf("%w: the discount type must be 'Percentage' or 'Fixed'", ErrInvalidPromoCode)
+	}
+
+	if !validFrom.Before(validUntil) {
+		return models.PromoCode{}, fmt.Errorf("%w: the code must become valid before it expires", ErrInvalidPromoCode)
+	}
+
+	if maxUses < 0 || maxUsesPerUser < 0 || movieID < 0 {
+		return models.PromoCode{}, fmt.Errorf("%w: limits and the movie ID cannot be negative", ErrInvalidPromoCode)
+	}
+
+	return promoCode, nil
+}
+
+// ApplyPromoCode checks that a promo code can be used for a booking and adds its discount to the quote.
+//
+// The code must be inside its validity window, below its total usage cap, and match the movie and
+// hall type of the show when it is restricted to them. Only seat lines are discounted, at the price
+// of their ticket category, never the booking fee, and a code restricted to a seat type only
+// discounts seats of that type. A fixed discount never exceeds the price of the seats it applies to.
+// The per-user cap depends on the customer's earlier bookings, so it is checked by the caller.
+//
+// Parameters:
+//   - quote (models.PriceQuote): The quote built by buildPriceQuote.
+//   - showSeats ([]models.ShowSeat): The seats of the quote, used to match the seat type restriction.
+//   - promoCode (models.PromoCode): The code entered by the customer.
+//   - show (models.ShowMovieHall): The movie and hall type of the show being booked.
+//   - now (time.Time): The moment the code is used.
+//
+// Returns:
+//   - models.PriceQuote: The quote with a "Discount" line, its Discount and PromoCode set and a reduced Total.
+//   - error: ErrPromoCodeNotActive, ErrPromoCodeUsageLimitReached or ErrPromoCodeNotApplicable.
+func ApplyPromoCode(quote models.PriceQuote, showSeats []models.ShowSeat, promoCode models.PromoCode, show models.ShowMovieHall, now time.Time) (models.PriceQuote, error) {
+	if now.Before(promoCode.ValidFrom) || !now.Before(promoCode.ValidUntil) {
+		return models.PriceQuote{}, ErrPromoCodeNotActive
+	}
+
+	if promoCode.MaxUses > 0 && promoCode.TimesUsed >= promoCode.MaxUses {
+		return models.PriceQuote{}, ErrPromoCodeUsageLimitReached
+	}
+
+	if promoCode.MovieID != 0 && promoCode.MovieID != show.MovieID {
+		return models.PriceQuote{}, ErrPromoCodeNotApplicable
+	}
+
+	if promoCode.HallType != "" && !strings.EqualFold(promoCode.HallType, show.HallType) {
+		return models.PriceQuote{}, ErrPromoCodeNotApplicable
+	}
+
+	seatTypes := make(map[int]string, len(showSeats))
+	for _, showSeat := range showSeats {
+		seatTypes[showSeat.ShowSeatID] = showSeat.SeatType
+	}
+
+	// Add up the seat lines the code discounts, at the price of their ticket category.
+	var eligibleAmount models.Money
+	for _, item := range quote.Items {
+		if item.ItemType != "Seat" {
+			continue
+		}
+		if promoCode.SeatType == "" || strings.EqualFold(promoCode.SeatType, seatTypes[item.ShowSeatID]) {
+			eligibleAmount = eligibleAmount.Add(item.Amount)
+		}
+	}
+
+	if eligibleAmount.IsZero() {
+		return models.PriceQuote{}, ErrPromoCodeNotApplicable
+	}
+
+	var discount models.Money
+	var description string
+
+	switch promoCode.DiscountType {
+	case "Percentage":
+		discount = eligibleAmount.Percent(promoCode.DiscountPercent)
+		description = fmt.Sprintf("Promo code %s (%d%% off)", promoCode.Code, promoCode.DiscountPercent)
+	default:
+		discount = promoCode.DiscountAmount.Min(eligibleAmount)
+		description = fmt.Sprintf("Promo code %s", promoCode.Code)
+	}
+
+	// Discounts are stored as a negative line so the lines still add up to the total.
+	quote.Items = append(quote.Items, models.PriceQuoteItem{
+		ItemType:    "Discount",
+		Description: description,
+		Amount:      discount.Neg(),
+	})
+	quote.Discount = discount
+	quote.PromoCode = promoCode.Code
+	quote.Total = quote.Subtotal.Add(quote.Concessions).Add(quote.Fees).Sub(quote.Discount)
+
+	return quote, nil
+}
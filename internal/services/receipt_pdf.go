@@ -0,0 +1,211 @@
+package services
+
+import (
+	"bytes"
+	"cinemaGo/backend/internal/models"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Page geometry of receipts, in PDF points (A4 portrait).
+const (
+	receiptPageWidth   = 595
+	receiptPageHeight  = 842
+	receiptMarginLeft  = 50
+	receiptMarginRight = 545
+	receiptMarginTop   = 790
+	receiptMarginEnd   = 60
+	receiptLineHeight  = 16
+)
+
+// receiptPDF lays the text of a receipt out on as many pages as it needs. It only uses the standard
+// Helvetica fonts every PDF reader ships with, so no font has to be embedded.
+type receiptPDF struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+// newPage starts a new page with the cursor at the top margin.
+func (rp *receiptPDF) newPage() {
+	rp.pages = append(rp.pages, &bytes.Buffer{})
+	rp.y = receiptMarginTop
+}
+
+// row writes one line of text, with an optional amount aligned to the right margin.
+// Bold rows use Helvetica-Bold.
+func (rp *receiptPDF) row(text, amount string, size float64, bold bool) {
+	if len(rp.pages) == 0 || rp.y < receiptMarginEnd {
+		rp.newPage()
+	}
+
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+
+	page := rp.pages[len(rp.pages)-1]
+	fmt.Fprintf(page, "BT /%s %.0f Tf %d %.0f Td (%s) Tj ET\n", font, size, receiptMarginLeft, rp.y, pdfText(text))
+
+	if amount != "" {
+		x := receiptMarginRight - amountWidth(amount, size)
+		fmt.Fprintf(page, "BT /%s %.0f Tf %.2f %.0f Td (%s) Tj ET\n", font, size, x, rp.y, pdfText(amount))
+	}
+
+	rp.y -= receiptLineHeight
+}
+
+// rule draws a horizontal line across the page, below the previous row.
+func (rp *receiptPDF) rule() {
+	if len(rp.pages) == 0 || rp.y < receiptMarginEnd {
+		rp.newPage()
+	}
+
+	lineY := rp.y + receiptLineHeight/2
+	fmt.Fprintf(rp.pages[len(rp.pages)-1], "0.5 w %d %.0f m %d %.0f l S\n", receiptMarginLeft, lineY, receiptMarginRight, lineY)
+	rp.y -= receiptLineHeight / 2
+}
+
+// gap leaves an empty line.
+func (rp *receiptPDF) gap() {
+	rp.y -= receiptLineHeight
+}
+
+// bytes assembles the pages into a PDF file: the catalog, the page tree, the two fonts, and a page
+// object and content stream per page, followed by the cross-reference table.
+func (rp *receiptPDF) bytes() []byte {
+	var objects []string
+
+	kids := make([]string, len(rp.pages))
+	for i := range rp.pages {
+		// Objects 1 to 4 are the catalog, the page tree and the fonts; each page then takes two objects.
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(rp.pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	)
+
+	for i, page := range rp.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				receiptPageWidth, receiptPageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()),
+		)
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return pdf.Bytes()
+}
+
+// pdfText escapes text for a PDF string literal. Characters outside Latin-1 cannot be shown by the
+// standard fonts and are replaced with "?".
+func pdfText(text string) string {
+	var escaped strings.Builder
+
+	for _, char := range text {
+		switch {
+		case char == '(' || char == ')' || char == '\\':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(char)
+		case char >= 32 && char < 127:
+			escaped.WriteRune(char)
+		case char >= 160 && char <= 255:
+			fmt.Fprintf(&escaped, "\\%03o", char)
+		default:
+			escaped.WriteByte('?')
+		}
+	}
+
+	return escaped.String()
+}
+
+// amountWidth measures an amount printed in Helvetica, whose digits all have the same width,
+// so amounts can be aligned on the right margin.
+func amountWidth(amount string, size float64) float64 {
+	units := 0
+	for _, char := range amount {
+		switch char {
+		case '.':
+			units += 278
+		case '-':
+			units += 333
+		default:
+			units += 556
+		}
+	}
+
+	return float64(units) * size / 1000
+}
+
+// FormatVATRate prints a VAT rate in basis points as a percentage, e.g., "12%" or "7.5%".
+func FormatVATRate(vatRate int) string {
+	return strconv.FormatFloat(float64(vatRate)/100, 'f', -1, 64) + "%"
+}
+
+// RenderReceiptPDF renders a receipt as a PDF document with the same breakdown as its JSON form:
+// every line charged, the totals, and the VAT included in the total.
+//
+// Parameters:
+//   - receipt (models.Receipt): The receipt to render, as built by BuildReceipt.
+//
+// Returns:
+//   - []byte: The PDF document.
+func RenderReceiptPDF(receipt models.Receipt) []byte {
+	var pdf receiptPDF
+
+	pdf.row("Receipt", "", 18, true)
+	pdf.gap()
+	pdf.row("Receipt number: "+receipt.ReceiptNumber, "", 10, false)
+	pdf.row("Issued: "+receipt.IssuedAt.UTC().Format("2006-01-02 15:04 UTC"), "", 10, false)
+	pdf.row("Booking: "+receipt.BookingReference, "", 10, false)
+	pdf.row(fmt.Sprintf("Show: %s, %s %s, %s", receipt.MovieTitle, receipt.ShowDate, receipt.ShowStartTime, receipt.HallName), "", 10, false)
+	pdf.row("Payment method: "+receipt.PaymentMethod, "", 10, false)
+	pdf.gap()
+
+	pdf.row("Description", "Amount ("+receipt.Total.Currency+")", 10, true)
+	pdf.rule()
+	for _, item := range receipt.Items {
+		pdf.row(item.Description, item.Amount.Decimal(), 10, false)
+	}
+	pdf.rule()
+
+	pdf.row("Seats", receipt.Subtotal.Decimal(), 10, false)
+	if receipt.Concessions.IsPositive() {
+		pdf.row("Food and drink", receipt.Concessions.Decimal(), 10, false)
+	}
+	if receipt.Fees.IsPositive() {
+		pdf.row("Fees", receipt.Fees.Decimal(), 10, false)
+	}
+	if receipt.Discount.IsPositive() {
+		pdf.row("Discount", receipt.Discount.Neg().Decimal(), 10, false)
+	}
+	pdf.row("Total", receipt.Total.Decimal(), 12, true)
+	pdf.gap()
+
+	pdf.row("Net amount", receipt.NetAmount.Decimal(), 10, false)
+	pdf.row("VAT "+FormatVATRate(receipt.VATRate)+" (included)", receipt.VATAmount.Decimal(), 10, false)
+	if receipt.AmountRefunded.IsPositive() {
+		pdf.row("Refunded", receipt.AmountRefunded.Neg().Decimal(), 10, false)
+	}
+
+	return pdf.bytes()
+}
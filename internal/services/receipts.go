@@ -0,0 +1,87 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseVATRate reads a VAT rate written as a percentage, such as "12" or "7.5", into basis points
+// (1200 and 750), the unit receipts store it in.
+//
+// Parameters:
+//   - rate (string): The VAT rate in percent, with at most two decimals.
+//
+// Returns:
+//   - int: The VAT rate in basis points.
+//   - error: ErrInvalidVATRate if the rate is not a number between 0 and 100.
+func ParseVATRate(rate string) (int, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSpace(rate), 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidVATRate, rate)
+	}
+
+	return int(math.Round(percent * 100)), nil
+}
+
+// VATIncluded works out the VAT contained in a price that already includes it, rounded to the nearest
+// minor unit.
+//
+// Parameters:
+//   - amount (models.Money): The price including VAT.
+//   - vatRate (int): The VAT rate in basis points.
+//
+// Returns:
+//   - models.Money: The VAT part of the price, in the currency of the price.
+func VATIncluded(amount models.Money, vatRate int) models.Money {
+	if !amount.IsPositive() || vatRate <= 0 {
+		return models.NewMoney(0, amount.Currency)
+	}
+
+	// amount * rate / (1 + rate), with the rate in basis points and rounding half up.
+	rate := int64(vatRate)
+	return models.NewMoney((2*amount.Amount*rate+10000+rate)/(2*(10000+rate)), amount.Currency)
+}
+
+// FormatReceiptNumber prints a sequential receipt number the way it appears on receipts, e.g., "RCPT-000042".
+func FormatReceiptNumber(receiptNumber int64) string {
+	return fmt.Sprintf("RCPT-%06d", receiptNumber)
+}
+
+// BuildReceipt puts together the receipt of a paid booking from the lines it was charged and the
+// receipt issued for it. Prices include VAT, so the VAT is worked out from the total at the rate in
+// force when the receipt was issued.
+//
+// Parameters:
+//   - booking (models.BookingDetail): The paid booking with its itemised lines and totals.
+//   - receipt (models.ReceiptRecord): The receipt issued for the booking.
+//
+// Returns:
+//   - models.Receipt: The receipt with its line-item breakdown, VAT and total.
+func BuildReceipt(booking models.BookingDetail, receipt models.ReceiptRecord) models.Receipt {
+	vatAmount := VATIncluded(booking.Total, receipt.VATRate)
+
+	return models.Receipt{
+		ReceiptNumber:    FormatReceiptNumber(receipt.ReceiptNumber),
+		IssuedAt:         receipt.IssuedAt,
+		BookingID:        booking.BookingID,
+		BookingReference: booking.BookingReference,
+		MovieTitle:       booking.MovieTitle,
+		HallName:         booking.HallName,
+		ShowDate:         booking.ShowDate,
+		ShowStartTime:    booking.ShowStartTime,
+		PaymentMethod:    booking.PaymentMethod,
+		Items:            booking.Items,
+		Subtotal:         booking.Subtotal,
+		Concessions:      booking.ConcessionsAmount,
+		Fees:             booking.Fees,
+		Discount:         booking.Discount,
+		Total:            booking.Total,
+		VATRate:          receipt.VATRate,
+		NetAmount:        booking.Total.Sub(vatAmount),
+		VATAmount:        vatAmount,
+		AmountRefunded:   booking.AmountRefunded,
+	}
+}
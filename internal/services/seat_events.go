@@ -0,0 +1,245 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// seatChangesChannelPrefix is the Redis pub/sub channel prefix seat changes are published on;
+// the show ID is appended to it.
+const seatChangesChannelPrefix = "show_seats:"
+
+// waitlistOffersChannelPrefix is the Redis pub/sub channel prefix waitlist offers are published on;
+// the ID of the customer the offer is made to is appended to it.
+const waitlistOffersChannelPrefix = "waitlist_offers:"
+
+// waitlistOfferBuffer is how many offers a watcher can fall behind before further offers are dropped.
+// A customer holds at most one offer per show, so this is only reached by a stalled connection.
+const waitlistOfferBuffer = 8
+
+// SeatChange tells watchers that the seats of a show have changed and why
+// (e.g., "held", "booked", "released", "expired", "cancelled", "price_changed").
+type SeatChange struct {
+	ShowID int    `json:"showID"`
+	Reason string `json:"reason"`
+}
+
+// SeatEvents publishes seat changes and lets clients watch the changes of a show.
+type SeatEvents interface {
+	// PublishSeatChange announces that the seats of a show have changed. Publishing is best
+	// effort: a failure is logged and never fails the change itself.
+	PublishSeatChange(showID int, reason string)
+	// SubscribeSeatChanges watches a show. The returned function must be called to stop watching.
+	SubscribeSeatChanges(showID int) (<-chan SeatChange, func())
+}
+
+// RedisSeatEvents fans seat changes and waitlist offers out to every instance of the server through
+// Redis pub/sub. Each instance keeps a single subscription and forwards the changes of a show to the
+// clients connected to it that are watching that show, and the offers made to a customer to that
+// customer's connected clients. It is both the SeatEvents and the WaitlistNotifier of the server.
+type RedisSeatEvents struct {
+	redisClient *redis.Client
+
+	mu               sync.Mutex
+	subscribers      map[int]map[chan SeatChange]struct{}
+	offerSubscribers map[int]map[chan models.WaitlistOffer]struct{}
+}
+
+func NewRedisSeatEvents() (*RedisSeatEvents, error) {
+	redisAddr, redisPass, err := LoadRedisEnvironmentVariables("REDIS_ADDR", "REDIS_PASS")
+	if err != nil {
+		return nil, err
+	}
+	rdb := redis.NewClient(&redis.Options{
+		Addr:        redisAddr,
+		Password:    redisPass,
+		DialTimeout: 5 * time.Second,
+	})
+	return &RedisSeatEvents{
+		redisClient:      rdb,
+		subscribers:      make(map[int]map[chan SeatChange]struct{}),
+		offerSubscribers: make(map[int]map[chan models.WaitlistOffer]struct{}),
+	}, nil
+}
+
+// PublishSeatChange publishes a seat change of a show to every instance.
+func (rs *RedisSeatEvents) PublishSeatChange(showID int, reason string) {
+	payload, err := json.Marshal(SeatChange{ShowID: showID, Reason: reason})
+	if err != nil {
+		log.Printf("error occurred while encoding seat change: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := rs.redisClient.Publish(ctx, fmt.Sprintf("%s%d", seatChangesChannelPrefix, showID), payload).Err(); err != nil {
+		log.Printf("error occurred while publishing seat change: %v", err)
+	}
+}
+
+// SubscribeSeatChanges registers a watcher for the seat changes of a show on this instance.
+//
+// The channel holds at most one pending change: a watcher that falls behind only misses changes
+// it would have coalesced anyway, since every change means "the seat map must be refreshed".
+func (rs *RedisSeatEvents) SubscribeSeatChanges(showID int) (<-chan SeatChange, func()) {
+	changes := make(chan SeatChange, 1)
+
+	rs.mu.Lock()
+	if rs.subscribers[showID] == nil {
+		rs.subscribers[showID] = make(map[chan SeatChange]struct{})
+	}
+	rs.subscribers[showID][changes] = struct{}{}
+	rs.mu.Unlock()
+
+	unsubscribe := func() {
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+
+		if _, ok := rs.subscribers[showID][changes]; !ok {
+			return
+		}
+		delete(rs.subscribers[showID], changes)
+		if len(rs.subscribers[showID]) == 0 {
+			delete(rs.subscribers, showID)
+		}
+		close(changes)
+	}
+
+	return changes, unsubscribe
+}
+
+// NotifyWaitlistOffer publishes a waitlist offer to every instance, so it reaches the customer
+// wherever they are connected.
+func (rs *RedisSeatEvents) NotifyWaitlistOffer(offer models.WaitlistOffer) {
+	payload, err := json.Marshal(offer)
+	if err != nil {
+		log.Printf("error occurred while encoding waitlist offer: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := rs.redisClient.Publish(ctx, fmt.Sprintf("%s%d", waitlistOffersChannelPrefix, offer.UserID), payload).Err(); err != nil {
+		log.Printf("error occurred while publishing waitlist offer: %v", err)
+	}
+}
+
+// SubscribeWaitlistOffers registers a watcher for the waitlist offers made to a customer on this instance.
+//
+// Unlike seat changes, offers are not coalesced: each one is for different seats, so the channel
+// buffers them in the order they were made.
+func (rs *RedisSeatEvents) SubscribeWaitlistOffers(userID int) (<-chan models.WaitlistOffer, func()) {
+	offers := make(chan models.WaitlistOffer, waitlistOfferBuffer)
+
+	rs.mu.Lock()
+	if rs.offerSubscribers[userID] == nil {
+		rs.offerSubscribers[userID] = make(map[chan models.WaitlistOffer]struct{})
+	}
+	rs.offerSubscribers[userID][offers] = struct{}{}
+	rs.mu.Unlock()
+
+	unsubscribe := func() {
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+
+		if _, ok := rs.offerSubscribers[userID][offers]; !ok {
+			return
+		}
+		delete(rs.offerSubscribers[userID], offers)
+		if len(rs.offerSubscribers[userID]) == 0 {
+			delete(rs.offerSubscribers, userID)
+		}
+		close(offers)
+	}
+
+	return offers, unsubscribe
+}
+
+// Run receives the seat changes and waitlist offers published by every instance and forwards them
+// to the local watchers. It blocks until the context is cancelled, so it is meant to be started in
+// its own goroutine.
+func (rs *RedisSeatEvents) Run(ctx context.Context) {
+	pubsub := rs.redisClient.PSubscribe(ctx, seatChangesChannelPrefix+"*", waitlistOffersChannelPrefix+"*")
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			if strings.HasPrefix(message.Channel, waitlistOffersChannelPrefix) {
+				var offer models.WaitlistOffer
+				if err := json.Unmarshal([]byte(message.Payload), &offer); err != nil {
+					log.Printf("error occurred while decoding waitlist offer: %v", err)
+					continue
+				}
+
+				// Trust the channel name over the payload for the customer the offer is made to.
+				if userID, err := strconv.Atoi(strings.TrimPrefix(message.Channel, waitlistOffersChannelPrefix)); err == nil {
+					offer.UserID = userID
+				}
+
+				rs.dispatchOffer(offer)
+				continue
+			}
+
+			var change SeatChange
+			if err := json.Unmarshal([]byte(message.Payload), &change); err != nil {
+				log.Printf("error occurred while decoding seat change: %v", err)
+				continue
+			}
+
+			// Trust the channel name over the payload for the show the change belongs to.
+			if showID, err := strconv.Atoi(strings.TrimPrefix(message.Channel, seatChangesChannelPrefix)); err == nil {
+				change.ShowID = showID
+			}
+
+			rs.dispatch(change)
+		}
+	}
+}
+
+// dispatch hands a change to every local watcher of its show without ever blocking.
+func (rs *RedisSeatEvents) dispatch(change SeatChange) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for changes := range rs.subscribers[change.ShowID] {
+		select {
+		case changes <- change:
+		default:
+			// A refresh is already pending for this watcher.
+		}
+	}
+}
+
+// dispatchOffer hands an offer to every local watcher of its customer without ever blocking.
+func (rs *RedisSeatEvents) dispatchOffer(offer models.WaitlistOffer) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for offers := range rs.offerSubscribers[offer.UserID] {
+		select {
+		case offers <- offer:
+		default:
+			// The watcher has stalled; the offer is still shown on the waitlist page.
+			log.Printf("dropped waitlist offer %d for a stalled watcher of user %d", offer.WaitlistEntryID, offer.UserID)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"bytes"
+	"cinemaGo/backend/internal/models"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Geometry of rendered seat maps, in SVG user units.
+const (
+	seatMapCell        = 32 // Width and height of a grid cell.
+	seatMapSeatInset   = 3  // Gap between a seat and the edge of its cell.
+	seatMapLabelWidth  = 40 // Room on the left for the row labels.
+	seatMapScreenDepth = 48 // Room for the screen, above or below the grid.
+	seatMapLegendDepth = 36 // Room for the legend below everything else.
+	seatMapMargin      = 12
+)
+
+// Colours of rendered seat maps. Available seats are coloured by type, every other seat by status.
+var (
+	seatTypeColours = map[string]string{
+		"Standard":   "#4caf50",
+		"VIP":        "#8e44ad",
+		"Accessible": "#1e88e5",
+	}
+	seatStatusColours = map[string]string{
+		"Selected": "#ffa726",
+		"Booked":   "#9e9e9e",
+	}
+	markerColours = map[string]string{
+		"Aisle":  "#f5f5f5",
+		"Stairs": "#d7ccc8",
+		"Exit":   "#c8e6c9",
+	}
+)
+
+const (
+	seatMapDefaultColour   = "#4caf50"
+	seatMapHighlightColour = "#e91e63"
+)
+
+// seatColour picks the fill of a seat: its type's colour while it can be booked, its status's otherwise.
+func seatColour(showSeat models.ShowSeat) string {
+	if showSeat.SeatStatus != "Available" {
+		if colour, found := seatStatusColours[showSeat.SeatStatus]; found {
+			return colour
+		}
+		return seatStatusColours["Booked"]
+	}
+
+	if colour, found := seatTypeColours[showSeat.SeatType]; found {
+		return colour
+	}
+
+	return seatMapDefaultColour
+}
+
+// RenderSeatMapSVG draws a seat map as a standalone SVG image, for clients that cannot draw the grid
+// themselves such as kiosks and emails. Seats are coloured by status, and by type while available, and
+// carry their label and a tooltip; highlighted seats get a thick outline. Aisles, stairs and exits are
+// shaded, the screen is drawn on its side of the hall, and a legend explains the colours.
+//
+// Parameters:
+//   - grid (models.SeatGrid): The seat map, as built by BuildSeatGrid.
+//   - highlighted (map[int]bool): The show seat IDs to mark, e.g., the seats of a booking; may be nil.
+//
+// Returns:
+//   - []byte: The SVG document.
+func RenderSeatMapSVG(grid models.SeatGrid, highlighted map[int]bool) []byte {
+	gridTop := seatMapMargin
+	if grid.ScreenPosition != "Bottom" {
+		gridTop += seatMapScreenDepth
+	}
+
+	gridWidth := grid.Columns * seatMapCell
+	gridHeight := grid.Rows * seatMapCell
+	width := max(seatMapMargin+seatMapLabelWidth+gridWidth, seatMapLegendWidth(highlighted != nil)) + seatMapMargin
+	height := seatMapMargin + seatMapScreenDepth + gridHeight + seatMapLegendDepth + seatMapMargin
+
+	var svg bytes.Buffer
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">`+"\n", width, height, width, height)
+	fmt.Fprintf(&svg, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+
+	// The screen spans the grid, slightly inset, on the side of the hall it is on.
+	screenY := seatMapMargin + 12
+	if grid.ScreenPosition == "Bottom" {
+		screenY = gridTop + gridHeight + 12
+	}
+	gridLeft := seatMapMargin + seatMapLabelWidth
+	fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="6" rx="3" fill="#607d8b"/>`+"\n", gridLeft+seatMapCell/2, screenY, max(gridWidth-seatMapCell, seatMapCell))
+	fmt.Fprintf(&svg, `<text x="%d" y="%d" font-size="11" fill="#607d8b" text-anchor="middle">SCREEN</text>`+"\n", gridLeft+gridWidth/2, screenY+20)
+
+	for y, cells := range grid.Cells {
+		cellY := gridTop + y*seatMapCell
+		rowLabel := ""
+
+		for x, cell := range cells {
+			cellX := gridLeft + x*seatMapCell
+
+			switch cell.Kind {
+			case "Seat":
+				if cell.Seat == nil {
+					continue
+				}
+				if rowLabel == "" {
+					rowLabel = cell.Seat.SeatRow
+				}
+				writeSeat(&svg, *cell.Seat, cellX, cellY, highlighted[cell.Seat.ShowSeatID])
+			case "Empty":
+			default:
+				colour, found := markerColours[cell.Kind]
+				if !found {
+					continue
+				}
+				fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"><title>%s</title></rect>`+"\n", cellX, cellY, seatMapCell, seatMapCell, colour, html.EscapeString(cell.Kind))
+				if cell.Kind == "Exit" {
+					fmt.Fprintf(&svg, `<text x="%d" y="%d" font-size="8" fill="#2e7d32" text-anchor="middle">EXIT</text>`+"\n", cellX+seatMapCell/2, cellY+seatMapCell/2+3)
+				}
+			}
+		}
+
+		if rowLabel != "" {
+			fmt.Fprintf(&svg, `<text x="%d" y="%d" font-size="12" fill="#424242" text-anchor="middle">%s</text>`+"\n", seatMapMargin+seatMapLabelWidth/2, cellY+seatMapCell/2+4, html.EscapeString(rowLabel))
+		}
+	}
+
+	writeSeatMapLegend(&svg, height-seatMapMargin-seatMapLegendDepth/2, highlighted != nil)
+
+	svg.WriteString("</svg>\n")
+
+	return svg.Bytes()
+}
+
+// writeSeat draws one seat, turned by its angle around the centre of its cell.
+func writeSeat(svg *bytes.Buffer, showSeat models.ShowSeat, cellX, cellY int, highlighted bool) {
+	size := seatMapCell - 2*seatMapSeatInset
+	centreX, centreY := cellX+seatMapCell/2, cellY+seatMapCell/2
+
+	stroke := `stroke="#ffffff" stroke-width="1"`
+	if highlighted {
+		stroke = fmt.Sprintf(`stroke="%s" stroke-width="3"`, seatMapHighlightColour)
+	}
+
+	title := fmt.Sprintf("%s%d, %s, %s", showSeat.SeatRow, showSeat.SeatNumber, showSeat.SeatType, seatStatusLabel(showSeat.SeatStatus))
+
+	fmt.Fprintf(svg, `<g transform="rotate(%d %d %d)">`, showSeat.Angle, centreX, centreY)
+	fmt.Fprintf(svg, `<rect x="%d" y="%d" width="%d" height="%d" rx="6" fill="%s" %s><title>%s</title></rect>`,
+		cellX+seatMapSeatInset, cellY+seatMapSeatInset, size, size, seatColour(showSeat), stroke, html.EscapeString(title))
+	fmt.Fprintf(svg, `<text x="%d" y="%d" font-size="10" fill="#ffffff" text-anchor="middle">%d</text>`, centreX, centreY+3, showSeat.SeatNumber)
+	svg.WriteString("</g>\n")
+}
+
+// seatStatusLabel names a seat status the way customers understand it.
+func seatStatusLabel(status string) string {
+	switch status {
+	case "Available":
+		return "available"
+	case "Selected":
+		return "held"
+	default:
+		return strings.ToLower(status)
+	}
+}
+
+// seatMapLegend lists the colours of the legend with what they stand for.
+var seatMapLegend = [][2]string{
+	{seatTypeColours["Standard"], "Standard"},
+	{seatTypeColours["VIP"], "VIP"},
+	{seatTypeColours["Accessible"], "Accessible"},
+	{seatStatusColours["Selected"], "Held"},
+	{seatStatusColours["Booked"], "Booked"},
+}
+
+// seatMapLegendEntryWidth estimates the room a legend entry takes: its swatch and its text, at about
+// 8 units per character, and the space before the next entry.
+func seatMapLegendEntryWidth(label string) int {
+	return 16 + 8*len(label) + 12
+}
+
+// seatMapLegendWidth is how far the legend reaches from the left edge of the map.
+func seatMapLegendWidth(withHighlight bool) int {
+	width := seatMapMargin
+	for _, entry := range seatMapLegend {
+		width += seatMapLegendEntryWidth(entry[1])
+	}
+	if withHighlight {
+		width += seatMapLegendEntryWidth("Your seats")
+	}
+
+	return width
+}
+
+// writeSeatMapLegend explains the colours in a line along the bottom of the map.
+func writeSeatMapLegend(svg *bytes.Buffer, y int, withHighlight bool) {
+	x := seatMapMargin
+	for _, entry := range seatMapLegend {
+		fmt.Fprintf(svg, `<rect x="%d" y="%d" width="12" height="12" rx="3" fill="%s"/>`, x, y-6, entry[0])
+		fmt.Fprintf(svg, `<text x="%d" y="%d" font-size="11" fill="#424242">%s</text>`+"\n", x+16, y+4, entry[1])
+		x += seatMapLegendEntryWidth(entry[1])
+	}
+
+	if withHighlight {
+		fmt.Fprintf(svg, `<rect x="%d" y="%d" width="12" height="12" rx="3" fill="#ffffff" stroke="%s" stroke-width="3"/>`, x, y-6, seatMapHighlightColour)
+		fmt.Fprintf(svg, `<text x="%d" y="%d" font-size="11" fill="#424242">Your seats</text>`+"\n", x+16, y+4)
+	}
+}
+
+// FetchSeatMapSVG renders the seat map of a show as an SVG image, optionally marking the seats of a booking.
+//
+// The map is public, so the seats of a booking are only marked for its owner or for whoever holds its
+// ticket. A reference that is malformed, unknown, for another show or not the caller's gives the plain
+// map, so the map never tells which references exist or where anyone else sits.
+//
+// Params:
+//   - showID (int): The ID of the show.
+//   - bookingReference (string): The reference of a booking for the show whose seats to mark, or empty.
+//     The seats of a booking that was cancelled or whose payment failed are not marked, as they were given back.
+//   - userID (int): The ID of the logged-in user, or 0 for an anonymous request.
+//   - ticketToken (string): The signed ticket token of the booking, or empty.
+//
+// Returns:
+//   - []byte: The SVG document.
+//   - error: ErrShowSeatNotFound or a wrapped error.
+func (bs *BookingService) FetchSeatMapSVG(showID int, bookingReference string, userID int, ticketToken string) ([]byte, error) {
+	var highlighted map[int]bool
+
+	if bookingReference != "" {
+		booking, ok, err := bs.fetchHighlightedBooking(bookingReference, userID, ticketToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok && booking.ShowID == showID {
+			highlighted = make(map[int]bool)
+			if booking.BookingStatus == "Confirmed" || booking.BookingStatus == "Pending" {
+				for _, item := range booking.Items {
+					if item.ItemType == "Seat" {
+						highlighted[item.ShowSeatID] = true
+					}
+				}
+			}
+		}
+	}
+
+	showSeats, err := bs.db.RetrieveShowSeats(showID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return nil, ErrShowSeatNotFound
+		}
+		return nil, fmt.Errorf("error occurred while fetching show seats for the seat map in the service section: %w", err)
+	}
+
+	grid, err := bs.FetchSeatGrid(showID, showSeats)
+	if err != nil {
+		return nil, err
+	}
+
+	return RenderSeatMapSVG(grid, highlighted), nil
+}
+
+// fetchHighlightedBooking looks up the booking whose seats the caller asked to mark on the seat map.
+// It reports false, without an error, unless the booking exists and the caller owns it or holds its ticket.
+func (bs *BookingService) fetchHighlightedBooking(bookingReference string, userID int, ticketToken string) (models.BookingDetail, bool, error) {
+	booking, err := bs.FetchBookingByReference(bookingReference)
+	if err != nil {
+		if errors.Is(err, ErrInvalidBookingReference) || errors.Is(err, ErrBookingNotFound) {
+			return models.BookingDetail{}, false, nil
+		}
+		return models.BookingDetail{}, false, err
+	}
+
+	if ticketToken != "" {
+		if claims, err := bs.tickets.Verify(ticketToken); err == nil && claims.BookingID == booking.BookingID {
+			return booking, true, nil
+		}
+	}
+
+	if userID > 0 {
+		// Only the owner's own bookings are found for them.
+		_, err := bs.db.RetrieveUserBooking(booking.BookingID, userID)
+		if err == nil {
+			return booking, true, nil
+		}
+		if !errors.Is(err, models.ErrBookingNotFound) {
+			return models.BookingDetail{}, false, fmt.Errorf("error occurred while checking the owner of the booking for the seat map in the service section: %w", err)
+		}
+	}
+
+	return models.BookingDetail{}, false, nil
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// rowDistanceWeight makes moving one row away from the middle of the hall cost more than moving
+// one seat sideways, since sitting too close or too far from the screen matters most.
+const rowDistanceWeight = 1.5
+
+// SuggestAdjacentSeats picks the best block of count adjacent available seats in the hall.
+//
+// Seats are adjacent when they share a row and have consecutive seat numbers, and, on a hall layout,
+// sit in neighbouring grid columns rather than on either side of an aisle. Every candidate
+// block is scored by how far its centre is from the centre of the hall, both sideways and in rows,
+// and the closest block wins. Ties go to the block listed first.
+//
+// Parameters:
+//   - showSeats ([]models.ShowSeat): Every seat of the show, as returned for the seat map.
+//   - count (int): How many adjacent seats are needed.
+//   - seatType (string): Only consider seats of this type (e.g., "VIP"); empty for any type.
+//
+// Returns:
+//   - []models.ShowSeat: The suggested seats, ordered by seat number.
+//   - error: ErrNoAdjacentSeats if no block of count adjacent seats is available.
+func SuggestAdjacentSeats(showSeats []models.ShowSeat, count int, seatType string) ([]models.ShowSeat, error) {
+	return suggestAdjacentSeats(showSeats, count, seatType, false)
+}
+
+// suggestAdjacentSeats is SuggestAdjacentSeats, optionally skipping the blocks that would leave a
+// single empty seat on its own, which CheckSingleSeatGaps would refuse.
+func suggestAdjacentSeats(showSeats []models.ShowSeat, count int, seatType string, blockGaps bool) ([]models.ShowSeat, error) {
+	if count < 1 || len(showSeats) == 0 {
+		return nil, ErrNoAdjacentSeats
+	}
+
+	// Work out the shape of the hall from every seat, whatever its status.
+	rowSeats := make(map[string][]models.ShowSeat)
+	minNumber, maxNumber := math.MaxInt, math.MinInt
+	for _, showSeat := range showSeats {
+		rowSeats[showSeat.SeatRow] = append(rowSeats[showSeat.SeatRow], showSeat)
+		minNumber = min(minNumber, showSeat.SeatNumber)
+		maxNumber = max(maxNumber, showSeat.SeatNumber)
+	}
+
+	rows := make([]string, 0, len(rowSeats))
+	for row := range rowSeats {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rowLess(rows[i], rows[j]) })
+
+	centreNumber := float64(minNumber+maxNumber) / 2
+	centreRow := float64(len(rows)-1) / 2
+
+	seats := newSeatIndex(showSeats)
+
+	var best []models.ShowSeat
+	bestScore := math.Inf(1)
+
+	for rowIndex, row := range rows {
+		rowSeats := rowSeats[row]
+		sort.Slice(rowSeats, func(i, j int) bool { return rowSeats[i].SeatNumber < rowSeats[j].SeatNumber })
+
+		// Slide over runs of consecutive suitable seats.
+		runStart := 0
+		for i := range rowSeats {
+			if !isSuggestable(rowSeats[i], seatType) {
+				runStart = i + 1
+				continue
+			}
+			if i > runStart && !placedAside(rowSeats[i-1], rowSeats[i], 1) {
+				runStart = i
+			}
+
+			// The run ending at i is long enough to hold a block of count seats.
+			if i-runStart+1 >= count {
+				block := rowSeats[i-count+1 : i+1]
+				if blockGaps {
+					if _, _, found := seats.singleSeatGap(block); found {
+						continue
+					}
+				}
+
+				blockCentre := float64(block[0].SeatNumber+block[count-1].SeatNumber) / 2
+				score := math.Abs(blockCentre-centreNumber) + rowDistanceWeight*math.Abs(float64(rowIndex)-centreRow)
+
+				if score < bestScore {
+					bestScore = score
+					best = block
+				}
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, ErrNoAdjacentSeats
+	}
+
+	// Return a copy so callers cannot alter the grouped seats.
+	return append([]models.ShowSeat(nil), best...), nil
+}
+
+// isSuggestable reports whether a seat can be offered: it must be free, priced and of the
+// requested type.
+func isSuggestable(showSeat models.ShowSeat, seatType string) bool {
+	if showSeat.SeatStatus != "Available" || !showSeat.SeatPrice.IsPositive() {
+		return false
+	}
+
+	return seatType == "" || strings.EqualFold(showSeat.SeatType, seatType)
+}
+
+// rowLess orders row labels the way they are painted in a hall: "A" to "Z", then "AA", "AB" and so on.
+func rowLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+
+	return a < b
+}
+
+// CheckSingleSeatGaps refuses a selection that would leave a single empty seat on its own next to it:
+// a free seat between a selected seat and a taken seat, the end of the row or an aisle. Seats are
+// neighbours when they are adjacent as SuggestAdjacentSeats defines it, so an aisle marked on the hall
+// layout, or a jump in the numbering, separates them. Single seats that were already stranded before
+// the selection are not the customer's doing and are allowed.
+//
+// Parameters:
+//   - showSeats ([]models.ShowSeat): Every seat of the show, as returned for the seat map.
+//   - showSeatsID ([]int): The IDs of the selected show seats.
+//
+// Returns:
+//   - error: ErrSingleSeatGap naming the stranded seat and the selections that avoid it, or nil.
+func CheckSingleSeatGaps(showSeats []models.ShowSeat, showSeatsID []int) error {
+	seats := newSeatIndex(showSeats)
+
+	var selection []models.ShowSeat
+	for _, showSeatID := range showSeatsID {
+		if showSeat, found := seats.byID[showSeatID]; found {
+			selection = append(selection, showSeat)
+		}
+	}
+
+	gapSeat, direction, found := seats.singleSeatGap(selection)
+	if !found {
+		return nil
+	}
+
+	// Offer the fixes that keep the group together: taking the stranded seat too, or moving every
+	// seat one place towards it.
+	var fixes []string
+	if shifted, ok := seats.shift(selection, direction); ok {
+		fixes = append(fixes, "choose "+seatLabels(shifted)+" instead")
+	}
+	if len(selection) < 5 {
+		fixes = append(fixes, "add seat "+seatLabel(gapSeat)+" to your selection")
+	}
+	if len(fixes) == 0 {
+		fixes = append(fixes, "choose other seats")
+	}
+
+	return fmt.Errorf("%w: seat %s would be left empty on its own; %s", ErrSingleSeatGap, seatLabel(gapSeat), strings.Join(fixes, ", or "))
+}
+
+// seatIndex finds the seats of a show by ID and by position.
+type seatIndex struct {
+	byID       map[int]models.ShowSeat
+	byPosition map[string]map[int]models.ShowSeat
+}
+
+func newSeatIndex(showSeats []models.ShowSeat) seatIndex {
+	seats := seatIndex{byID: make(map[int]models.ShowSeat), byPosition: make(map[string]map[int]models.ShowSeat)}
+
+	for _, showSeat := range showSeats {
+		seats.byID[showSeat.ShowSeatID] = showSeat
+		if seats.byPosition[showSeat.SeatRow] == nil {
+			seats.byPosition[showSeat.SeatRow] = make(map[int]models.ShowSeat)
+		}
+		seats.byPosition[showSeat.SeatRow][showSeat.SeatNumber] = showSeat
+	}
+
+	return seats
+}
+
+// neighbour returns the seat offset places away from a seat in the same row, if there is one and no
+// aisle lies between them.
+func (si seatIndex) neighbour(showSeat models.ShowSeat, offset int) (models.ShowSeat, bool) {
+	neighbour, found := si.byPosition[showSeat.SeatRow][showSeat.SeatNumber+offset]
+	if !found || !placedAside(showSeat, neighbour, offset) {
+		return models.ShowSeat{}, false
+	}
+
+	return neighbour, true
+}
+
+// placedAside reports whether the seat "to" sits offset places from the seat "from" in the same row: its
+// seat number is offset higher and, if both are on a hall layout, so is its grid column.
+func placedAside(from, to models.ShowSeat, offset int) bool {
+	if to.SeatNumber != from.SeatNumber+offset {
+		return false
+	}
+
+	if from.GridX == 0 || to.GridX == 0 {
+		return true
+	}
+
+	// Numbering may run either way across the grid, but the seats must be as close as their numbers.
+	distance := to.GridX - from.GridX
+	return distance == offset || distance == -offset
+}
+
+// singleSeatGap finds the first free seat the selection would strand, and the direction (-1 for lower
+// seat numbers, +1 for higher ones) it lies in from the selection.
+func (si seatIndex) singleSeatGap(selection []models.ShowSeat) (models.ShowSeat, int, bool) {
+	selected := make(map[int]bool, len(selection))
+	for _, showSeat := range selection {
+		selected[showSeat.ShowSeatID] = true
+	}
+
+	isFree := func(showSeat models.ShowSeat, found bool) bool {
+		return found && !selected[showSeat.ShowSeatID] && isSuggestable(showSeat, "")
+	}
+
+	ordered := append([]models.ShowSeat(nil), selection...)
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].SeatRow != ordered[j].SeatRow {
+			return rowLess(ordered[i].SeatRow, ordered[j].SeatRow)
+		}
+		return ordered[i].SeatNumber < ordered[j].SeatNumber
+	})
+
+	for _, showSeat := range ordered {
+		for _, direction := range []int{-1, 1} {
+			next, found := si.neighbour(showSeat, direction)
+			if !isFree(next, found) {
+				continue
+			}
+
+			// The free seat is stranded when the seat beyond it cannot be sold either.
+			beyond, found := si.neighbour(showSeat, 2*direction)
+			if !isFree(beyond, found) {
+				return next, direction, true
+			}
+		}
+	}
+
+	return models.ShowSeat{}, 0, false
+}
+
+// shift moves every selected seat one place in the given direction, if all the seats it lands on
+// are free and the moved selection strands no seat.
+func (si seatIndex) shift(selection []models.ShowSeat, direction int) ([]models.ShowSeat, bool) {
+	selected := make(map[int]bool, len(selection))
+	for _, showSeat := range selection {
+		selected[showSeat.ShowSeatID] = true
+	}
+
+	shifted := make([]models.ShowSeat, 0, len(selection))
+	for _, showSeat := range selection {
+		next, found := si.neighbour(showSeat, direction)
+		if !found || !(selected[next.ShowSeatID] || isSuggestable(next, "")) {
+			return nil, false
+		}
+		shifted = append(shifted, next)
+	}
+
+	if _, _, found := si.singleSeatGap(shifted); found {
+		return nil, false
+	}
+
+	sort.Slice(shifted, func(i, j int) bool {
+		if shifted[i].SeatRow != shifted[j].SeatRow {
+			return rowLess(shifted[i].SeatRow, shifted[j].SeatRow)
+		}
+		return shifted[i].SeatNumber < shifted[j].SeatNumber
+	})
+
+	return shifted, true
+}
+
+// seatLabel names a seat the way it is painted in the hall, e.g., "B4".
+func seatLabel(showSeat models.ShowSeat) string {
+	return showSeat.SeatRow + strconv.Itoa(showSeat.SeatNumber)
+}
+
+// seatLabels names several seats, e.g., "B4, B5".
+func seatLabels(showSeats []models.ShowSeat) string {
+	labels := make([]string, 0, len(showSeats))
+	for _, showSeat := range showSeats {
+		labels = append(labels, seatLabel(showSeat))
+	}
+
+	return strings.Join(labels, ", ")
+}
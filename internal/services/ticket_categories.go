@@ -0,0 +1,148 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultTicketCategory is the category of every seat the customer does not pick a category for.
+const DefaultTicketCategory = "Adult"
+
+// adultsOnlyAge is the minimum age of movies rated "A" (adults only).
+const adultsOnlyAge = 18
+
+// MinimumAge reads the youngest age a movie may be watched at from its age limit.
+//
+// Ratings that carry an age use it, whatever surrounds it: "18+", "UA13+" and "PG-13" give 18, 13
+// and 13. "A" (adults only) gives 18. Any other rating, such as "U" or "UA", or an empty one is
+// open to every age.
+//
+// Parameters:
+//   - ageLimit (string): The age limit of the movie, as stored in movies.age_limit.
+//
+// Returns:
+//   - int: The minimum age, or 0 if anyone may watch the movie.
+func MinimumAge(ageLimit string) int {
+	ageLimit = strings.TrimSpace(ageLimit)
+
+	if strings.EqualFold(ageLimit, "A") {
+		return adultsOnlyAge
+	}
+
+	start := strings.IndexFunc(ageLimit, unicode.IsDigit)
+	if start == -1 {
+		return 0
+	}
+
+	end := start
+	for end < len(ageLimit) && unicode.IsDigit(rune(ageLimit[end])) {
+		end++
+	}
+
+	age, err := strconv.Atoi(ageLimit[start:end])
+	if err != nil {
+		return 0
+	}
+
+	return age
+}
+
+// AssignTicketCategories picks the ticket category of every selected seat and checks that the
+// categories are allowed for the movie.
+//
+// Seats missing from choices are sold as DefaultTicketCategory. Category names are matched in any
+// case. A category whose oldest age is below the minimum age of the movie cannot be booked, so no
+// child ticket is ever sold for an "18+" film.
+//
+// Parameters:
+//   - showSeatsID ([]int): The IDs of the selected show seats.
+//   - choices (map[int]string): The category chosen for each show seat ID, by name.
+//   - ticketCategories ([]models.TicketCategory): Every ticket category.
+//   - ageLimit (string): The age limit of the movie being booked.
+//
+// Returns:
+//   - map[int]models.TicketCategory: The category of every selected show seat ID.
+//   - error: ErrInvalidTicketCategory or ErrTicketCategoryAgeRestricted, wrapped with the reason.
+func AssignTicketCategories(showSeatsID []int, choices map[int]string, ticketCategories []models.TicketCategory, ageLimit string) (map[int]models.TicketCategory, error) {
+	selected := make(map[int]bool, len(showSeatsID))
+	for _, showSeatID := range showSeatsID {
+		selected[showSeatID] = true
+	}
+
+	for showSeatID := range choices {
+		if !selected[showSeatID] {
+			return nil, fmt.Errorf("%w: seat %d is not part of the selection", ErrInvalidTicketCategory, showSeatID)
+		}
+	}
+
+	minimumAge := MinimumAge(ageLimit)
+	tickets := make(map[int]models.TicketCategory, len(showSeatsID))
+
+	for _, showSeatID := range showSeatsID {
+		name, ok := choices[showSeatID]
+		if !ok || strings.TrimSpace(name) == "" {
+			name = DefaultTicketCategory
+		}
+
+		ticketCategory, found := findTicketCategory(ticketCategories, name)
+		if !found {
+			return nil, fmt.Errorf("%w: unknown ticket category %q", ErrInvalidTicketCategory, name)
+		}
+
+		if ticketCategory.MaxAge > 0 && ticketCategory.MaxAge < minimumAge {
+			return nil, fmt.Errorf("%w: %s tickets are for ages up to %d, but this movie is rated %s", ErrTicketCategoryAgeRestricted,
+				ticketCategory.Name, ticketCategory.MaxAge, ageLimit)
+		}
+
+		tickets[showSeatID] = ticketCategory
+	}
+
+	return tickets, nil
+}
+
+// findTicketCategory looks a ticket category up by name, in any case.
+func findTicketCategory(ticketCategories []models.TicketCategory, name string) (models.TicketCategory, bool) {
+	for _, ticketCategory := range ticketCategories {
+		if strings.EqualFold(ticketCategory.Name, strings.TrimSpace(name)) {
+			return ticketCategory, true
+		}
+	}
+
+	return models.TicketCategory{}, false
+}
+
+// newTicketCategory validates the fields of a ticket category entered on the admin page.
+// An age bound of 0 means the category has no such bound.
+//
+// Returns:
+//   - models.TicketCategory: The ticket category ready to be stored.
+//   - error: ErrInvalidTicketCategoryDefinition, wrapped with the reason, if any field is invalid.
+func newTicketCategory(name string, pricePercent, minAge, maxAge int) (models.TicketCategory, error) {
+	ticketCategory := models.TicketCategory{
+		Name:         strings.TrimSpace(name),
+		PricePercent: pricePercent,
+		MinAge:       minAge,
+		MaxAge:       maxAge,
+	}
+
+	if ticketCategory.Name == "" || len(ticketCategory.Name) > 50 {
+		return models.TicketCategory{}, fmt.Errorf("%w: the name must be 1 to 50 characters", ErrInvalidTicketCategoryDefinition)
+	}
+
+	if pricePercent < 1 {
+		return models.TicketCategory{}, fmt.Errorf("%w: the price percentage must be greater than zero", ErrInvalidTicketCategoryDefinition)
+	}
+
+	if minAge < 0 || maxAge < 0 {
+		return models.TicketCategory{}, fmt.Errorf("%w: ages cannot be negative", ErrInvalidTicketCategoryDefinition)
+	}
+
+	if minAge > 0 && maxAge > 0 && minAge > maxAge {
+		return models.TicketCategory{}, fmt.Errorf("%w: the minimum age cannot be above the maximum age", ErrInvalidTicketCategoryDefinition)
+	}
+
+	return ticketCategory, nil
+}
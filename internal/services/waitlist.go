@@ -0,0 +1,288 @@
+package services
+
+import (
+	"cinemaGo/backend/internal/models"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+)
+
+// WaitlistNotifier tells waitlisted customers that seats are being held for them.
+type WaitlistNotifier interface {
+	// NotifyWaitlistOffer announces an offer to its customer. Notifying is best effort: a failure
+	// is logged and never withdraws the offer, which the customer can also see on the waitlist page.
+	NotifyWaitlistOffer(offer models.WaitlistOffer)
+	// SubscribeWaitlistOffers watches the offers made to a customer. The returned function must be
+	// called to stop watching.
+	SubscribeWaitlistOffers(userID int) (<-chan models.WaitlistOffer, func())
+}
+
+// PickWaitlistSeats picks the seats offered to a waitlisted customer: the best block of adjacent
+// available seats if there is one, or else the available seats closest to the front of the hall.
+//
+// Parameters:
+//   - showSeats ([]models.ShowSeat): Every seat of the show, as returned for the seat map.
+//   - count (int): How many seats the customer needs.
+//
+// Returns:
+//   - []models.ShowSeat: The picked seats, or nil if fewer than count seats are available.
+func PickWaitlistSeats(showSeats []models.ShowSeat, count int) []models.ShowSeat {
+	if bestSeats, err := SuggestAdjacentSeats(showSeats, count, ""); err == nil {
+		return bestSeats
+	}
+
+	var availableSeats []models.ShowSeat
+	for _, showSeat := range showSeats {
+		if isSuggestable(showSeat, "") {
+			availableSeats = append(availableSeats, showSeat)
+		}
+	}
+
+	if count < 1 || len(availableSeats) < count {
+		return nil
+	}
+
+	sort.Slice(availableSeats, func(i, j int) bool {
+		if availableSeats[i].SeatRow != availableSeats[j].SeatRow {
+			return rowLess(availableSeats[i].SeatRow, availableSeats[j].SeatRow)
+		}
+		return availableSeats[i].SeatNumber < availableSeats[j].SeatNumber
+	})
+
+	return availableSeats[:count]
+}
+
+// JoinWaitlist puts a customer on the waitlist of a sold-out show. Once seats free up, they are
+// offered to the waitlist in the order customers joined.
+//
+// Params:
+//   - showID (int): The ID of the show.
+//   - userID (int): The ID of the customer.
+//   - numberOfSeats (int): How many seats the customer needs (at most 5).
+//
+// Returns:
+//   - models.WaitlistEntry: The new entry with the customer's place in the queue.
+//   - error: ErrTooManySeats, ErrShowSeatNotFound, ErrShowNotSoldOut if a seat can still be booked,
+//     ErrAlreadyWaitlisted, or a wrapped error.
+func (bs *BookingService) JoinWaitlist(showID, userID, numberOfSeats int) (models.WaitlistEntry, error) {
+	// A waitlisted customer is offered at most as many seats as a single booking allows.
+	if numberOfSeats > 5 {
+		return models.WaitlistEntry{}, ErrTooManySeats
+	}
+
+	showSeats, err := bs.db.RetrieveShowSeats(showID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return models.WaitlistEntry{}, ErrShowSeatNotFound
+		}
+		return models.WaitlistEntry{}, fmt.Errorf("error occurred while fetching show seats for the waitlist in the service section: %w", err)
+	}
+
+	// Customers can only wait for shows where no seat can be booked right now.
+	for _, showSeat := range showSeats {
+		if isSuggestable(showSeat, "") {
+			return models.WaitlistEntry{}, ErrShowNotSoldOut
+		}
+	}
+
+	_, err = bs.db.InsertWaitlistEntry(showID, userID, numberOfSeats)
+	if err != nil {
+		if errors.Is(err, models.ErrAlreadyWaitlisted) {
+			return models.WaitlistEntry{}, ErrAlreadyWaitlisted
+		}
+		if errors.Is(err, models.ErrShowNotFound) {
+			return models.WaitlistEntry{}, ErrShowSeatNotFound
+		}
+		return models.WaitlistEntry{}, fmt.Errorf("error occurred while joining the waitlist in the service section: %w", err)
+	}
+
+	// Seats may have freed up since they were counted; serve the queue so they are not left unoffered.
+	bs.offerWaitlistSeats(showID)
+
+	return bs.FetchWaitlistEntry(showID, userID)
+}
+
+// FetchWaitlistEntry retrieves a customer's latest waitlist entry for a show: their place in the
+// queue while they wait, or the seats held for them and until when once they are offered seats.
+//
+// Params:
+//   - showID (int): The ID of the show.
+//   - userID (int): The ID of the customer.
+//
+// Returns:
+//   - models.WaitlistEntry: The entry.
+//   - error: ErrWaitlistEntryNotFound if the customer never joined the waitlist of the show, or a wrapped error.
+func (bs *BookingService) FetchWaitlistEntry(showID, userID int) (models.WaitlistEntry, error) {
+	waitlistEntry, err := bs.db.RetrieveWaitlistEntry(showID, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrWaitlistEntryNotFound) {
+			return models.WaitlistEntry{}, ErrWaitlistEntryNotFound
+		}
+		return models.WaitlistEntry{}, fmt.Errorf("error occurred while fetching the waitlist entry in the service section: %w", err)
+	}
+
+	return waitlistEntry, nil
+}
+
+// LeaveWaitlist takes a customer off the waitlist of a show. Seats held for them go to the next
+// customer in the queue.
+//
+// Params:
+//   - showID (int): The ID of the show.
+//   - userID (int): The ID of the customer.
+//
+// Returns:
+//   - error: ErrWaitlistEntryNotFound if the customer is not waiting for the show, or a wrapped error.
+func (bs *BookingService) LeaveWaitlist(showID, userID int) error {
+	released, err := bs.db.CancelWaitlistEntry(showID, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrWaitlistEntryNotFound) {
+			return ErrWaitlistEntryNotFound
+		}
+		return fmt.Errorf("error occurred while leaving the waitlist in the service section: %w", err)
+	}
+
+	if released {
+		bs.events.PublishSeatChange(showID, "released")
+		bs.offerWaitlistSeats(showID)
+	}
+
+	return nil
+}
+
+// offerWaitlistSeats offers the seats that have freed up on a show to its waitlist and notifies the
+// customers who got an offer. It runs after seats are given back, which has already succeeded, so a
+// failure is only logged; the next seats given back serve the queue again.
+func (bs *BookingService) offerWaitlistSeats(showID int) {
+	offers, err := bs.createWaitlistOffers(showID)
+	if err != nil {
+		log.Printf("error occurred while offering seats to the waitlist of show %d: %v", showID, err)
+		return
+	}
+
+	if len(offers) == 0 {
+		return
+	}
+
+	bs.events.PublishSeatChange(showID, "held")
+
+	for _, offer := range offers {
+		bs.waitlist.NotifyWaitlistOffer(offer)
+	}
+}
+
+// createWaitlistOffers serves the waitlist of a show first come first served: the earliest waiting
+// customer is offered seats under an exclusive hold, then the next one, for as long as there are
+// enough available seats. A customer who needs more seats than are available keeps their place,
+// and nobody behind them is served before them.
+func (bs *BookingService) createWaitlistOffers(showID int) ([]models.WaitlistOffer, error) {
+	tx, err := bs.db.BeginBookingTx()
+	if err != nil {
+		return nil, fmt.Errorf("error occurred while starting the waitlist transaction in the service section: %w", err)
+	}
+
+	// Roll back on every early return; this is a no-op once the transaction has been committed.
+	defer tx.Rollback()
+
+	// Close the offers whose hold has lapsed; their seats are offered again below.
+	if err := tx.ExpireWaitlistOffers(showID); err != nil {
+		return nil, fmt.Errorf("error occurred while expiring waitlist offers in the service section: %w", err)
+	}
+
+	// Lock the queue, so concurrent rounds of offers never serve the same customer twice.
+	waitingEntries, err := tx.LockWaitingEntries(showID)
+	if err != nil {
+		return nil, fmt.Errorf("error occurred while locking the waitlist in the service section: %w", err)
+	}
+
+	var offers []models.WaitlistOffer
+
+	if len(waitingEntries) > 0 {
+		showSeats, err := bs.db.RetrieveShowSeats(showID)
+		if err != nil && !errors.Is(err, models.ErrShowSeatNotFound) {
+			return nil, fmt.Errorf("error occurred while fetching show seats for the waitlist in the service section: %w", err)
+		}
+
+		for _, waitingEntry := range waitingEntries {
+			offeredSeats := PickWaitlistSeats(showSeats, waitingEntry.NumberOfSeats)
+			if offeredSeats == nil {
+				break
+			}
+
+			offer, ok, err := bs.offerSeats(tx, waitingEntry, offeredSeats)
+			if err != nil {
+				return nil, err
+			}
+
+			// The seats were taken in the meantime; the seats given back next serve the queue again.
+			if !ok {
+				break
+			}
+			offers = append(offers, offer)
+
+			// The offered seats are no longer available to the customers further down the queue.
+			for i := range showSeats {
+				for _, offeredSeat := range offeredSeats {
+					if showSeats[i].ShowSeatID == offeredSeat.ShowSeatID {
+						showSeats[i].SeatStatus = "Selected"
+					}
+				}
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error occurred while committing the waitlist offers in the service section: %w", err)
+	}
+
+	return offers, nil
+}
+
+// offerSeats places an exclusive hold on the offered seats for a waiting customer and records the offer.
+// It reports false if any of the seats is no longer available.
+func (bs *BookingService) offerSeats(tx models.BookingTx, waitingEntry models.WaitlistEntry, offeredSeats []models.ShowSeat) (models.WaitlistOffer, bool, error) {
+	showSeatsID := make([]int, 0, len(offeredSeats))
+	for _, offeredSeat := range offeredSeats {
+		showSeatsID = append(showSeatsID, offeredSeat.ShowSeatID)
+	}
+
+	// Lock the seats so no concurrent booking or hold can take them.
+	lockedSeats, err := tx.LockShowSeats(waitingEntry.ShowID, showSeatsID)
+	if err != nil {
+		if errors.Is(err, models.ErrShowSeatNotFound) {
+			return models.WaitlistOffer{}, false, nil
+		}
+		return models.WaitlistOffer{}, false, fmt.Errorf("error occurred while locking the offered seats in the service section: %w", err)
+	}
+
+	for _, lockedSeat := range lockedSeats {
+		if !isShowSeatBookable(lockedSeat, 0) {
+			return models.WaitlistOffer{}, false, nil
+		}
+	}
+
+	holdID, expiresAt, err := tx.InsertSeatHold(waitingEntry.UserID, waitingEntry.ShowID, bs.settings.WaitlistHoldMinutes)
+	if err != nil {
+		return models.WaitlistOffer{}, false, fmt.Errorf("error occurred while creating the waitlist seat hold in the service section: %w", err)
+	}
+
+	if err := tx.HoldShowSeats(holdID, waitingEntry.ShowID, showSeatsID); err != nil {
+		return models.WaitlistOffer{}, false, fmt.Errorf("error occurred while holding the offered seats in the service section: %w", err)
+	}
+
+	if err := tx.OfferWaitlistEntry(waitingEntry.WaitlistEntryID, holdID); err != nil {
+		return models.WaitlistOffer{}, false, fmt.Errorf("error occurred while recording the waitlist offer in the service section: %w", err)
+	}
+
+	return models.WaitlistOffer{
+		WaitlistEntryID: waitingEntry.WaitlistEntryID,
+		UserID:          waitingEntry.UserID,
+		SeatHold: models.SeatHold{
+			HoldID:      holdID,
+			ShowID:      waitingEntry.ShowID,
+			ShowSeatsID: showSeatsID,
+			ExpiresAt:   expiresAt,
+		},
+	}, true, nil
+}
@@ -0,0 +1,2 @@
+ALTER TABLE show_seat DROP COLUMN IF EXISTS hold_id;
+DROP TABLE IF EXISTS seat_hold;
@@ -0,0 +1,16 @@
+CREATE TABLE seat_hold (
+    hold_id SERIAL PRIMARY KEY,             -- Unique ID for each seat hold (auto-incremented)
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,      -- Foreign key to users table, the customer holding the seats
+    show_id INT REFERENCES show(show_id) ON DELETE CASCADE,  -- Foreign key to show table, the show the held seats belong to
+    status VARCHAR(50) NOT NULL DEFAULT 'Active' CHECK (status IN ('Active', 'Released', 'Expired', 'Converted')), -- Lifecycle of the hold
+    expires_at TIMESTAMPTZ NOT NULL,        -- Moment the hold lapses and its seats go back to "Available"
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP  -- When the hold was placed
+);
+
+CREATE INDEX idx_seat_hold_user_id ON seat_hold (user_id);
+CREATE INDEX idx_seat_hold_active_expires_at ON seat_hold (expires_at) WHERE status = 'Active';  -- Used by the background expirer
+
+-- Links a "Selected" show seat to the hold that reserves it (NULL when the seat is not held)
+ALTER TABLE show_seat ADD COLUMN hold_id INT REFERENCES seat_hold(hold_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_show_seat_hold_id ON show_seat (hold_id);
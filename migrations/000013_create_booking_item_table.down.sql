@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS booking_item;
+ALTER TABLE booking DROP COLUMN IF EXISTS total_amount;
+ALTER TABLE booking DROP COLUMN IF EXISTS fee_amount;
+ALTER TABLE booking DROP COLUMN IF EXISTS subtotal_amount;
@@ -0,0 +1,15 @@
+-- Totals of the price quote the customer accepted, stored on the booking itself (in cents)
+ALTER TABLE booking ADD COLUMN subtotal_amount INT NOT NULL DEFAULT 0;  -- Sum of the seat prices
+ALTER TABLE booking ADD COLUMN fee_amount INT NOT NULL DEFAULT 0;       -- Sum of the fees applied on top of the seats
+ALTER TABLE booking ADD COLUMN total_amount INT NOT NULL DEFAULT 0;     -- Amount charged for the booking (subtotal + fees)
+
+CREATE TABLE booking_item (
+    booking_item_id SERIAL PRIMARY KEY,     -- Unique ID for each line of a booking (auto-incremented)
+    booking_id INT REFERENCES booking(booking_id) ON DELETE CASCADE,  -- Foreign key to the booking the line belongs to
+    item_type VARCHAR(50) NOT NULL CHECK (item_type IN ('Seat', 'Fee')), -- What the line charges for
+    show_seat_id INT REFERENCES show_seat(show_seat_id) ON DELETE SET NULL, -- The seat charged for (NULL for non-seat lines)
+    description VARCHAR(255) NOT NULL,      -- Human-readable label (e.g., "Seat A5 (VIP)", "Booking fee")
+    amount INT NOT NULL                     -- Amount of the line in cents, as quoted at booking time
+);
+
+CREATE INDEX idx_booking_item_booking_id ON booking_item (booking_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_payment_booking_id;
+DROP INDEX IF EXISTS idx_payment_remote_transaction_id;
+ALTER TABLE payment DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE payment DROP COLUMN IF EXISTS created_at;
+ALTER TABLE payment DROP COLUMN IF EXISTS status;
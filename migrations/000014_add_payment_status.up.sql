@@ -0,0 +1,8 @@
+-- Lifecycle of a payment at the provider
+ALTER TABLE payment ADD COLUMN status VARCHAR(50) NOT NULL DEFAULT 'Pending' CHECK (status IN ('Pending', 'Authorized', 'Captured', 'Failed', 'Refunded'));
+ALTER TABLE payment ADD COLUMN created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP;  -- When the payment was recorded
+ALTER TABLE payment ADD COLUMN updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP;  -- When the payment status last changed
+
+-- Webhooks identify payments by the provider's transaction ID
+CREATE UNIQUE INDEX idx_payment_remote_transaction_id ON payment (remote_transaction_id) WHERE remote_transaction_id <> '';
+CREATE INDEX idx_payment_booking_id ON payment (booking_id);
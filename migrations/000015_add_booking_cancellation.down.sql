@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS refund;
+ALTER TABLE booking DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE booking DROP CONSTRAINT IF EXISTS booking_status_check;
+ALTER TABLE booking ALTER COLUMN status DROP NOT NULL;
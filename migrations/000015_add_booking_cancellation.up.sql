@@ -0,0 +1,16 @@
+-- Make the booking lifecycle explicit, including the "Cancelled" state
+UPDATE booking SET status = 'Pending' WHERE status IS NULL;
+ALTER TABLE booking ALTER COLUMN status SET NOT NULL;
+ALTER TABLE booking ADD CONSTRAINT booking_status_check CHECK (status IN ('Pending', 'Confirmed', 'Failed', 'Cancelled'));
+ALTER TABLE booking ADD COLUMN cancelled_at TIMESTAMPTZ;  -- When the customer cancelled the booking (NULL if never cancelled)
+
+CREATE TABLE refund (
+    refund_id SERIAL PRIMARY KEY,             -- Unique ID for each refund (auto-incremented)
+    payment_id INT REFERENCES payment(payment_id) ON DELETE CASCADE,  -- Foreign key to the payment being refunded
+    amount INT NOT NULL,                      -- Amount refunded in cents
+    remote_refund_id VARCHAR(255),            -- External refund ID from the payment provider
+    reason VARCHAR(255),                      -- Why the refund was issued (e.g., "Cancelled by customer")
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP  -- When the refund was issued
+);
+
+CREATE INDEX idx_refund_payment_id ON refund (payment_id);
@@ -0,0 +1,2 @@
+ALTER TABLE show_seat DROP COLUMN IF EXISTS admitted_by;
+ALTER TABLE show_seat DROP COLUMN IF EXISTS admitted_at;
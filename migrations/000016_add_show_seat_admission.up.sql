@@ -0,0 +1,3 @@
+-- Record when a booked seat was admitted at the door, so a ticket cannot be scanned twice
+ALTER TABLE show_seat ADD COLUMN admitted_at TIMESTAMPTZ;  -- When the ticket holder was let in (NULL until checked in)
+ALTER TABLE show_seat ADD COLUMN admitted_by INT REFERENCES users(id) ON DELETE SET NULL;  -- The helpdesk user who checked the ticket
@@ -0,0 +1,11 @@
+CREATE TABLE idempotency_key (
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,  -- Foreign key to the user who sent the request
+    idempotency_key VARCHAR(255) NOT NULL,    -- Value of the client's Idempotency-Key header
+    request_hash CHAR(64) NOT NULL,           -- SHA-256 of the request body, to detect a key reused for a different request
+    status_code INT,                          -- HTTP status of the first response (NULL while the first request is still running)
+    response_body BYTEA,                      -- Body of the first response, replayed to retries
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,  -- When the key was first used
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+CREATE INDEX idx_idempotency_key_created_at ON idempotency_key (created_at);  -- Index for sweeping expired keys
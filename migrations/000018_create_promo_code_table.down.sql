@@ -0,0 +1,6 @@
+DELETE FROM booking_item WHERE item_type = 'Discount';
+ALTER TABLE booking_item DROP CONSTRAINT IF EXISTS booking_item_item_type_check;
+ALTER TABLE booking_item ADD CONSTRAINT booking_item_item_type_check CHECK (item_type IN ('Seat', 'Fee'));
+ALTER TABLE booking DROP COLUMN IF EXISTS discount_amount;
+DROP TABLE IF EXISTS promo_code_redemption;
+DROP TABLE IF EXISTS promo_code;
@@ -0,0 +1,36 @@
+CREATE TABLE promo_code (
+    promo_code_id SERIAL PRIMARY KEY,         -- Unique ID for each promo code (auto-incremented)
+    code VARCHAR(50) NOT NULL UNIQUE,         -- The code customers type at checkout, stored in upper case (e.g., "SUMMER25")
+    description VARCHAR(255),                 -- Internal note about the campaign the code belongs to
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('Percentage', 'Fixed')),  -- How the discount is calculated
+    discount_value INT NOT NULL CHECK (discount_value > 0),  -- Percent off (1-100) for 'Percentage', amount off in cents for 'Fixed'
+    valid_from TIMESTAMPTZ NOT NULL,          -- The code can be used from this moment...
+    valid_until TIMESTAMPTZ NOT NULL,         -- ...until this moment
+    max_uses INT,                             -- How many bookings may use the code in total (NULL for no limit)
+    max_uses_per_user INT,                    -- How many bookings each user may use the code for (NULL for no limit)
+    movie_id INT REFERENCES movies(id) ON DELETE CASCADE,  -- Only valid for this movie (NULL for any movie)
+    hall_type VARCHAR(50),                    -- Only valid in halls of this type (NULL for any hall)
+    seat_type VARCHAR(20),                    -- Only discounts seats of this type (NULL for any seat)
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (valid_from < valid_until),
+    CHECK (discount_type <> 'Percentage' OR discount_value <= 100)
+);
+
+CREATE TABLE promo_code_redemption (
+    promo_code_redemption_id SERIAL PRIMARY KEY,  -- Unique ID for each use of a promo code (auto-incremented)
+    promo_code_id INT REFERENCES promo_code(promo_code_id) ON DELETE CASCADE,  -- Foreign key to the code that was used
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,  -- Foreign key to the user who used the code
+    booking_id INT REFERENCES booking(booking_id) ON DELETE CASCADE UNIQUE,  -- The booking the code was applied to (one code per booking)
+    discount_amount INT NOT NULL,             -- Discount granted in cents
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_promo_code_redemption_code_user ON promo_code_redemption (promo_code_id, user_id);  -- Index for counting uses per code and user
+
+-- Discount taken off the booking's total (in cents)
+ALTER TABLE booking ADD COLUMN discount_amount INT NOT NULL DEFAULT 0;
+
+-- Allow discount lines next to seats and fees
+ALTER TABLE booking_item DROP CONSTRAINT IF EXISTS booking_item_item_type_check;
+ALTER TABLE booking_item ADD CONSTRAINT booking_item_item_type_check CHECK (item_type IN ('Seat', 'Fee', 'Discount'));
@@ -0,0 +1,13 @@
+CREATE TABLE price_rule (
+    price_rule_id SERIAL PRIMARY KEY,         -- Unique ID for each price rule (auto-incremented)
+    rule_name VARCHAR(100) NOT NULL,          -- Label shown to admins (e.g., "Matinee", "Late night VIP")
+    hall_type VARCHAR(50),                    -- Only applies to halls of this type (NULL for any hall)
+    seat_type VARCHAR(20),                    -- Only applies to seats of this type (NULL for any seat)
+    day_of_week INT CHECK (day_of_week BETWEEN 0 AND 6),  -- Only applies on this weekday, 0 = Sunday (NULL for any day)
+    start_time_from TIME,                     -- Only applies to shows starting at or after this time...
+    start_time_until TIME,                    -- ...and before this time; a band may wrap past midnight (NULL for any time)
+    price INT NOT NULL CHECK (price > 0),     -- Seat price in cents
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((start_time_from IS NULL) = (start_time_until IS NULL))  -- A time band needs both ends
+);
@@ -0,0 +1,4 @@
+ALTER TABLE booking_item DROP COLUMN IF EXISTS seat_price_history_id;
+DROP TABLE IF EXISTS seat_price_history;
+DROP TABLE IF EXISTS show_dynamic_pricing;
+ALTER TABLE show_seat DROP COLUMN IF EXISTS base_price;
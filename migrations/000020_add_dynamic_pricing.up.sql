@@ -0,0 +1,31 @@
+-- Price set by the admin or a price rule; dynamic pricing adjusts show_seat.price around it (in cents)
+ALTER TABLE show_seat ADD COLUMN base_price INT NOT NULL DEFAULT 0;
+UPDATE show_seat SET base_price = COALESCE(price, 0);
+
+CREATE TABLE show_dynamic_pricing (
+    show_id INT PRIMARY KEY REFERENCES show(show_id) ON DELETE CASCADE,  -- The show whose remaining seats follow demand
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,    -- Whether prices are currently adjusted
+    min_price_percent INT NOT NULL CHECK (min_price_percent > 0),  -- Floor, as a percentage of each seat's base price
+    max_price_percent INT NOT NULL,           -- Ceiling, as a percentage of each seat's base price
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (min_price_percent <= 100 AND max_price_percent >= 100)
+);
+
+CREATE TABLE seat_price_history (
+    seat_price_history_id SERIAL PRIMARY KEY, -- Unique ID for each price change (auto-incremented)
+    show_seat_id INT REFERENCES show_seat(show_seat_id) ON DELETE CASCADE,  -- Foreign key to the seat whose price changed
+    old_price INT NOT NULL,                   -- Price before the change in cents
+    new_price INT NOT NULL,                   -- Price after the change in cents
+    reason VARCHAR(255) NOT NULL,             -- Why the price changed (e.g., "Price rule: Matinee", "Dynamic pricing: 80% booked (+25%)")
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP  -- When the price changed
+);
+
+CREATE INDEX idx_seat_price_history_show_seat_id ON seat_price_history (show_seat_id, seat_price_history_id);
+
+-- Start the history of seats that were priced before it was recorded
+INSERT INTO seat_price_history (show_seat_id, old_price, new_price, reason)
+SELECT show_seat_id, 0, price, 'Price before history was recorded' FROM show_seat WHERE price > 0;
+
+-- The price change a booked seat was charged at, so every booking can explain its prices
+ALTER TABLE booking_item ADD COLUMN seat_price_history_id INT REFERENCES seat_price_history(seat_price_history_id) ON DELETE SET NULL;
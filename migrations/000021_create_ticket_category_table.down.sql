@@ -0,0 +1,2 @@
+ALTER TABLE booking_item DROP COLUMN IF EXISTS ticket_category_id;
+DROP TABLE IF EXISTS ticket_category;
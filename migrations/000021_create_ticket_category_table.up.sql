@@ -0,0 +1,20 @@
+CREATE TABLE ticket_category (
+    ticket_category_id SERIAL PRIMARY KEY,    -- Unique ID for each ticket category (auto-incremented)
+    name VARCHAR(50) NOT NULL UNIQUE,         -- Category customers pick per seat (e.g., "Adult", "Child")
+    price_percent INT NOT NULL CHECK (price_percent > 0),  -- Share of the seat price charged, 100 = full price
+    min_age INT CHECK (min_age >= 0),         -- Youngest age the category is for (NULL for no minimum)
+    max_age INT CHECK (max_age >= 0),         -- Oldest age the category is for (NULL for no maximum)
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (min_age IS NULL OR max_age IS NULL OR min_age <= max_age)
+);
+
+-- Seats booked without a category are sold as "Adult" tickets
+INSERT INTO ticket_category (name, price_percent, min_age, max_age) VALUES
+    ('Adult', 100, NULL, NULL),
+    ('Child', 60, NULL, 12),
+    ('Senior', 75, 65, NULL),
+    ('Student', 80, NULL, NULL);
+
+-- The ticket category each booked seat was sold as
+ALTER TABLE booking_item ADD COLUMN ticket_category_id INT REFERENCES ticket_category(ticket_category_id) ON DELETE SET NULL;
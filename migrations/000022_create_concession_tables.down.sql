@@ -0,0 +1,7 @@
+DELETE FROM booking_item WHERE item_type = 'Concession';
+ALTER TABLE booking_item DROP CONSTRAINT IF EXISTS booking_item_item_type_check;
+ALTER TABLE booking_item ADD CONSTRAINT booking_item_item_type_check CHECK (item_type IN ('Seat', 'Fee', 'Discount'));
+ALTER TABLE booking DROP COLUMN IF EXISTS concessions_amount;
+DROP TABLE IF EXISTS booking_concession;
+DROP TABLE IF EXISTS concession_combo_item;
+DROP TABLE IF EXISTS concession_item;
@@ -0,0 +1,38 @@
+CREATE TABLE concession_item (
+    concession_item_id SERIAL PRIMARY KEY,    -- Unique ID for each food or drink item (auto-incremented)
+    name VARCHAR(100) NOT NULL UNIQUE,        -- Name shown to customers (e.g., "Large popcorn")
+    description TEXT,                         -- Optional details (e.g., size, flavours)
+    category VARCHAR(20) NOT NULL CHECK (category IN ('Food', 'Drink', 'Combo')),  -- Where the item is listed
+    price INT NOT NULL CHECK (price > 0),     -- Price in cents
+    stock INT CHECK (stock >= 0),             -- Units left to sell (NULL for unlimited; always NULL for combos)
+    active BOOLEAN NOT NULL DEFAULT TRUE,     -- Whether customers can order the item
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE concession_combo_item (
+    combo_id INT REFERENCES concession_item(concession_item_id) ON DELETE CASCADE,      -- The combo bundle
+    component_id INT REFERENCES concession_item(concession_item_id) ON DELETE RESTRICT, -- An item the combo contains
+    quantity INT NOT NULL CHECK (quantity > 0),  -- How many of the item one combo contains
+    PRIMARY KEY (combo_id, component_id),
+    CHECK (combo_id <> component_id)
+);
+
+CREATE TABLE booking_concession (
+    booking_concession_id SERIAL PRIMARY KEY, -- Unique ID for each ordered item (auto-incremented)
+    booking_id INT REFERENCES booking(booking_id) ON DELETE CASCADE,  -- The booking the item was ordered with
+    concession_item_id INT REFERENCES concession_item(concession_item_id) ON DELETE RESTRICT,  -- The ordered item
+    quantity INT NOT NULL CHECK (quantity > 0),  -- How many were ordered
+    unit_price INT NOT NULL,                  -- Price of one unit when ordered, in cents
+    pickup_status VARCHAR(20) NOT NULL DEFAULT 'Pending' CHECK (pickup_status IN ('Pending', 'Collected', 'Cancelled')),
+    collected_at TIMESTAMPTZ,                 -- When the customer collected the item
+    collected_by INT REFERENCES users(id) ON DELETE SET NULL  -- The helpdesk user who handed it over
+);
+
+CREATE INDEX idx_booking_concession_booking_id ON booking_concession (booking_id);
+
+-- Concessions are charged on top of the seats
+ALTER TABLE booking ADD COLUMN concessions_amount INT NOT NULL DEFAULT 0;
+
+ALTER TABLE booking_item DROP CONSTRAINT IF EXISTS booking_item_item_type_check;
+ALTER TABLE booking_item ADD CONSTRAINT booking_item_item_type_check CHECK (item_type IN ('Seat', 'Fee', 'Discount', 'Concession'));
@@ -0,0 +1,2 @@
+ALTER TABLE booking DROP CONSTRAINT IF EXISTS booking_booking_reference_key;
+ALTER TABLE booking DROP COLUMN IF EXISTS booking_reference;
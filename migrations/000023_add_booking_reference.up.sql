@@ -0,0 +1,23 @@
+-- Short, non-guessable code customers quote instead of the booking ID (e.g., "CG-7KQ2MX")
+ALTER TABLE booking ADD COLUMN booking_reference VARCHAR(9);
+
+-- Give every existing booking a reference, drawn from the same alphabet as the application
+-- (digits and letters without 0, 1, I and O), retrying the rare collision
+DO $$
+DECLARE
+    existing RECORD;
+    candidate VARCHAR(9);
+BEGIN
+    FOR existing IN SELECT booking_id FROM booking LOOP
+        LOOP
+            SELECT 'CG-' || STRING_AGG(SUBSTR('23456789ABCDEFGHJKLMNPQRSTUVWXYZ', FLOOR(RANDOM() * 32)::INT + 1, 1), '')
+                INTO candidate FROM GENERATE_SERIES(1, 6);
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM booking WHERE booking_reference = candidate);
+        END LOOP;
+
+        UPDATE booking SET booking_reference = candidate WHERE booking_id = existing.booking_id;
+    END LOOP;
+END $$;
+
+ALTER TABLE booking ALTER COLUMN booking_reference SET NOT NULL;
+ALTER TABLE booking ADD CONSTRAINT booking_booking_reference_key UNIQUE (booking_reference);
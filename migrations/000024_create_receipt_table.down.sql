@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS receipt;
+DROP TABLE IF EXISTS receipt_counter;
@@ -0,0 +1,17 @@
+-- Single-row counter receipt numbers are drawn from. Unlike a sequence, it is updated inside the
+-- transaction that issues the receipt, so a rolled back payment never leaves a gap in the numbering.
+CREATE TABLE receipt_counter (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),  -- Only one row may exist
+    last_number BIGINT NOT NULL DEFAULT 0     -- The last receipt number issued
+);
+
+INSERT INTO receipt_counter (id, last_number) VALUES (TRUE, 0);
+
+CREATE TABLE receipt (
+    receipt_id SERIAL PRIMARY KEY,            -- Unique ID for each receipt (auto-incremented)
+    receipt_number BIGINT NOT NULL UNIQUE,    -- Sequential number printed on the receipt
+    booking_id INT NOT NULL UNIQUE REFERENCES booking(booking_id) ON DELETE RESTRICT,  -- The booking that was paid
+    payment_id INT REFERENCES payment(payment_id) ON DELETE RESTRICT,  -- The payment the receipt is for
+    vat_rate INT NOT NULL CHECK (vat_rate >= 0),  -- VAT rate included in the prices, in basis points (1200 = 12%)
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP  -- When the receipt was issued
+);
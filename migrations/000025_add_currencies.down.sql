@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS exchange_rate;
+ALTER TABLE cinema_hall DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,8 @@
+-- Currency every price of a hall is kept and charged in (ISO 4217, e.g., 'UZS', 'KZT')
+ALTER TABLE cinema_hall ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'UZS';
+
+CREATE TABLE exchange_rate (
+    currency CHAR(3) PRIMARY KEY,             -- The currency the rate is for (ISO 4217)
+    rate NUMERIC(24, 10) NOT NULL CHECK (rate > 0),  -- Units of the default currency one unit of this currency is worth
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP  -- When the rate was last set
+);
@@ -0,0 +1,14 @@
+CREATE TABLE waitlist_entry (
+    waitlist_entry_id SERIAL PRIMARY KEY,     -- Unique ID for each waitlist entry (auto-incremented)
+    show_id INT NOT NULL REFERENCES show(show_id) ON DELETE CASCADE,  -- The sold-out show the customer is waiting for
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,      -- The waiting customer
+    number_of_seats INT NOT NULL CHECK (number_of_seats BETWEEN 1 AND 5),  -- How many seats the customer needs
+    status VARCHAR(50) NOT NULL DEFAULT 'Waiting' CHECK (status IN ('Waiting', 'Offered', 'Fulfilled', 'Expired', 'Cancelled')), -- Lifecycle of the entry
+    hold_id INT REFERENCES seat_hold(hold_id) ON DELETE SET NULL,     -- The exclusive hold placed for the customer once seats are offered
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,       -- When the customer joined; the queue is served in this order
+    offered_at TIMESTAMPTZ                    -- When seats were offered to the customer
+);
+
+-- A customer can only wait once for a show at a time
+CREATE UNIQUE INDEX idx_waitlist_entry_show_user_open ON waitlist_entry (show_id, user_id) WHERE status IN ('Waiting', 'Offered');
+CREATE INDEX idx_waitlist_entry_show_waiting ON waitlist_entry (show_id, created_at) WHERE status = 'Waiting';  -- Used to serve the queue in order
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS hall_layout_marker;
+DROP TABLE IF EXISTS hall_layout;
+ALTER TABLE cinema_seat DROP COLUMN IF EXISTS angle;
+ALTER TABLE cinema_seat DROP COLUMN IF EXISTS grid_y;
+ALTER TABLE cinema_seat DROP COLUMN IF EXISTS grid_x;
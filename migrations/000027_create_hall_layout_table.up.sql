@@ -0,0 +1,25 @@
+-- Where each seat is drawn on the seat map, in grid cells counted from 1 (NULL until the hall has a layout)
+ALTER TABLE cinema_seat ADD COLUMN grid_x INT CHECK (grid_x >= 1);
+ALTER TABLE cinema_seat ADD COLUMN grid_y INT CHECK (grid_y >= 1);
+-- Rotation of the seat in degrees, so curved rows can be drawn facing the screen
+ALTER TABLE cinema_seat ADD COLUMN angle INT NOT NULL DEFAULT 0 CHECK (angle BETWEEN -90 AND 90);
+
+CREATE TABLE hall_layout (
+    hall_id INT PRIMARY KEY REFERENCES cinema_hall(cinema_hall_id) ON DELETE CASCADE,  -- The hall the layout draws
+    grid_columns INT NOT NULL CHECK (grid_columns BETWEEN 1 AND 100),  -- Width of the seat map in grid cells
+    grid_rows INT NOT NULL CHECK (grid_rows BETWEEN 1 AND 100),        -- Depth of the seat map in grid cells
+    screen_position VARCHAR(10) NOT NULL DEFAULT 'Top' CHECK (screen_position IN ('Top', 'Bottom')),  -- Side of the map the screen is drawn on
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP       -- When the layout was last uploaded
+);
+
+CREATE TABLE hall_layout_marker (
+    hall_layout_marker_id SERIAL PRIMARY KEY,  -- Unique ID for each marker (auto-incremented)
+    hall_id INT NOT NULL REFERENCES hall_layout(hall_id) ON DELETE CASCADE,  -- The layout the marker belongs to
+    marker_type VARCHAR(10) NOT NULL CHECK (marker_type IN ('Aisle', 'Stairs', 'Exit')),  -- What is drawn on the covered cells
+    grid_x INT NOT NULL CHECK (grid_x >= 1),   -- Left-most grid cell covered by the marker
+    grid_y INT NOT NULL CHECK (grid_y >= 1),   -- Top-most grid cell covered by the marker
+    width INT NOT NULL DEFAULT 1 CHECK (width >= 1),    -- Number of columns covered
+    height INT NOT NULL DEFAULT 1 CHECK (height >= 1)   -- Number of rows covered
+);
+
+CREATE INDEX idx_hall_layout_marker_hall_id ON hall_layout_marker (hall_id);
@@ -0,0 +1 @@
+ALTER TABLE idempotency_key ADD COLUMN claim_token VARCHAR(32) NOT NULL DEFAULT '';  -- Token of the request holding the key; only it may store the response or release the key
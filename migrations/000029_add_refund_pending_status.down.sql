@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_refund_pending;
+UPDATE payment SET status = 'Captured' WHERE status = 'RefundPending';
+ALTER TABLE payment DROP CONSTRAINT IF EXISTS payment_status_check;
+ALTER TABLE payment ADD CONSTRAINT payment_status_check CHECK (status IN ('Pending', 'Authorized', 'Captured', 'Failed', 'Refunded'));
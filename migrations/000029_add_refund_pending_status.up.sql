@@ -0,0 +1,5 @@
+-- A cancelled booking's refund is recorded as pending before the provider is asked to pay it out
+ALTER TABLE payment DROP CONSTRAINT IF EXISTS payment_status_check;
+ALTER TABLE payment ADD CONSTRAINT payment_status_check CHECK (status IN ('Pending', 'Authorized', 'Captured', 'Failed', 'Refunded', 'RefundPending'));
+
+CREATE INDEX idx_refund_pending ON refund (payment_id) WHERE remote_refund_id IS NULL;  -- Index for settling pending refunds
@@ -3,6 +3,7 @@ package configs
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
 // LoadEnvironmentVariable retrieves the value of an environment variable
@@ -25,3 +26,78 @@ func LoadEnvironmentVariable(key string) (string, error) {
 
 	return value, nil
 }
+
+// LoadEnvironmentVariableOrDefault retrieves the value of an optional environment
+// variable by its key. If the environment variable is not set or is empty, it returns
+// the provided fallback value.
+//
+// Parameters:
+//
+//	key (string): The name of the environment variable to retrieve.
+//	fallback (string): The value to use when the environment variable is not set.
+//
+// Returns:
+//
+//	string: The value of the environment variable, or the fallback if it is not set.
+func LoadEnvironmentVariableOrDefault(key, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// LoadIntEnvironmentVariable retrieves an optional integer environment variable
+// by its key. If the environment variable is not set or is empty, it returns the
+// provided fallback value.
+//
+// Parameters:
+//
+//	key (string): The name of the environment variable to retrieve.
+//	fallback (int): The value to use when the environment variable is not set.
+//
+// Returns:
+//
+//	int: The value of the environment variable, or the fallback if it is not set.
+//	error: An error if the environment variable is set but is not an integer.
+func LoadIntEnvironmentVariable(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("config: environment variable %s must be an integer: %w", key, err)
+	}
+
+	return intValue, nil
+}
+
+// LoadBoolEnvironmentVariable retrieves the value of an environment variable, specified
+// by its key, as a boolean. If the environment variable is not set or is empty, it returns
+// the provided fallback value.
+//
+// Parameters:
+//
+//	key (string): The name of the environment variable to retrieve.
+//	fallback (bool): The value to use when the environment variable is not set.
+//
+// Returns:
+//
+//	bool: The value of the environment variable, or the fallback if it is not set.
+//	error: An error if the environment variable is set but is not a boolean (e.g., "true", "0").
+func LoadBoolEnvironmentVariable(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("config: environment variable %s must be a boolean: %w", key, err)
+	}
+
+	return boolValue, nil
+}
@@ -0,0 +1,221 @@
+package modelstests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateShowSeatPrices(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	t.Run("by_seat_type_and_rows", func(t *testing.T) {
+		mock.ExpectQuery("WITH old AS \\(SELECT ss.show_seat_id, .* WHERE ss.show_id = \\$2 .*\\(UPDATE show_seat ss SET price = \\$1, base_price = \\$1 .*\\(INSERT INTO seat_price_history").
+			WithArgs(1800, 3, "VIP", "A", "D").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(24))
+
+		updated, err := psql.UpdateShowSeatPrices(3, uzs(1800), "VIP", "A", "D")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 24, updated)
+	})
+
+	t.Run("no_matching_seat", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE show_seat ss SET price").
+			WithArgs(1800, 3, "", "", "").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		_, err := psql.UpdateShowSeatPrices(3, uzs(1800), "", "", "")
+
+		assert.ErrorIs(t, err, models.ErrShowSeatNotFound)
+	})
+
+	t.Run("copy_between_shows", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE show_seat ss SET price = old.new_price, base_price = old.new_price").
+			WithArgs(3, 4, "Copied from show 3").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(120))
+
+		updated, err := psql.CopyShowSeatPrices(3, 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 120, updated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReplaceHallLayout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	hallLayout := models.HallLayout{
+		HallID:         2,
+		Columns:        4,
+		Rows:           1,
+		ScreenPosition: "Top",
+		Markers:        []models.HallLayoutMarker{{MarkerType: "Aisle", GridX: 3, GridY: 1, Width: 1, Height: 1}},
+		Seats: []models.HallLayoutSeat{
+			{SeatRow: "A", SeatNumber: 1, SeatType: "Standard", GridX: 1, GridY: 1},
+			{SeatRow: "A", SeatNumber: 2, SeatType: "Standard", GridX: 2, GridY: 1},
+		},
+	}
+
+	expectLayout := func(capacity int) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT capacity FROM cinema_hall WHERE cinema_hall_id = \\$1 FOR UPDATE").
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(capacity))
+		mock.ExpectExec("INSERT INTO hall_layout .* ON CONFLICT \\(hall_id\\) DO UPDATE").
+			WithArgs(2, 4, 1, "Top").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM hall_layout_marker WHERE hall_id = \\$1").
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO hall_layout_marker").
+			WithArgs(2, "Aisle", 3, 1, 1, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery("INSERT INTO cinema_seat .* ON CONFLICT \\(hall_id, seat_row, seat_number\\) DO UPDATE .* RETURNING xmax = 0").
+			WithArgs("A", 1, "Standard", 2, 1, 1, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"inserted"}).AddRow(false))
+		mock.ExpectQuery("INSERT INTO cinema_seat").
+			WithArgs("A", 2, "Standard", 2, 2, 1, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"inserted"}).AddRow(true))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FILTER \\(WHERE grid_x IS NULL OR grid_y IS NULL\\), COUNT\\(\\*\\) FROM cinema_seat WHERE hall_id = \\$1").
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"unplaced", "total"}).AddRow(0, 2))
+	}
+
+	t.Run("creates_new_seats", func(t *testing.T) {
+		expectLayout(10)
+		mock.ExpectCommit()
+
+		created, err := psql.ReplaceHallLayout(hallLayout)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, created)
+	})
+
+	t.Run("over_capacity", func(t *testing.T) {
+		expectLayout(1)
+		mock.ExpectRollback()
+
+		_, err := psql.ReplaceHallLayout(hallLayout)
+
+		assert.ErrorIs(t, err, models.ErrCinemaHallCapacityExceeded)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCloneHallSeats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	expectTargetHall := func(capacity int, hasSeats bool) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT capacity FROM cinema_hall WHERE cinema_hall_id = \\$1 FOR UPDATE").
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(capacity))
+		mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM cinema_seat WHERE hall_id = \\$1\\)").
+			WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(hasSeats))
+	}
+
+	expectCopy := func(copied int64) {
+		mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM cinema_hall WHERE cinema_hall_id = \\$1\\)").
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectExec("DELETE FROM hall_layout WHERE hall_id = \\$1").
+			WithArgs(5).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO hall_layout \\(hall_id, grid_columns, grid_rows, screen_position\\) SELECT \\$2").
+			WithArgs(2, 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO hall_layout_marker .* SELECT \\$2").
+			WithArgs(2, 5).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec("INSERT INTO cinema_seat .* SELECT seat_row, seat_number, seat_type, \\$2, grid_x, grid_y, angle FROM cinema_seat WHERE hall_id = \\$1").
+			WithArgs(2, 5).
+			WillReturnResult(sqlmock.NewResult(0, copied))
+	}
+
+	t.Run("copies_into_empty_hall", func(t *testing.T) {
+		expectTargetHall(100, false)
+		expectCopy(80)
+		mock.ExpectCommit()
+
+		created, err := psql.CloneHallSeats(2, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 80, created)
+	})
+
+	t.Run("hall_not_empty", func(t *testing.T) {
+		expectTargetHall(100, true)
+		mock.ExpectRollback()
+
+		_, err := psql.CloneHallSeats(2, 5)
+
+		assert.ErrorIs(t, err, models.ErrCinemaHallNotEmpty)
+	})
+
+	t.Run("over_capacity", func(t *testing.T) {
+		expectTargetHall(50, false)
+		expectCopy(80)
+		mock.ExpectRollback()
+
+		_, err := psql.CloneHallSeats(2, 5)
+
+		assert.ErrorIs(t, err, models.ErrCinemaHallCapacityExceeded)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteWithPaidBookings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	// The receipt of a paid booking stops the cascade from the show or the movie down to the booking.
+	t.Run("show", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM show WHERE show_id = \\$1").WithArgs(3).WillReturnError(&pq.Error{Code: "23503"})
+
+		err := psql.DeleteShowByID(3)
+
+		assert.ErrorIs(t, err, models.ErrShowHasPaidBookings)
+	})
+
+	t.Run("movie", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM movies WHERE id = \\$1").WithArgs(4).WillReturnError(&pq.Error{Code: "23503"})
+
+		err := psql.DeleteMovieByMovieID(4)
+
+		assert.ErrorIs(t, err, models.ErrMovieHasPaidBookings)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,507 @@
+package modelstests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookingTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT ss.show_seat_id, cs.seat_row, cs.seat_number, cs.seat_type, ss.status, ss.price, ch.currency, sh.user_id, .* FROM show_seat ss .* FOR UPDATE OF ss").
+			WillReturnRows(sqlmock.NewRows([]string{"show_seat_id", "seat_row", "seat_number", "seat_type", "status", "price", "currency", "user_id", "seat_price_history_id"}).
+				AddRow(1, "A", 5, "VIP", "Available", 1500, "UZS", nil, 42).AddRow(2, "A", 6, "VIP", "Selected", nil, "UZS", 7, nil))
+		mock.ExpectQuery("INSERT INTO booking").WithArgs(2, "Pending", 7, 3, 1500, 0, 100, 0, 1600, "CG-7KQ2MX").
+			WillReturnRows(sqlmock.NewRows([]string{"booking_id"}).AddRow(11))
+		mock.ExpectExec("INSERT INTO booking_item").WithArgs(11, "Seat", 1, "Seat A5 (VIP, Adult)", 1500, 42, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO booking_item").WithArgs(11, "Fee", nil, "Booking fee", 100, nil, nil).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec("UPDATE show_seat SET status = \\$1, booking_id = \\$2, hold_id = NULL").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO payment").WithArgs(1600, "fake_txn_11_1600", "Fake", "Authorized", 11).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		tx, err := psql.BeginBookingTx()
+		assert.NoError(t, err)
+
+		lockedSeats, err := tx.LockShowSeats(3, []int{1, 2})
+		assert.NoError(t, err)
+		assert.Len(t, lockedSeats, 2)
+		assert.Equal(t, uzs(1500), lockedSeats[0].SeatPrice)
+		assert.Equal(t, "A", lockedSeats[0].SeatRow)
+		assert.Equal(t, 5, lockedSeats[0].SeatNumber)
+		assert.Equal(t, 0, lockedSeats[0].HoldUserID)
+		assert.Equal(t, 42, lockedSeats[0].PriceHistoryID)
+		assert.Equal(t, uzs(0), lockedSeats[1].SeatPrice)
+		assert.Equal(t, 7, lockedSeats[1].HoldUserID)
+
+		quote := models.PriceQuote{
+			ShowID: 3,
+			Items: []models.PriceQuoteItem{
+				{ItemType: "Seat", ShowSeatID: 1, Description: "Seat A5 (VIP, Adult)", Amount: uzs(1500), SeatPriceHistoryID: 42, TicketCategoryID: 1, TicketCategory: "Adult"},
+				{ItemType: "Fee", Description: "Booking fee", Amount: uzs(100)},
+			},
+			Subtotal: uzs(1500),
+			Fees:     uzs(100),
+			Total:    uzs(1600),
+		}
+
+		bookingID, err := tx.InsertNewBooking(2, "Pending", "CG-7KQ2MX", quote, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, bookingID)
+
+		assert.NoError(t, tx.InsertBookingItems(bookingID, quote.Items))
+
+		assert.NoError(t, tx.LinkShowSeatsToBooking("Booked", bookingID, 3, []int{1, 2}))
+		assert.NoError(t, tx.InsertPaymentDetails(quote.Total, "fake_txn_11_1600", "Fake", "Authorized", bookingID))
+		assert.NoError(t, tx.Commit())
+		assert.NoError(t, tx.Rollback())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("seat_not_in_show", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT ss.show_seat_id, cs.seat_row, cs.seat_number, cs.seat_type, ss.status, ss.price, ch.currency, sh.user_id, .* FROM show_seat ss").
+			WillReturnRows(sqlmock.NewRows([]string{"show_seat_id", "seat_row", "seat_number", "seat_type", "status", "price", "currency", "user_id", "seat_price_history_id"}).
+				AddRow(1, "A", 5, "VIP", "Available", 1500, "UZS", nil, nil))
+		mock.ExpectRollback()
+
+		tx, err := psql.BeginBookingTx()
+		assert.NoError(t, err)
+
+		lockedSeats, err := tx.LockShowSeats(3, []int{1, 99})
+		assert.ErrorIs(t, err, models.ErrShowSeatNotFound)
+		assert.Nil(t, lockedSeats)
+
+		assert.NoError(t, tx.Rollback())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("partial_link", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE show_seat SET status = \\$1, booking_id = \\$2").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+
+		tx, err := psql.BeginBookingTx()
+		assert.NoError(t, err)
+
+		err = tx.LinkShowSeatsToBooking("Booked", 11, 3, []int{1, 2})
+		assert.ErrorIs(t, err, models.ErrShowSeatNotFound)
+
+		assert.NoError(t, tx.Rollback())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("begin_error", func(t *testing.T) {
+		mock.ExpectBegin().WillReturnError(fmt.Errorf("connection lost"))
+
+		tx, err := psql.BeginBookingTx()
+
+		assert.Error(t, err)
+		assert.Nil(t, tx)
+		assert.Contains(t, err.Error(), "failed to begin booking transaction")
+	})
+}
+
+func TestExpireSeatHolds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("WITH expired AS \\(UPDATE seat_hold SET status = 'Expired'.* SELECT DISTINCT show_id FROM released").
+			WillReturnRows(sqlmock.NewRows([]string{"show_id"}).AddRow(3).AddRow(5))
+
+		showIDs, err := psql.ExpireSeatHolds()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3, 5}, showIDs)
+	})
+
+	t.Run("nothing_expired", func(t *testing.T) {
+		mock.ExpectQuery("WITH expired AS").WillReturnRows(sqlmock.NewRows([]string{"show_id"}))
+
+		showIDs, err := psql.ExpireSeatHolds()
+
+		assert.NoError(t, err)
+		assert.Empty(t, showIDs)
+	})
+
+	t.Run("query_error", func(t *testing.T) {
+		mock.ExpectQuery("WITH expired AS").WillReturnError(fmt.Errorf("query failed"))
+
+		showIDs, err := psql.ExpireSeatHolds()
+
+		assert.Error(t, err)
+		assert.Nil(t, showIDs)
+		assert.Contains(t, err.Error(), "failed to expire seat holds")
+	})
+}
+
+func TestRetrievePendingRefunds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT p.booking_id, p.remote_transaction_id, r.amount, ch.currency FROM payment p .* WHERE p.status = 'RefundPending'").
+			WillReturnRows(sqlmock.NewRows([]string{"booking_id", "remote_transaction_id", "amount", "currency"}).AddRow(7, "txn_7", 5000, "USD"))
+
+		pendingRefunds, err := psql.RetrievePendingRefunds()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.PendingRefund{{BookingID: 7, RemoteTransactionID: "txn_7", Amount: models.NewMoney(5000, "USD")}}, pendingRefunds)
+	})
+
+	t.Run("query_error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT p.booking_id").WillReturnError(fmt.Errorf("query failed"))
+
+		pendingRefunds, err := psql.RetrievePendingRefunds()
+
+		assert.Error(t, err)
+		assert.Nil(t, pendingRefunds)
+		assert.Contains(t, err.Error(), "failed to retrieve pending refunds")
+	})
+}
+
+func TestRetrieveShowSeats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	t.Run("unpriced_seat", func(t *testing.T) {
+		// Seats without a price are read as free instead of failing the whole seat map.
+		mock.ExpectQuery("SELECT cs.seat_row, .* COALESCE\\(ss.price, 0\\), ch.currency").
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"seat_row", "seat_number", "seat_type", "show_seat_id", "status", "price", "currency", "grid_x", "grid_y", "angle"}).
+				AddRow("A", 1, "Standard", 11, "Available", 5000, "UZS", 1, 1, 0).
+				AddRow("A", 2, "Standard", 12, "Available", 0, "UZS", 2, 1, 0))
+
+		showSeats, err := psql.RetrieveShowSeats(3)
+
+		assert.NoError(t, err)
+		assert.Len(t, showSeats, 2)
+		assert.Equal(t, uzs(5000), showSeats[0].SeatPrice)
+		assert.Equal(t, uzs(0), showSeats[1].SeatPrice)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserBookings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	historyColumns := []string{"booking_id", "status", "booking_reference", "show_id", "title", "poster_url", "hall_name", "hall_type", "show_date", "start_time", "number_of_seats", "seats", "amount", "refunded", "currency", "payment_status"}
+
+	t.Run("upcoming_page", func(t *testing.T) {
+		mock.ExpectQuery("SELECT b.booking_id, b.status, .* WHERE b.user_id = \\$1 AND s.show_date \\+ s.start_time >= LOCALTIMESTAMP GROUP BY .* ORDER BY s.show_date, s.start_time, b.booking_id LIMIT \\$2 OFFSET \\$3").
+			WithArgs(7, 10, 10).
+			WillReturnRows(sqlmock.NewRows(historyColumns).
+				AddRow(11, "Confirmed", "CG-7KQ2MX", 3, "Dune", "dune.jpg", "Hall 1", "IMAX", "2026-11-02", "18:30", 2, "{A5,A6}", 3100, 0, "UZS", "Captured"))
+
+		bookings, err := psql.RetrieveUserBookings(7, "upcoming", 10, 10)
+
+		assert.NoError(t, err)
+		assert.Len(t, bookings, 1)
+		assert.Equal(t, []string{"A5", "A6"}, bookings[0].Seats)
+		assert.Equal(t, uzs(3100), bookings[0].AmountPaid)
+		assert.Equal(t, "CG-7KQ2MX", bookings[0].BookingReference)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no_bookings", func(t *testing.T) {
+		mock.ExpectQuery("SELECT b.booking_id, b.status").WithArgs(7, 10, 0).WillReturnRows(sqlmock.NewRows(historyColumns))
+
+		bookings, err := psql.RetrieveUserBookings(7, "", 10, 0)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, bookings)
+		assert.Empty(t, bookings)
+	})
+
+	t.Run("count_past", func(t *testing.T) {
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM booking b .* AND s.show_date \\+ s.start_time < LOCALTIMESTAMP").WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+		total, err := psql.CountUserBookings(7, "past")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, total)
+	})
+
+	t.Run("detail", func(t *testing.T) {
+		mock.ExpectQuery("SELECT b.booking_id, b.status, .* b.subtotal_amount, b.concessions_amount, b.fee_amount, b.discount_amount, b.total_amount, .* WHERE b.booking_id = \\$1 AND b.user_id = \\$2").
+			WithArgs(11, 7).
+			WillReturnRows(sqlmock.NewRows(append(historyColumns, "subtotal_amount", "concessions_amount", "fee_amount", "discount_amount", "total_amount", "payment_method", "cancelled_at")).
+				AddRow(11, "Cancelled", "CG-7KQ2MX", 3, "Dune", "dune.jpg", "Hall 1", "IMAX", "2026-11-02", "18:30", 1, "{A5}", 2300, 800, "USD", "Captured", 1500, 700, 100, 0, 2300, "Fake", nil))
+		mock.ExpectQuery("SELECT bi.item_type, COALESCE\\(bi.show_seat_id, 0\\), bi.description, bi.amount, .* FROM booking_item bi LEFT JOIN seat_price_history sph").WithArgs(11).
+			WillReturnRows(sqlmock.NewRows([]string{"item_type", "show_seat_id", "description", "amount", "seat_price_history_id", "reason", "ticket_category_id", "name"}).
+				AddRow("Seat", 1, "Seat A5 (VIP, Child)", 900, 42, "Dynamic pricing: 80% booked (+25%)", 2, "Child").AddRow("Fee", 0, "Booking fee (1 x 100)", 100, 0, "", 0, ""))
+		mock.ExpectQuery("SELECT bc.booking_concession_id, .* FROM booking_concession bc JOIN concession_item ci").WithArgs(11).
+			WillReturnRows(sqlmock.NewRows([]string{"booking_concession_id", "concession_item_id", "name", "quantity", "unit_price", "pickup_status", "collected_at"}).
+				AddRow(4, 2, "Large popcorn", 1, 700, "Cancelled", nil))
+
+		booking, err := psql.RetrieveUserBooking(11, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Cancelled", booking.BookingStatus)
+		assert.Equal(t, models.NewMoney(800, "USD"), booking.AmountRefunded)
+		assert.Len(t, booking.Items, 2)
+		assert.Equal(t, "Dynamic pricing: 80% booked (+25%)", booking.Items[0].PriceReason)
+		assert.Equal(t, "Child", booking.Items[0].TicketCategory)
+		assert.Equal(t, models.NewMoney(900, "USD"), booking.Items[0].Amount)
+		assert.Equal(t, models.NewMoney(700, "USD"), booking.ConcessionsAmount)
+		assert.Len(t, booking.Concessions, 1)
+		assert.Equal(t, "Cancelled", booking.Concessions[0].PickupStatus)
+		assert.Nil(t, booking.CancelledAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("by_reference_not_found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT b.booking_id, b.status, .* WHERE b.booking_reference = \\$1").WithArgs("CG-ZZZZZZ").WillReturnRows(sqlmock.NewRows(historyColumns))
+
+		_, err := psql.RetrieveBookingByReference("CG-ZZZZZZ")
+
+		assert.ErrorIs(t, err, models.ErrBookingNotFound)
+	})
+
+	t.Run("detail_not_found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT b.booking_id, b.status").WithArgs(12, 7).WillReturnRows(sqlmock.NewRows(historyColumns))
+
+		_, err := psql.RetrieveUserBooking(12, 7)
+
+		assert.ErrorIs(t, err, models.ErrBookingNotFound)
+	})
+}
+
+func TestConcessionTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+	itemColumns := []string{"concession_item_id", "name", "description", "category", "price", "stock", "active"}
+
+	t.Run("lock_and_sell", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT concession_item_id, name, .* FROM concession_item WHERE concession_item_id = ANY\\(\\$1\\) .* FOR UPDATE").
+			WillReturnRows(sqlmock.NewRows(itemColumns).
+				AddRow(1, "Large popcorn", "", "Food", 700, 5, true).AddRow(2, "Cola", "", "Drink", 400, nil, true).AddRow(3, "Movie combo", "", "Combo", 1000, nil, true))
+		mock.ExpectQuery("SELECT cci.combo_id, cci.component_id, ci.name, cci.quantity FROM concession_combo_item cci").
+			WillReturnRows(sqlmock.NewRows([]string{"combo_id", "component_id", "name", "quantity"}).
+				AddRow(3, 2, "Cola", 1).AddRow(3, 1, "Large popcorn", 1))
+		mock.ExpectExec("UPDATE concession_item SET stock = stock - \\$2").WithArgs(1, 6).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		tx, err := psql.BeginBookingTx()
+		assert.NoError(t, err)
+
+		concessionItems, err := tx.LockConcessionItems([]int{3})
+		assert.NoError(t, err)
+		assert.Len(t, concessionItems, 3)
+		assert.Equal(t, 5, *concessionItems[0].Stock)
+		assert.Nil(t, concessionItems[1].Stock)
+		assert.Len(t, concessionItems[2].ComboItems, 2)
+
+		err = tx.DecrementConcessionStock(map[int]int{1: 6})
+		assert.ErrorIs(t, err, models.ErrConcessionOutOfStock)
+
+		assert.NoError(t, tx.Rollback())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("collect", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE booking_concession SET pickup_status = 'Collected'").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("WITH cancelled AS .*\\(UPDATE booking_concession SET pickup_status = 'Cancelled'").WithArgs(11).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		tx, err := psql.BeginBookingTx()
+		assert.NoError(t, err)
+
+		collected, err := tx.CollectBookingConcessions(11, 5, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, collected)
+
+		assert.NoError(t, tx.RestockBookingConcessions(11))
+		assert.NoError(t, tx.Commit())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReceipts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	t.Run("issue", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("WITH next AS \\(UPDATE receipt_counter SET last_number = last_number \\+ 1 .* INSERT INTO receipt").WithArgs(7, 1200).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tx, err := psql.BeginBookingTx()
+		assert.NoError(t, err)
+
+		assert.NoError(t, tx.InsertReceipt(7, 1200))
+		assert.NoError(t, tx.Commit())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("retrieve", func(t *testing.T) {
+		issuedAt := time.Date(2026, 10, 17, 14, 3, 0, 0, time.UTC)
+		mock.ExpectQuery("SELECT receipt_number, booking_id, vat_rate, issued_at FROM receipt WHERE booking_id = \\$1").WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"receipt_number", "booking_id", "vat_rate", "issued_at"}).AddRow(42, 7, 1200, issuedAt))
+
+		receipt, err := psql.RetrieveReceipt(7)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), receipt.ReceiptNumber)
+		assert.Equal(t, 1200, receipt.VATRate)
+
+		mock.ExpectQuery("SELECT receipt_number, booking_id, vat_rate, issued_at FROM receipt").WithArgs(8).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = psql.RetrieveReceipt(8)
+		assert.ErrorIs(t, err, models.ErrReceiptNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWaitlist(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	psql := &models.Postgres{DB: db}
+
+	t.Run("join", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO waitlist_entry \\(show_id, user_id, number_of_seats\\)").WithArgs(3, 7, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"waitlist_entry_id"}).AddRow(5))
+		mock.ExpectQuery("INSERT INTO waitlist_entry").WithArgs(3, 7, 2).WillReturnError(&pq.Error{Code: "23505"})
+
+		waitlistEntryID, err := psql.InsertWaitlistEntry(3, 7, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, waitlistEntryID)
+
+		_, err = psql.InsertWaitlistEntry(3, 7, 2)
+		assert.ErrorIs(t, err, models.ErrAlreadyWaitlisted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("offered_entry", func(t *testing.T) {
+		createdAt := time.Date(2026, 10, 17, 14, 0, 0, 0, time.UTC)
+		expiresAt := createdAt.Add(time.Hour)
+		mock.ExpectQuery("SELECT w.waitlist_entry_id, .* FROM waitlist_entry w LEFT JOIN seat_hold sh .* WHERE w.show_id = \\$1 AND w.user_id = \\$2").WithArgs(3, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"waitlist_entry_id", "show_id", "user_id", "number_of_seats", "status", "position", "hold_id", "show_seats", "expires_at", "created_at", "offered_at"}).
+				AddRow(5, 3, 7, 2, "Offered", 0, 9, "{11,12}", expiresAt, createdAt, createdAt))
+
+		waitlistEntry, err := psql.RetrieveWaitlistEntry(3, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, "Offered", waitlistEntry.Status)
+		assert.Equal(t, []int{11, 12}, waitlistEntry.ShowSeatsID)
+		assert.Equal(t, expiresAt, *waitlistEntry.HoldExpiresAt)
+
+		mock.ExpectQuery("SELECT w.waitlist_entry_id").WithArgs(3, 8).WillReturnError(sql.ErrNoRows)
+
+		_, err = psql.RetrieveWaitlistEntry(3, 8)
+		assert.ErrorIs(t, err, models.ErrWaitlistEntryNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("serve_queue", func(t *testing.T) {
+		createdAt := time.Date(2026, 10, 17, 14, 0, 0, 0, time.UTC)
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE waitlist_entry w SET status = 'Expired' WHERE w.show_id = \\$1 AND w.status = 'Offered'").WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT waitlist_entry_id, .* FROM waitlist_entry WHERE show_id = \\$1 AND status = 'Waiting' ORDER BY created_at, waitlist_entry_id FOR UPDATE").WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"waitlist_entry_id", "show_id", "user_id", "number_of_seats", "status", "created_at"}).
+				AddRow(5, 3, 7, 2, "Waiting", createdAt).AddRow(6, 3, 8, 1, "Waiting", createdAt.Add(time.Minute)))
+		mock.ExpectExec("UPDATE waitlist_entry SET status = 'Offered', hold_id = \\$2").WithArgs(5, 9).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tx, err := psql.BeginBookingTx()
+		assert.NoError(t, err)
+
+		assert.NoError(t, tx.ExpireWaitlistOffers(3))
+
+		waitingEntries, err := tx.LockWaitingEntries(3)
+		assert.NoError(t, err)
+		assert.Len(t, waitingEntries, 2)
+		assert.Equal(t, 2, waitingEntries[1].Position)
+
+		assert.NoError(t, tx.OfferWaitlistEntry(5, 9))
+		assert.NoError(t, tx.Commit())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("leave_with_offer", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE waitlist_entry SET status = 'Cancelled' .* RETURNING hold_id").WithArgs(3, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"hold_id"}).AddRow(9))
+		mock.ExpectExec("UPDATE seat_hold SET status = 'Released' WHERE hold_id = \\$1").WithArgs(9).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE show_seat SET status = 'Available', hold_id = NULL WHERE hold_id = \\$1").WithArgs(9).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		released, err := psql.CancelWaitlistEntry(3, 7)
+		assert.NoError(t, err)
+		assert.True(t, released)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
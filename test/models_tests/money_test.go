@@ -0,0 +1,76 @@
+package modelstests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// uzs returns an amount in minor units of the default currency.
+func uzs(amount int64) models.Money {
+	return models.NewMoney(amount, models.DefaultCurrency)
+}
+
+func TestParseMoney(t *testing.T) {
+	for input, expected := range map[string]int64{"19.99": 1999, "19.9": 1990, "19": 1900, " 0.01 ": 1, "-5.50": -550} {
+		amount, err := models.ParseMoney(input, "UZS")
+
+		assert.NoError(t, err, input)
+		assert.Equal(t, models.NewMoney(expected, "UZS"), amount, input)
+	}
+
+	amount, err := models.ParseMoney("1500", "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1500), amount.Amount)
+
+	for _, input := range []string{"", "-", "19.999", "1e3", "19.", ".5", "+5", "12,50", "1234567890123456"} {
+		_, err := models.ParseMoney(input, "UZS")
+
+		assert.ErrorIs(t, err, models.ErrInvalidMoney, input)
+	}
+
+	_, err = models.ParseMoney("15.5", "JPY")
+	assert.ErrorIs(t, err, models.ErrInvalidMoney)
+}
+
+func TestMoneyFormatting(t *testing.T) {
+	assert.Equal(t, "19.99", uzs(1999).Decimal())
+	assert.Equal(t, "-0.05", uzs(-5).Decimal())
+	assert.Equal(t, "19.99 UZS", uzs(1999).String())
+	assert.Equal(t, "1500", models.NewMoney(1500, "JPY").Decimal())
+	assert.Equal(t, "1.250", models.NewMoney(1250, "KWD").Decimal())
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	assert.Equal(t, uzs(2500), uzs(1999).Add(uzs(501)))
+	assert.Equal(t, uzs(1999), models.Money{}.Add(uzs(1999)))
+	assert.Equal(t, uzs(-500), uzs(1000).Sub(uzs(1500)))
+	assert.Equal(t, uzs(5997), uzs(1999).Mul(3))
+	assert.Equal(t, uzs(1499), uzs(1999).Percent(75))
+	assert.Equal(t, uzs(1000), uzs(1999).Min(uzs(1000)))
+
+	assert.Panics(t, func() { uzs(100).Add(models.NewMoney(100, "USD")) })
+}
+
+func TestMoneyJSON(t *testing.T) {
+	encoded, err := json.Marshal(uzs(1999))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Amount": "19.99", "Currency": "UZS"}`, string(encoded))
+
+	var decoded models.Money
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, uzs(1999), decoded)
+
+	// Bare numbers are read from their text, so 19.99 is never rounded down to 1998.
+	for _, input := range []string{`19.99`, `"19.99"`, `{"Amount": "19.99"}`} {
+		var amount models.Money
+
+		assert.NoError(t, json.Unmarshal([]byte(input), &amount), input)
+		assert.Equal(t, uzs(1999), amount, input)
+	}
+
+	var amount models.Money
+	assert.ErrorIs(t, json.Unmarshal([]byte(`19.999`), &amount), models.ErrInvalidMoney)
+}
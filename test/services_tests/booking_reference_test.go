@@ -0,0 +1,47 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/services"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBookingReference(t *testing.T) {
+	format := regexp.MustCompile(`^CG-[2-9A-HJ-NP-Z]{6}$`)
+	seen := make(map[string]bool)
+
+	for i := 0; i < 100; i++ {
+		reference, err := services.NewBookingReference()
+
+		assert.NoError(t, err)
+		assert.Regexp(t, format, reference)
+		seen[reference] = true
+	}
+
+	assert.Greater(t, len(seen), 95)
+}
+
+func TestNormalizeBookingReference(t *testing.T) {
+	for _, input := range []string{"CG-7KQ2MX", "cg-7kq2mx", " 7KQ2MX ", "CG 7KQ2MX", "cg7kq2mx"} {
+		reference, err := services.NormalizeBookingReference(input)
+
+		assert.NoError(t, err, input)
+		assert.Equal(t, "CG-7KQ2MX", reference, input)
+	}
+
+	// A code that starts with the letters of the prefix keeps them when typed without the prefix.
+	for input, expected := range map[string]string{"CGXYZ2": "CG-CGXYZ2", "cgxyz2": "CG-CGXYZ2", "CG-CGXYZ2": "CG-CGXYZ2", "CGCGXYZ2": "CG-CGXYZ2"} {
+		reference, err := services.NormalizeBookingReference(input)
+
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, reference, input)
+	}
+
+	for _, input := range []string{"", "CG-7KQ2M", "CG-7KQ2MXX", "CG-7KQ0MX", "CG-7KQIMX", "42"} {
+		_, err := services.NormalizeBookingReference(input)
+
+		assert.ErrorIs(t, err, services.ErrInvalidBookingReference, input)
+	}
+}
@@ -0,0 +1,740 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"cinemaGo/backend/internal/services"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBookingDB is an in-memory stand-in for the booking database. It only implements what the tests
+// exercise; calling anything else panics on the nil embedded interface. Every call is logged, so tests
+// can check what happened in which order. The log may be written by the background sweepers, so it is
+// guarded by a mutex.
+type fakeBookingDB struct {
+	models.DBContractBooking
+
+	mu             sync.Mutex
+	calls          []string
+	showSeats      []models.ShowSeat
+	bookingPayment models.BookingPayment
+	waitingEntries []models.WaitlistEntry
+	expiredShowIDs []int
+	receiptErr     error
+	commitErr      error
+	nextHoldID     int
+}
+
+func newFakeBookingDB() *fakeBookingDB {
+	return &fakeBookingDB{
+		showSeats: []models.ShowSeat{
+			{ShowSeatID: 1, SeatRow: "A", SeatNumber: 1, SeatType: "Standard", SeatStatus: "Available", SeatPrice: uzs(5000)},
+			{ShowSeatID: 2, SeatRow: "A", SeatNumber: 2, SeatType: "Standard", SeatStatus: "Available", SeatPrice: uzs(5000)},
+		},
+		bookingPayment: models.BookingPayment{BookingID: 7, ShowID: 3, UserID: 5, BookingStatus: "Pending", PaymentID: 9,
+			Amount: uzs(10000), PaymentStatus: "Authorized", RemoteTransactionID: "txn_7"},
+		nextHoldID: 100,
+	}
+}
+
+func (db *fakeBookingDB) log(format string, args ...any) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.calls = append(db.calls, fmt.Sprintf(format, args...))
+}
+
+// index returns where a call was logged first, or -1.
+func (db *fakeBookingDB) index(call string) int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for i, logged := range db.calls {
+		if logged == call {
+			return i
+		}
+	}
+	return -1
+}
+
+func (db *fakeBookingDB) RetrieveShowMovieHall(showID int) (models.ShowMovieHall, error) {
+	return models.ShowMovieHall{ShowID: showID, Currency: models.DefaultCurrency}, nil
+}
+
+func (db *fakeBookingDB) RetrieveAllTicketCategories() ([]models.TicketCategory, error) {
+	return []models.TicketCategory{{TicketCategoryID: 1, Name: services.DefaultTicketCategory, PricePercent: 100}}, nil
+}
+
+func (db *fakeBookingDB) RetrieveShowSeats(showID int) ([]models.ShowSeat, error) {
+	return append([]models.ShowSeat(nil), db.showSeats...), nil
+}
+
+func (db *fakeBookingDB) ReleaseSeatHold(holdID, userID int) (int, error) {
+	db.log("release hold %d", holdID)
+	return db.bookingPayment.ShowID, nil
+}
+
+func (db *fakeBookingDB) ExpireSeatHolds() ([]int, error) {
+	showIDs := db.expiredShowIDs
+	db.expiredShowIDs = nil
+	return showIDs, nil
+}
+
+// RetrievePendingRefunds returns the refund of the booking while its payment is waiting for it.
+func (db *fakeBookingDB) RetrievePendingRefunds() ([]models.PendingRefund, error) {
+	if db.bookingPayment.PaymentStatus != "RefundPending" {
+		return nil, nil
+	}
+	return []models.PendingRefund{{BookingID: db.bookingPayment.BookingID, RemoteTransactionID: db.bookingPayment.RemoteTransactionID,
+		Amount: db.bookingPayment.Amount}}, nil
+}
+
+func (db *fakeBookingDB) RetrieveShowHallLayout(showID int) (models.HallLayout, error) {
+	return models.HallLayout{Columns: len(db.showSeats), Rows: 1, ScreenPosition: "Top"}, nil
+}
+
+func (db *fakeBookingDB) RetrieveBookingByReference(bookingReference string) (models.BookingDetail, error) {
+	if bookingReference != "CG-7KQ2MX" {
+		return models.BookingDetail{}, models.ErrBookingNotFound
+	}
+	return models.BookingDetail{
+		BookingHistoryEntry: models.BookingHistoryEntry{BookingID: db.bookingPayment.BookingID, BookingReference: bookingReference,
+			BookingStatus: db.bookingPayment.BookingStatus, ShowID: db.bookingPayment.ShowID},
+		Items: []models.PriceQuoteItem{{ItemType: "Seat", ShowSeatID: 1}},
+	}, nil
+}
+
+func (db *fakeBookingDB) RetrieveUserBooking(bookingID, userID int) (models.BookingDetail, error) {
+	if bookingID != db.bookingPayment.BookingID || userID != db.bookingPayment.UserID {
+		return models.BookingDetail{}, models.ErrBookingNotFound
+	}
+	return models.BookingDetail{}, nil
+}
+
+func (db *fakeBookingDB) BeginBookingTx() (models.BookingTx, error) {
+	db.log("begin")
+	return &fakeBookingTx{db: db, rollbackTo: db.bookingPayment}, nil
+}
+
+// fakeBookingTx applies writes to its fakeBookingDB straight away; a rollback only restores the booking
+// payment, which is all the tests check besides the order of calls.
+type fakeBookingTx struct {
+	models.BookingTx
+
+	db         *fakeBookingDB
+	rollbackTo models.BookingPayment
+	committed  bool
+}
+
+func (tx *fakeBookingTx) LockShowSeats(showID int, showSeatIDs []int) ([]models.LockedShowSeat, error) {
+	tx.db.log("lock seats %v", showSeatIDs)
+
+	var lockedSeats []models.LockedShowSeat
+	for _, showSeat := range tx.db.showSeats {
+		for _, showSeatID := range showSeatIDs {
+			if showSeat.ShowSeatID == showSeatID {
+				lockedSeats = append(lockedSeats, models.LockedShowSeat{ShowSeat: showSeat})
+			}
+		}
+	}
+	return lockedSeats, nil
+}
+
+func (tx *fakeBookingTx) InsertNewBooking(numberOfSeats int, bookingStatus, bookingReference string, quote models.PriceQuote, userID int) (int, error) {
+	return tx.db.bookingPayment.BookingID, nil
+}
+
+func (tx *fakeBookingTx) InsertBookingItems(bookingID int, items []models.PriceQuoteItem) error {
+	return nil
+}
+
+func (tx *fakeBookingTx) LinkShowSeatsToBooking(seatStatus string, bookingID, showID int, showSeatIDs []int) error {
+	return nil
+}
+
+func (tx *fakeBookingTx) ConvertSeatHolds(userID, showID int) error {
+	return nil
+}
+
+func (tx *fakeBookingTx) FulfillWaitlistEntries(userID, showID int) error {
+	return nil
+}
+
+func (tx *fakeBookingTx) InsertPaymentDetails(amount models.Money, remoteTransactionID, paymentMethod, paymentStatus string, bookingID int) error {
+	tx.db.log("insert payment %s", paymentStatus)
+	tx.db.bookingPayment.PaymentStatus = paymentStatus
+	return nil
+}
+
+func (tx *fakeBookingTx) LockBookingPayment(remoteTransactionID string) (models.BookingPayment, error) {
+	if remoteTransactionID != tx.db.bookingPayment.RemoteTransactionID {
+		return models.BookingPayment{}, models.ErrPaymentNotFound
+	}
+	return tx.db.bookingPayment, nil
+}
+
+func (tx *fakeBookingTx) LockUserBookingPayment(bookingID, userID int) (models.BookingPayment, error) {
+	if bookingID != tx.db.bookingPayment.BookingID || userID != tx.db.bookingPayment.UserID {
+		return models.BookingPayment{}, models.ErrBookingNotFound
+	}
+	return tx.db.bookingPayment, nil
+}
+
+func (tx *fakeBookingTx) LockBookingPaymentByID(bookingID int) (models.BookingPayment, error) {
+	if bookingID != tx.db.bookingPayment.BookingID {
+		return models.BookingPayment{}, models.ErrBookingNotFound
+	}
+	return tx.db.bookingPayment, nil
+}
+
+func (tx *fakeBookingTx) UpdateBookingStatus(bookingID int, bookingStatus string) error {
+	tx.db.log("booking %s", bookingStatus)
+	tx.db.bookingPayment.BookingStatus = bookingStatus
+	return nil
+}
+
+func (tx *fakeBookingTx) UpdatePaymentStatus(paymentID int, paymentStatus string) error {
+	tx.db.log("payment %s", paymentStatus)
+	tx.db.bookingPayment.PaymentStatus = paymentStatus
+	return nil
+}
+
+func (tx *fakeBookingTx) UpdatePaymentAuthorization(paymentID int, remoteTransactionID string) error {
+	tx.db.log("payment Authorized")
+	tx.db.bookingPayment.PaymentStatus = "Authorized"
+	tx.db.bookingPayment.RemoteTransactionID = remoteTransactionID
+	return nil
+}
+
+func (tx *fakeBookingTx) ReleaseBookingSeats(bookingID int) error {
+	tx.db.log("release seats")
+	return nil
+}
+
+func (tx *fakeBookingTx) InsertRefund(paymentID int, amount models.Money, remoteRefundID, reason string) error {
+	tx.db.log("refund %d: %s", amount.Amount, reason)
+	return nil
+}
+
+func (tx *fakeBookingTx) CompleteRefund(paymentID int, remoteRefundID string) error {
+	tx.db.log("refund settled as %s", remoteRefundID)
+	return nil
+}
+
+func (tx *fakeBookingTx) CancelBooking(bookingID int) error {
+	tx.db.log("booking Cancelled")
+	tx.db.bookingPayment.BookingStatus = "Cancelled"
+	return nil
+}
+
+func (tx *fakeBookingTx) RestockBookingConcessions(bookingID int) error {
+	return nil
+}
+
+func (tx *fakeBookingTx) InsertReceipt(bookingID, vatRate int) error {
+	tx.db.log("receipt")
+	return tx.db.receiptErr
+}
+
+func (tx *fakeBookingTx) ExpireWaitlistOffers(showID int) error {
+	return nil
+}
+
+func (tx *fakeBookingTx) LockWaitingEntries(showID int) ([]models.WaitlistEntry, error) {
+	var waitingEntries []models.WaitlistEntry
+	for _, waitingEntry := range tx.db.waitingEntries {
+		if waitingEntry.ShowID == showID && waitingEntry.Status == "Waiting" {
+			waitingEntries = append(waitingEntries, waitingEntry)
+		}
+	}
+	return waitingEntries, nil
+}
+
+func (tx *fakeBookingTx) InsertSeatHold(userID, showID, holdMinutes int) (int, time.Time, error) {
+	tx.db.nextHoldID++
+	return tx.db.nextHoldID, time.Now().Add(time.Duration(holdMinutes) * time.Minute), nil
+}
+
+func (tx *fakeBookingTx) HoldShowSeats(holdID, showID int, showSeatIDs []int) error {
+	for i := range tx.db.showSeats {
+		for _, showSeatID := range showSeatIDs {
+			if tx.db.showSeats[i].ShowSeatID == showSeatID {
+				tx.db.showSeats[i].SeatStatus = "Selected"
+			}
+		}
+	}
+	return nil
+}
+
+func (tx *fakeBookingTx) OfferWaitlistEntry(waitlistEntryID, holdID int) error {
+	for i := range tx.db.waitingEntries {
+		if tx.db.waitingEntries[i].WaitlistEntryID == waitlistEntryID {
+			tx.db.waitingEntries[i].Status = "Offered"
+		}
+	}
+	return nil
+}
+
+func (tx *fakeBookingTx) Commit() error {
+	tx.db.log("commit")
+	if tx.db.commitErr != nil {
+		return tx.db.commitErr
+	}
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeBookingTx) Rollback() error {
+	if !tx.committed {
+		tx.db.log("rollback")
+		tx.db.bookingPayment = tx.rollbackTo
+	}
+	return nil
+}
+
+// recordingPaymentProvider logs every call to the provider in the log of the fake database.
+type recordingPaymentProvider struct {
+	*services.FakePaymentProvider
+
+	db           *fakeBookingDB
+	authorizeErr error
+	captureErr   error
+	refundErr    error
+}
+
+func (rp *recordingPaymentProvider) Authorize(bookingID int, amount models.Money) (string, error) {
+	rp.db.log("authorize")
+	if rp.authorizeErr != nil {
+		return "", rp.authorizeErr
+	}
+	return fmt.Sprintf("txn_%d", bookingID), nil
+}
+
+func (rp *recordingPaymentProvider) Capture(remoteTransactionID string, amount models.Money) error {
+	rp.db.log("capture")
+	return rp.captureErr
+}
+
+func (rp *recordingPaymentProvider) Refund(remoteTransactionID string, amount models.Money, refundKey string) (string, error) {
+	rp.db.log("provider refund %d as %s", amount.Amount, refundKey)
+	if rp.refundErr != nil {
+		return "", rp.refundErr
+	}
+	return "refund_" + remoteTransactionID, nil
+}
+
+func (rp *recordingPaymentProvider) Void(remoteTransactionID string) error {
+	rp.db.log("void")
+	return nil
+}
+
+// noSeatEvents drops every seat change.
+type noSeatEvents struct{}
+
+func (noSeatEvents) PublishSeatChange(showID int, reason string) {}
+
+func (noSeatEvents) SubscribeSeatChanges(showID int) (<-chan services.SeatChange, func()) {
+	changes := make(chan services.SeatChange)
+	return changes, func() { close(changes) }
+}
+
+// recordingWaitlistNotifier keeps every offer it is asked to send, in order. Offers may be sent from
+// the seat hold expirer, so it is safe for concurrent use.
+type recordingWaitlistNotifier struct {
+	mu     sync.Mutex
+	offers []models.WaitlistOffer
+}
+
+func (rn *recordingWaitlistNotifier) NotifyWaitlistOffer(offer models.WaitlistOffer) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	rn.offers = append(rn.offers, offer)
+}
+
+func (rn *recordingWaitlistNotifier) SubscribeWaitlistOffers(userID int) (<-chan models.WaitlistOffer, func()) {
+	offers := make(chan models.WaitlistOffer)
+	return offers, func() { close(offers) }
+}
+
+// offeredUsers returns the customers offered seats so far and how many seats each got, in order.
+func (rn *recordingWaitlistNotifier) offeredUsers() [][2]int {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+
+	var offeredUsers [][2]int
+	for _, offer := range rn.offers {
+		offeredUsers = append(offeredUsers, [2]int{offer.UserID, len(offer.SeatHold.ShowSeatsID)})
+	}
+	return offeredUsers
+}
+
+func newTestBookingService(db *fakeBookingDB, payments services.PaymentProvider, notifier services.WaitlistNotifier) *services.BookingService {
+	cancellationPolicy, _ := services.ParseCancellationPolicy("24:100,0:50")
+	return services.NewBookingService(db, payments, services.NewTicketSigner("ticket-secret"), noSeatEvents{}, notifier,
+		services.BookingSettings{HoldMinutes: 10, WaitlistHoldMinutes: 15, CancellationPolicy: cancellationPolicy})
+}
+
+func signedWebhook(status string) ([]byte, string) {
+	provider := services.NewFakePaymentProvider("webhook-secret")
+	payload := []byte(fmt.Sprintf(`{"transaction_id":"txn_7","status":%q}`, status))
+	return payload, provider.SignWebhook(payload)
+}
+
+func TestBookingPaymentFlow(t *testing.T) {
+	newProvider := func(db *fakeBookingDB) *recordingPaymentProvider {
+		return &recordingPaymentProvider{FakePaymentProvider: services.NewFakePaymentProvider("webhook-secret"), db: db}
+	}
+
+	t.Run("authorizes_after_commit", func(t *testing.T) {
+		db := newFakeBookingDB()
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		createdBooking, err := bookingService.CreateNewBooking(3, 5, []int{1, 2}, nil, nil, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Pending", createdBooking.BookingStatus)
+		assert.Equal(t, []string{"begin", "lock seats [1 2]", "insert payment Pending", "commit", "authorize", "begin", "payment Authorized", "commit"}, db.calls)
+		assert.Equal(t, "txn_7", db.bookingPayment.RemoteTransactionID)
+	})
+
+	t.Run("declined_authorization_fails_booking", func(t *testing.T) {
+		db := newFakeBookingDB()
+		provider := newProvider(db)
+		provider.authorizeErr = services.ErrPaymentDeclined
+		bookingService := newTestBookingService(db, provider, &recordingWaitlistNotifier{})
+
+		_, err := bookingService.CreateNewBooking(3, 5, []int{1, 2}, nil, nil, "")
+
+		assert.ErrorIs(t, err, services.ErrPaymentDeclined)
+		assert.Less(t, db.index("commit"), db.index("authorize"))
+		assert.Equal(t, "Failed", db.bookingPayment.BookingStatus)
+		assert.Equal(t, "Failed", db.bookingPayment.PaymentStatus)
+		assert.NotEqual(t, -1, db.index("release seats"))
+	})
+
+	t.Run("webhook_confirms_before_capture", func(t *testing.T) {
+		db := newFakeBookingDB()
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		err := bookingService.HandlePaymentWebhook(signedWebhook("succeeded"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"begin", "booking Confirmed", "commit", "capture", "begin", "payment Captured", "receipt", "commit"}, db.calls)
+	})
+
+	t.Run("failed_capture_voids_and_fails_booking", func(t *testing.T) {
+		db := newFakeBookingDB()
+		provider := newProvider(db)
+		provider.captureErr = errors.New("provider unavailable")
+		bookingService := newTestBookingService(db, provider, &recordingWaitlistNotifier{})
+
+		err := bookingService.HandlePaymentWebhook(signedWebhook("succeeded"))
+
+		assert.NoError(t, err)
+		assert.Less(t, db.index("capture"), db.index("void"))
+		assert.Equal(t, "Failed", db.bookingPayment.BookingStatus)
+		assert.Equal(t, "Failed", db.bookingPayment.PaymentStatus)
+		assert.Equal(t, -1, db.index("receipt"))
+	})
+
+	t.Run("redelivered_webhook_is_ignored", func(t *testing.T) {
+		db := newFakeBookingDB()
+		db.bookingPayment.BookingStatus = "Confirmed"
+		db.bookingPayment.PaymentStatus = "Captured"
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		err := bookingService.HandlePaymentWebhook(signedWebhook("succeeded"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, -1, db.index("capture"))
+	})
+
+	t.Run("redelivered_webhook_finishes_capture", func(t *testing.T) {
+		// The money was collected, but recording the capture failed, so the provider redelivers.
+		db := newFakeBookingDB()
+		db.receiptErr = errors.New("connection reset")
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		err := bookingService.HandlePaymentWebhook(signedWebhook("succeeded"))
+
+		assert.Error(t, err)
+		assert.Equal(t, "Confirmed", db.bookingPayment.BookingStatus)
+		assert.Equal(t, "Authorized", db.bookingPayment.PaymentStatus)
+
+		db.receiptErr = nil
+		db.calls = nil
+
+		err = bookingService.HandlePaymentWebhook(signedWebhook("succeeded"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"begin", "commit", "capture", "begin", "payment Captured", "receipt", "commit"}, db.calls)
+		assert.Equal(t, "Confirmed", db.bookingPayment.BookingStatus)
+		assert.Equal(t, "Captured", db.bookingPayment.PaymentStatus)
+	})
+}
+
+func TestRefundBooking(t *testing.T) {
+	// A paid booking for a show that started a minute ago, past the customer cancellation window.
+	newStartedBookingDB := func() *fakeBookingDB {
+		db := newFakeBookingDB()
+		db.bookingPayment.BookingStatus = "Confirmed"
+		db.bookingPayment.PaymentStatus = "Captured"
+		db.bookingPayment.SecondsUntilShow = -60
+		return db
+	}
+	newProvider := func(db *fakeBookingDB) *recordingPaymentProvider {
+		return &recordingPaymentProvider{FakePaymentProvider: services.NewFakePaymentProvider("webhook-secret"), db: db}
+	}
+
+	t.Run("customer_window_closed", func(t *testing.T) {
+		db := newStartedBookingDB()
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		_, err := bookingService.CancelBooking(7, 5)
+
+		assert.ErrorIs(t, err, services.ErrCancellationWindowClosed)
+		assert.Equal(t, "Confirmed", db.bookingPayment.BookingStatus)
+	})
+
+	t.Run("admin_refund_ignores_window", func(t *testing.T) {
+		db := newStartedBookingDB()
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		cancelledBooking, err := bookingService.RefundBooking(7, 100, "Show called off")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 100, cancelledBooking.RefundPercent)
+		assert.Equal(t, uzs(10000), cancelledBooking.RefundAmount)
+		assert.Equal(t, []string{"begin", "refund 10000: Show called off", "payment RefundPending", "release seats", "booking Cancelled", "commit"}, db.calls[:6])
+		assert.Equal(t, []string{"provider refund 10000 as booking-7-refund", "begin", "refund settled as refund_txn_7", "payment Refunded", "commit"}, db.calls[len(db.calls)-5:])
+	})
+
+	t.Run("admin_partial_refund", func(t *testing.T) {
+		db := newStartedBookingDB()
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		cancelledBooking, err := bookingService.RefundBooking(7, 50, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uzs(5000), cancelledBooking.RefundAmount)
+		assert.NotEqual(t, -1, db.index("refund 5000: Cancelled by admin"))
+		assert.Equal(t, "Captured", db.bookingPayment.PaymentStatus)
+		assert.Equal(t, "Cancelled", db.bookingPayment.BookingStatus)
+	})
+
+	t.Run("failed_commit_pays_nothing_out", func(t *testing.T) {
+		db := newStartedBookingDB()
+		db.commitErr = errors.New("connection lost")
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		_, err := bookingService.RefundBooking(7, 100, "")
+
+		assert.Error(t, err)
+		assert.Equal(t, -1, db.index("provider refund 10000 as booking-7-refund"))
+		assert.Equal(t, "Captured", db.bookingPayment.PaymentStatus)
+		assert.Equal(t, "Confirmed", db.bookingPayment.BookingStatus)
+	})
+
+	t.Run("provider_failure_settled_later", func(t *testing.T) {
+		db := newStartedBookingDB()
+		provider := newProvider(db)
+		provider.refundErr = errors.New("provider unavailable")
+		bookingService := newTestBookingService(db, provider, &recordingWaitlistNotifier{})
+
+		cancelledBooking, err := bookingService.RefundBooking(7, 100, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uzs(10000), cancelledBooking.RefundAmount)
+		assert.Equal(t, "RefundPending", db.bookingPayment.PaymentStatus)
+		assert.Equal(t, "Cancelled", db.bookingPayment.BookingStatus)
+
+		provider.refundErr = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+		go func() {
+			bookingService.RunRefundSettler(ctx, 5*time.Millisecond)
+			close(stopped)
+		}()
+
+		assert.Eventually(t, func() bool { return db.index("refund settled as refund_txn_7") != -1 }, time.Second, 5*time.Millisecond)
+		cancel()
+		<-stopped
+
+		assert.Equal(t, "Refunded", db.bookingPayment.PaymentStatus)
+		assert.Equal(t, 1, strings.Count(strings.Join(db.calls, "\n"), "refund settled"))
+	})
+
+	t.Run("invalid_percent", func(t *testing.T) {
+		db := newStartedBookingDB()
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		_, err := bookingService.RefundBooking(7, 120, "")
+
+		assert.ErrorIs(t, err, services.ErrInvalidRefundPercent)
+		assert.Empty(t, db.calls)
+	})
+
+	t.Run("admitted_booking", func(t *testing.T) {
+		db := newStartedBookingDB()
+		db.bookingPayment.Admitted = true
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		_, err := bookingService.RefundBooking(7, 100, "")
+
+		assert.ErrorIs(t, err, services.ErrBookingNotCancellable)
+	})
+
+	t.Run("unknown_booking", func(t *testing.T) {
+		db := newStartedBookingDB()
+		bookingService := newTestBookingService(db, newProvider(db), &recordingWaitlistNotifier{})
+
+		_, err := bookingService.RefundBooking(8, 100, "")
+
+		assert.ErrorIs(t, err, services.ErrBookingNotFound)
+	})
+}
+
+func TestWaitlistOffersOnReleasedSeats(t *testing.T) {
+	// A sold-out show whose four seats have just been given back, with four customers waiting for it.
+	newReleasedSeatsDB := func() *fakeBookingDB {
+		db := newFakeBookingDB()
+		db.showSeats = append(db.showSeats,
+			models.ShowSeat{ShowSeatID: 3, SeatRow: "A", SeatNumber: 3, SeatType: "Standard", SeatStatus: "Available", SeatPrice: uzs(5000)},
+			models.ShowSeat{ShowSeatID: 4, SeatRow: "A", SeatNumber: 4, SeatType: "Standard", SeatStatus: "Available", SeatPrice: uzs(5000)},
+		)
+		db.waitingEntries = []models.WaitlistEntry{
+			{WaitlistEntryID: 1, ShowID: 3, UserID: 11, NumberOfSeats: 2, Status: "Waiting"},
+			{WaitlistEntryID: 2, ShowID: 3, UserID: 12, NumberOfSeats: 1, Status: "Waiting"},
+			{WaitlistEntryID: 3, ShowID: 3, UserID: 13, NumberOfSeats: 1, Status: "Waiting"},
+			{WaitlistEntryID: 4, ShowID: 3, UserID: 14, NumberOfSeats: 1, Status: "Waiting"},
+		}
+		return db
+	}
+	newProvider := func(db *fakeBookingDB) *recordingPaymentProvider {
+		return &recordingPaymentProvider{FakePaymentProvider: services.NewFakePaymentProvider("webhook-secret"), db: db}
+	}
+
+	// The queue is served in the order customers joined until the seats run out.
+	expected := [][2]int{{11, 2}, {12, 1}, {13, 1}}
+
+	t.Run("cancellation", func(t *testing.T) {
+		db := newReleasedSeatsDB()
+		db.bookingPayment.BookingStatus = "Confirmed"
+		db.bookingPayment.PaymentStatus = "Captured"
+		db.bookingPayment.SecondsUntilShow = 48 * 3600
+		notifier := &recordingWaitlistNotifier{}
+		bookingService := newTestBookingService(db, newProvider(db), notifier)
+
+		_, err := bookingService.CancelBooking(7, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, notifier.offeredUsers())
+	})
+
+	t.Run("failed_payment", func(t *testing.T) {
+		db := newReleasedSeatsDB()
+		notifier := &recordingWaitlistNotifier{}
+		bookingService := newTestBookingService(db, newProvider(db), notifier)
+
+		err := bookingService.HandlePaymentWebhook(signedWebhook("failed"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Failed", db.bookingPayment.BookingStatus)
+		assert.Equal(t, expected, notifier.offeredUsers())
+	})
+
+	t.Run("released_hold", func(t *testing.T) {
+		db := newReleasedSeatsDB()
+		notifier := &recordingWaitlistNotifier{}
+		bookingService := newTestBookingService(db, newProvider(db), notifier)
+
+		err := bookingService.ReleaseSeatHold(42, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, notifier.offeredUsers())
+	})
+
+	t.Run("expirer", func(t *testing.T) {
+		db := newReleasedSeatsDB()
+		db.expiredShowIDs = []int{3}
+		notifier := &recordingWaitlistNotifier{}
+		bookingService := newTestBookingService(db, newProvider(db), notifier)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+		go func() {
+			bookingService.RunSeatHoldExpirer(ctx, 5*time.Millisecond)
+			close(stopped)
+		}()
+
+		assert.Eventually(t, func() bool { return len(notifier.offeredUsers()) == len(expected) }, time.Second, 5*time.Millisecond)
+		cancel()
+		<-stopped
+
+		assert.Equal(t, expected, notifier.offeredUsers())
+	})
+}
+
+func TestFetchSeatMapSVGHighlight(t *testing.T) {
+	db := newFakeBookingDB()
+	db.bookingPayment.BookingStatus = "Confirmed"
+	for i := range db.showSeats {
+		db.showSeats[i].GridX, db.showSeats[i].GridY = i+1, 1
+	}
+	bookingService := newTestBookingService(db, services.NewFakePaymentProvider("webhook-secret"), &recordingWaitlistNotifier{})
+
+	plainMap, err := bookingService.FetchSeatMapSVG(3, "", 0, "")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(plainMap), ">Your seats<")
+
+	ticketSigner := services.NewTicketSigner("ticket-secret")
+	ownTicket, _ := ticketSigner.Sign(7, 3, 5)
+	otherTicket, _ := ticketSigner.Sign(8, 3, 6)
+	forgedTicket, _ := services.NewTicketSigner("another-secret").Sign(7, 3, 5)
+
+	t.Run("owner", func(t *testing.T) {
+		svg, err := bookingService.FetchSeatMapSVG(3, "cg-7kq2mx", 5, "")
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(svg), ">Your seats<")
+	})
+
+	t.Run("ticket_holder", func(t *testing.T) {
+		svg, err := bookingService.FetchSeatMapSVG(3, "CG-7KQ2MX", 0, ownTicket)
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(svg), ">Your seats<")
+	})
+
+	// Every reference the caller may not see gives the same map as no reference at all.
+	for name, request := range map[string]struct {
+		reference   string
+		userID      int
+		ticketToken string
+	}{
+		"anonymous":          {"CG-7KQ2MX", 0, ""},
+		"other_user":         {"CG-7KQ2MX", 6, ""},
+		"ticket_of_other":    {"CG-7KQ2MX", 6, otherTicket},
+		"forged_ticket":      {"CG-7KQ2MX", 0, forgedTicket},
+		"unknown_reference":  {"CG-ABCDEF", 5, ""},
+		"invalid_reference":  {"not-a-reference", 5, ""},
+		"booking_other_show": {"CG-7KQ2MX", 5, ownTicket},
+	} {
+		t.Run(name, func(t *testing.T) {
+			showID := 3
+			if name == "booking_other_show" {
+				showID = 4
+			}
+
+			svg, err := bookingService.FetchSeatMapSVG(showID, request.reference, request.userID, request.ticketToken)
+
+			assert.NoError(t, err)
+			assert.Equal(t, string(plainMap), string(svg))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancellationPolicy(t *testing.T) {
+	policy, err := services.ParseCancellationPolicy("0:50, 24:100")
+	assert.NoError(t, err)
+
+	t.Run("full_refund", func(t *testing.T) {
+		percent, allowed := policy.RefundPercent(48 * time.Hour)
+		assert.True(t, allowed)
+		assert.Equal(t, 100, percent)
+	})
+
+	t.Run("full_refund_boundary", func(t *testing.T) {
+		percent, allowed := policy.RefundPercent(24 * time.Hour)
+		assert.True(t, allowed)
+		assert.Equal(t, 100, percent)
+	})
+
+	t.Run("partial_refund", func(t *testing.T) {
+		percent, allowed := policy.RefundPercent(3 * time.Hour)
+		assert.True(t, allowed)
+		assert.Equal(t, 50, percent)
+	})
+
+	t.Run("show_started", func(t *testing.T) {
+		_, allowed := policy.RefundPercent(-time.Minute)
+		assert.False(t, allowed)
+	})
+
+	t.Run("no_matching_rule", func(t *testing.T) {
+		strict, err := services.ParseCancellationPolicy("24:100")
+		assert.NoError(t, err)
+
+		_, allowed := strict.RefundPercent(time.Hour)
+		assert.False(t, allowed)
+	})
+
+	t.Run("invalid_spec", func(t *testing.T) {
+		for _, spec := range []string{"", "24", "x:100", "24:150", "-1:10"} {
+			_, err := services.ParseCancellationPolicy(spec)
+			assert.ErrorIs(t, err, services.ErrInvalidCancellationPolicy, spec)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"cinemaGo/backend/internal/services"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceConcessions(t *testing.T) {
+	popcornStock, colaStock := 3, 10
+	concessionItems := []models.ConcessionItem{
+		{ConcessionItemID: 1, Name: "Large popcorn", Category: "Food", Price: uzs(700), Stock: &popcornStock, Active: true},
+		{ConcessionItemID: 2, Name: "Cola", Category: "Drink", Price: uzs(400), Stock: &colaStock, Active: true},
+		{ConcessionItemID: 3, Name: "Nachos", Category: "Food", Price: uzs(600), Active: true},
+		{ConcessionItemID: 4, Name: "Movie combo", Category: "Combo", Price: uzs(1000), Active: true, ComboItems: []models.ConcessionComboItem{
+			{ConcessionItemID: 1, Name: "Large popcorn", Quantity: 1},
+			{ConcessionItemID: 2, Name: "Cola", Quantity: 2},
+		}},
+		{ConcessionItemID: 5, Name: "Hot dog", Category: "Food", Price: uzs(500), Active: false},
+	}
+
+	t.Run("combo_uses_component_stock", func(t *testing.T) {
+		concessions, units, err := services.PriceConcessions(map[int]int{4: 2, 1: 1, 3: 4}, concessionItems)
+
+		assert.NoError(t, err)
+		assert.Len(t, concessions, 3)
+		assert.Equal(t, "Large popcorn", concessions[0].Name)
+		assert.Equal(t, uzs(1000), concessions[2].UnitPrice)
+		assert.Equal(t, map[int]int{1: 3, 2: 4}, units)
+	})
+
+	t.Run("out_of_stock", func(t *testing.T) {
+		_, _, err := services.PriceConcessions(map[int]int{4: 2, 1: 2}, concessionItems)
+
+		assert.ErrorIs(t, err, services.ErrConcessionOutOfStock)
+	})
+
+	t.Run("invalid_orders", func(t *testing.T) {
+		for _, order := range []map[int]int{{5: 1}, {9: 1}, {1: 0}, {3: services.MaxConcessionQuantity + 1}} {
+			_, _, err := services.PriceConcessions(order, concessionItems)
+
+			assert.ErrorIs(t, err, services.ErrInvalidConcessionOrder)
+		}
+	})
+}
+
+// fakeConcessionsDB keeps the concession items the admin service stores.
+type fakeConcessionsDB struct {
+	models.DBContractAdminCtrl
+
+	inserted []models.ConcessionItem
+}
+
+func (db *fakeConcessionsDB) RetrieveConcessionItems(activeOnly bool) ([]models.ConcessionItem, error) {
+	return nil, nil
+}
+
+func (db *fakeConcessionsDB) InsertNewConcessionItem(concessionItem models.ConcessionItem) error {
+	db.inserted = append(db.inserted, concessionItem)
+	return nil
+}
+
+func TestAddNewConcessionItemPrice(t *testing.T) {
+	// Prices typed on the admin page are stored exactly, never rounded through a float.
+	for input, expected := range map[string]int64{`19.99`: 1999, `"0.29"`: 29, `{"Amount": "4.35"}`: 435, `1005.10`: 100510} {
+		var price models.Money
+		assert.NoError(t, json.Unmarshal([]byte(input), &price), input)
+
+		db := &fakeConcessionsDB{}
+		err := services.NewAdminService(db, nil).AddNewConcessionItem("Popcorn", "", "Food", price, nil, true, nil)
+
+		assert.NoError(t, err, input)
+		if assert.Len(t, db.inserted, 1, input) {
+			assert.Equal(t, uzs(expected), db.inserted[0].Price, input)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicPricingPolicy(t *testing.T) {
+	policy, err := services.ParseDynamicPricingPolicy("50:10, 80:25, 0:-10", "6:10,1:20")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		occupancyPercent int
+		timeUntilShow    time.Duration
+		wantAdjustment   int
+		wantReason       string
+	}{
+		{"slow_show_far_away", 20, 48 * time.Hour, -10, "Dynamic pricing: 0% booked (-10%)"},
+		{"half_booked", 50, 48 * time.Hour, 10, "Dynamic pricing: 50% booked (+10%)"},
+		{"nearly_full", 95, 48 * time.Hour, 25, "Dynamic pricing: 80% booked (+25%)"},
+		{"nearly_full_and_soon", 85, 3 * time.Hour, 35, "Dynamic pricing: 80% booked (+25%), within 6h of the show (+10%)"},
+		{"closest_band_wins", 60, 30 * time.Minute, 30, "Dynamic pricing: 50% booked (+10%), within 1h of the show (+20%)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adjustment, reason := policy.Adjustment(tt.occupancyPercent, tt.timeUntilShow)
+
+			assert.Equal(t, tt.wantAdjustment, adjustment)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+
+	t.Run("no_rule_matches", func(t *testing.T) {
+		policy, err := services.ParseDynamicPricingPolicy("50:10", "")
+		assert.NoError(t, err)
+
+		adjustment, reason := policy.Adjustment(10, 48*time.Hour)
+
+		assert.Equal(t, 0, adjustment)
+		assert.Equal(t, "Dynamic pricing: base price", reason)
+	})
+
+	t.Run("invalid_specs", func(t *testing.T) {
+		for _, spec := range [][2]string{{"50", ""}, {"120:10", ""}, {"50:-100", ""}, {"", "x:10"}} {
+			_, err := services.ParseDynamicPricingPolicy(spec[0], spec[1])
+
+			assert.ErrorIs(t, err, services.ErrInvalidDynamicPricingPolicy)
+		}
+	})
+}
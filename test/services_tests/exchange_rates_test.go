@@ -0,0 +1,71 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"cinemaGo/backend/internal/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCurrency(t *testing.T) {
+	currency, err := services.ParseCurrency(" usd ")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", currency)
+
+	for _, input := range []string{"", "US", "USDT", "U$D"} {
+		_, err := services.ParseCurrency(input)
+
+		assert.ErrorIs(t, err, services.ErrInvalidCurrency, input)
+	}
+}
+
+func TestParseExchangeRate(t *testing.T) {
+	rate, err := services.ParseExchangeRate(" 12650.5 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "12650.5", rate)
+
+	for _, input := range []string{"", "0", "-1", "1e3", ".5", "1.12345678901"} {
+		_, err := services.ParseExchangeRate(input)
+
+		assert.ErrorIs(t, err, services.ErrInvalidExchangeRate, input)
+	}
+}
+
+func TestExchangeRatesConvert(t *testing.T) {
+	rates, err := services.NewExchangeRates([]models.ExchangeRate{
+		{Currency: "USD", Rate: "12650.5"},
+		{Currency: "KZT", Rate: "25.1"},
+		{Currency: "JPY", Rate: "84.3"},
+	})
+	assert.NoError(t, err)
+
+	// 19.99 USD is 252,883.495 sums, which rounds half away from zero.
+	converted, err := rates.Convert(models.NewMoney(1999, "USD"), "UZS")
+	assert.NoError(t, err)
+	assert.Equal(t, uzs(25288350), converted)
+
+	// 50,000 sums is 3.9524... dollars.
+	converted, err = rates.Convert(uzs(5000000), "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, models.NewMoney(395, "USD"), converted)
+
+	// The yen has no minor unit: 10 dollars is 126,505 sums, or 1,500.65... yen.
+	converted, err = rates.Convert(models.NewMoney(1000, "USD"), "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, models.NewMoney(1501, "JPY"), converted)
+
+	converted, err = rates.Convert(models.NewMoney(-1000, "USD"), "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, models.NewMoney(-1501, "JPY"), converted)
+
+	converted, err = rates.Convert(uzs(0), "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, models.NewMoney(0, "EUR"), converted)
+
+	_, err = rates.Convert(uzs(100), "EUR")
+	assert.ErrorIs(t, err, services.ErrExchangeRateNotFound)
+
+	_, err = services.NewExchangeRates([]models.ExchangeRate{{Currency: "USD", Rate: "0"}})
+	assert.ErrorIs(t, err, services.ErrInvalidExchangeRate)
+}
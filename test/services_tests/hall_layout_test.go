@@ -0,0 +1,157 @@
+package servicestests
+
+import (
+	"bytes"
+	"cinemaGo/backend/internal/models"
+	"cinemaGo/backend/internal/services"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateHallLayout(t *testing.T) {
+	seat := func(number, x int) models.HallLayoutSeat {
+		return models.HallLayoutSeat{SeatRow: "A", SeatNumber: number, SeatType: "Standard", GridX: x, GridY: 1}
+	}
+
+	hallLayout, err := services.ValidateHallLayout(models.HallLayout{
+		Columns: 5,
+		Rows:    1,
+		Seats:   []models.HallLayoutSeat{seat(1, 1), seat(2, 2)},
+		Markers: []models.HallLayoutMarker{{MarkerType: "aisle", GridX: 3, GridY: 1}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Top", hallLayout.ScreenPosition)
+	assert.Equal(t, models.HallLayoutMarker{MarkerType: "Aisle", GridX: 3, GridY: 1, Width: 1, Height: 1}, hallLayout.Markers[0])
+
+	invalid := map[string]models.HallLayout{
+		"outside_grid":   {Columns: 5, Rows: 1, Seats: []models.HallLayoutSeat{seat(1, 6)}},
+		"duplicate_seat": {Columns: 5, Rows: 1, Seats: []models.HallLayoutSeat{seat(1, 1), seat(1, 2)}},
+		"shared_cell":    {Columns: 5, Rows: 1, Seats: []models.HallLayoutSeat{seat(1, 1), seat(2, 1)}},
+		"marker_on_seat": {Columns: 5, Rows: 1, Seats: []models.HallLayoutSeat{seat(1, 1)}, Markers: []models.HallLayoutMarker{{MarkerType: "Stairs", GridX: 1, GridY: 1, Height: 1}}},
+		"unknown_screen": {Columns: 5, Rows: 1, ScreenPosition: "Left", Seats: []models.HallLayoutSeat{seat(1, 1)}},
+		"no_seats":       {Columns: 5, Rows: 1},
+	}
+	for name, hallLayout := range invalid {
+		_, err := services.ValidateHallLayout(hallLayout)
+
+		assert.ErrorIs(t, err, services.ErrInvalidHallLayout, name)
+	}
+}
+
+func TestBuildSeatGrid(t *testing.T) {
+	t.Run("uploaded_layout", func(t *testing.T) {
+		showSeats := []models.ShowSeat{
+			{ShowSeatID: 1, SeatRow: "A", SeatNumber: 1, SeatStatus: "Available", GridX: 1, GridY: 2},
+			{ShowSeatID: 2, SeatRow: "A", SeatNumber: 2, SeatStatus: "Booked", GridX: 3, GridY: 2},
+		}
+		hallLayout := models.HallLayout{Columns: 3, Rows: 2, ScreenPosition: "Bottom", Markers: []models.HallLayoutMarker{{MarkerType: "Aisle", GridX: 2, GridY: 1, Width: 1, Height: 2}}}
+
+		grid := services.BuildSeatGrid(hallLayout, showSeats)
+
+		assert.Equal(t, "Bottom", grid.ScreenPosition)
+		assert.Equal(t, []string{"Empty", "Aisle", "Empty"}, cellKinds(grid.Cells[0]))
+		assert.Equal(t, []string{"Seat", "Aisle", "Seat"}, cellKinds(grid.Cells[1]))
+		assert.Equal(t, 2, grid.Cells[1][2].Seat.ShowSeatID)
+	})
+
+	t.Run("derived_from_numbering", func(t *testing.T) {
+		showSeats := []models.ShowSeat{
+			{ShowSeatID: 1, SeatRow: "B", SeatNumber: 1},
+			{ShowSeatID: 2, SeatRow: "A", SeatNumber: 1},
+			{ShowSeatID: 3, SeatRow: "A", SeatNumber: 3},
+		}
+
+		grid := services.BuildSeatGrid(models.HallLayout{}, showSeats)
+
+		assert.Equal(t, 3, grid.Columns)
+		assert.Equal(t, 2, grid.Rows)
+		assert.Equal(t, []string{"Seat", "Empty", "Seat"}, cellKinds(grid.Cells[0]))
+		assert.Equal(t, []string{"Seat", "Empty", "Empty"}, cellKinds(grid.Cells[1]))
+		assert.Equal(t, 1, grid.Cells[1][0].Seat.ShowSeatID)
+	})
+
+}
+
+func TestRenderSeatMapSVG(t *testing.T) {
+	showSeats := []models.ShowSeat{
+		{ShowSeatID: 1, SeatRow: "A", SeatNumber: 1, SeatType: "VIP", SeatStatus: "Available", GridX: 1, GridY: 1},
+		{ShowSeatID: 2, SeatRow: "A", SeatNumber: 2, SeatType: "Standard", SeatStatus: "Booked", GridX: 3, GridY: 1, Angle: 10},
+		{ShowSeatID: 3, SeatRow: "B&C", SeatNumber: 1, SeatType: "Standard", SeatStatus: "Selected", GridX: 1, GridY: 2},
+	}
+	hallLayout := models.HallLayout{Columns: 3, Rows: 2, ScreenPosition: "Top", Markers: []models.HallLayoutMarker{{MarkerType: "Exit", GridX: 2, GridY: 1, Width: 1, Height: 1}}}
+
+	svg := services.RenderSeatMapSVG(services.BuildSeatGrid(hallLayout, showSeats), map[int]bool{2: true})
+
+	// The document must be well-formed XML, row labels included.
+	decoder := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+	}
+
+	document := string(svg)
+	assert.Contains(t, document, `<title>A1, VIP, available</title>`)
+	assert.Contains(t, document, `fill="#8e44ad" stroke="#ffffff"`)
+	assert.Contains(t, document, `fill="#9e9e9e" stroke="#e91e63" stroke-width="3"`)
+	assert.Contains(t, document, `rotate(10 `)
+	assert.Contains(t, document, `>B&amp;C</text>`)
+	assert.Contains(t, document, `>EXIT</text>`)
+	assert.Contains(t, document, `>Your seats</text>`)
+}
+
+func TestGenerateHallLayout(t *testing.T) {
+	hallLayout, err := services.GenerateHallLayout(models.HallSeatSpec{
+		HallID:          4,
+		RowFrom:         "a",
+		RowTo:           "C",
+		SeatFrom:        1,
+		SeatTo:          4,
+		VIPRows:         []string{"C"},
+		AccessibleSeats: []string{"c1", "A4"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, hallLayout.Columns)
+	assert.Equal(t, 3, hallLayout.Rows)
+	assert.Len(t, hallLayout.Seats, 12)
+	assert.Equal(t, models.HallLayoutSeat{SeatRow: "A", SeatNumber: 4, SeatType: "Accessible", GridX: 4, GridY: 1}, hallLayout.Seats[3])
+	assert.Equal(t, models.HallLayoutSeat{SeatRow: "C", SeatNumber: 1, SeatType: "Accessible", GridX: 1, GridY: 3}, hallLayout.Seats[8])
+	assert.Equal(t, "VIP", hallLayout.Seats[9].SeatType)
+	assert.Equal(t, "Standard", hallLayout.Seats[4].SeatType)
+
+	// The generated layout is a valid upload, so it can be edited afterwards.
+	_, err = services.ValidateHallLayout(hallLayout)
+	assert.NoError(t, err)
+
+	invalid := map[string]models.HallSeatSpec{
+		"rows_backwards":     {RowFrom: "M", RowTo: "A", SeatFrom: 1, SeatTo: 20},
+		"double_letter_row":  {RowFrom: "A", RowTo: "AA", SeatFrom: 1, SeatTo: 20},
+		"no_seats":           {RowFrom: "A", RowTo: "M", SeatFrom: 5, SeatTo: 4},
+		"vip_row_outside":    {RowFrom: "A", RowTo: "M", SeatFrom: 1, SeatTo: 20, VIPRows: []string{"N"}},
+		"accessible_outside": {RowFrom: "A", RowTo: "M", SeatFrom: 1, SeatTo: 20, AccessibleSeats: []string{"M21"}},
+		"accessible_suffix":  {RowFrom: "A", RowTo: "M", SeatFrom: 1, SeatTo: 20, AccessibleSeats: []string{"A5x"}},
+		"accessible_list":    {RowFrom: "A", RowTo: "M", SeatFrom: 1, SeatTo: 20, AccessibleSeats: []string{"A5,6"}},
+	}
+	for name, spec := range invalid {
+		_, err := services.GenerateHallLayout(spec)
+
+		assert.ErrorIs(t, err, services.ErrInvalidHallSeatSpec, name)
+	}
+}
+
+func cellKinds(cells []models.SeatGridCell) []string {
+	var kinds []string
+	for _, cell := range cells {
+		kinds = append(kinds, cell.Kind)
+	}
+	return kinds
+}
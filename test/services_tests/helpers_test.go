@@ -0,0 +1,8 @@
+package servicestests
+
+import "cinemaGo/backend/internal/models"
+
+// uzs returns an amount in minor units of the default currency.
+func uzs(amount int64) models.Money {
+	return models.NewMoney(amount, models.DefaultCurrency)
+}
@@ -0,0 +1,116 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"cinemaGo/backend/internal/services"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyService(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	defer db.Close()
+
+	service := services.NewIdempotencyService(&models.Postgres{DB: db}, 24, 120)
+
+	body := []byte(`{"show_id":3,"show_seats_id":[1,2]}`)
+	sum := sha256.Sum256(body)
+	bodyHash := hex.EncodeToString(sum[:])
+
+	t.Run("first_request", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO idempotency_key").WithArgs(7, "key-1", bodyHash, 24, 120, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"claim_token"}).AddRow("token-1"))
+
+		claim, replay, err := service.BeginRequest(7, "key-1", body)
+
+		assert.NoError(t, err)
+		assert.False(t, replay)
+		assert.Equal(t, "token-1", claim.ClaimToken)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("completed_with_claim", func(t *testing.T) {
+		mock.ExpectExec("UPDATE idempotency_key SET status_code = \\$1, response_body = \\$2 WHERE .* AND claim_token = \\$5").
+			WithArgs(201, []byte(`{"message":"ok"}`), 7, "key-1", "token-1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := service.CompleteRequest(7, "key-1", "token-1", 201, []byte(`{"message":"ok"}`))
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("claim_taken_over", func(t *testing.T) {
+		// The request outlived its lease and a retry claimed the key with a new token.
+		mock.ExpectExec("UPDATE idempotency_key SET status_code").
+			WithArgs(201, []byte(`{}`), 7, "key-1", "token-1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM idempotency_key WHERE .* AND claim_token = \\$3").
+			WithArgs(7, "key-1", "token-1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := service.CompleteRequest(7, "key-1", "token-1", 201, []byte(`{}`))
+
+		assert.ErrorIs(t, err, models.ErrIdempotencyKeyNotFound)
+		assert.NoError(t, service.AbandonRequest(7, "key-1", "token-1"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("replayed_retry", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO idempotency_key").WillReturnRows(sqlmock.NewRows([]string{"claim_token"}))
+		mock.ExpectQuery("SELECT request_hash").WithArgs(7, "key-1").
+			WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "response_body"}).AddRow(bodyHash, 201, []byte(`{"message":"ok"}`)))
+
+		stored, replay, err := service.BeginRequest(7, "key-1", body)
+
+		assert.NoError(t, err)
+		assert.True(t, replay)
+		assert.Equal(t, 201, stored.StatusCode)
+		assert.Equal(t, `{"message":"ok"}`, string(stored.ResponseBody))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("still_in_progress", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO idempotency_key").WillReturnRows(sqlmock.NewRows([]string{"claim_token"}))
+		mock.ExpectQuery("SELECT request_hash").
+			WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "response_body"}).AddRow(bodyHash, 0, []byte{}))
+
+		_, _, err := service.BeginRequest(7, "key-1", body)
+
+		assert.ErrorIs(t, err, services.ErrIdempotencyKeyInProgress)
+	})
+
+	t.Run("different_body", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO idempotency_key").WillReturnRows(sqlmock.NewRows([]string{"claim_token"}))
+		mock.ExpectQuery("SELECT request_hash").
+			WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "response_body"}).AddRow(bodyHash, 201, []byte(`{}`)))
+
+		_, _, err := service.BeginRequest(7, "key-1", []byte(`{"show_id":3,"show_seats_id":[4]}`))
+
+		assert.ErrorIs(t, err, services.ErrIdempotencyKeyReused)
+	})
+
+	t.Run("abandoned_request", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM idempotency_key").WithArgs(7, "key-1", "token-1").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("INSERT INTO idempotency_key").WithArgs(7, "key-1", bodyHash, 24, 120, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"claim_token"}).AddRow("token-2"))
+
+		assert.NoError(t, service.AbandonRequest(7, "key-1", "token-1"))
+		_, replay, err := service.BeginRequest(7, "key-1", body)
+
+		assert.NoError(t, err)
+		assert.False(t, replay)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("invalid_key", func(t *testing.T) {
+		_, _, err := service.BeginRequest(7, string(make([]byte, 256)), body)
+
+		assert.ErrorIs(t, err, services.ErrInvalidIdempotencyKey)
+	})
+}
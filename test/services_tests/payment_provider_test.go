@@ -0,0 +1,73 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakePaymentProvider(t *testing.T) {
+	provider := services.NewFakePaymentProvider("webhook-secret")
+
+	t.Run("authorize_capture_refund", func(t *testing.T) {
+		transactionID, err := provider.Authorize(11, uzs(1600))
+		assert.NoError(t, err)
+		assert.Equal(t, "fake_txn_11_1600", transactionID)
+
+		assert.NoError(t, provider.Capture(transactionID, uzs(1600)))
+		assert.NoError(t, provider.Void(transactionID))
+
+		refundID, err := provider.Refund(transactionID, uzs(800), "booking-11-refund")
+		assert.NoError(t, err)
+		assert.Equal(t, "fake_refund_11_1600_800", refundID)
+	})
+
+	t.Run("authorize_declined", func(t *testing.T) {
+		transactionID, err := provider.Authorize(11, uzs(0))
+		assert.ErrorIs(t, err, services.ErrPaymentDeclined)
+		assert.Empty(t, transactionID)
+	})
+
+	t.Run("unknown_transaction", func(t *testing.T) {
+		assert.ErrorIs(t, provider.Capture("other_txn", uzs(1600)), services.ErrPaymentNotFound)
+		assert.ErrorIs(t, provider.Void("other_txn"), services.ErrPaymentNotFound)
+	})
+
+	t.Run("valid_webhook", func(t *testing.T) {
+		payload := []byte(`{"transaction_id":"fake_txn_11_1600","status":"succeeded"}`)
+
+		event, err := provider.VerifyWebhook(payload, provider.SignWebhook(payload))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "fake_txn_11_1600", event.RemoteTransactionID)
+		assert.Equal(t, "succeeded", event.Status)
+	})
+
+	t.Run("tampered_webhook", func(t *testing.T) {
+		signature := provider.SignWebhook([]byte(`{"transaction_id":"fake_txn_11_1600","status":"failed"}`))
+
+		_, err := provider.VerifyWebhook([]byte(`{"transaction_id":"fake_txn_11_1600","status":"succeeded"}`), signature)
+
+		assert.ErrorIs(t, err, services.ErrInvalidWebhookSignature)
+	})
+
+	t.Run("unknown_status", func(t *testing.T) {
+		payload := []byte(`{"transaction_id":"fake_txn_11_1600","status":"pending"}`)
+
+		_, err := provider.VerifyWebhook(payload, provider.SignWebhook(payload))
+
+		assert.ErrorIs(t, err, services.ErrInvalidWebhookPayload)
+	})
+}
+
+func TestNewPaymentProvider(t *testing.T) {
+	provider, err := services.NewPaymentProvider("fake", "webhook-secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "Fake", provider.Name())
+
+	for _, name := range []string{"", "stripe"} {
+		_, err := services.NewPaymentProvider(name, "webhook-secret")
+		assert.ErrorIs(t, err, services.ErrUnknownPaymentProvider, name)
+	}
+}
@@ -0,0 +1,59 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"cinemaGo/backend/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPriceRule(t *testing.T) {
+	friday := 5
+	priceRules := []models.PriceRule{
+		{PriceRuleID: 1, RuleName: "Standard", Price: uzs(1000)},
+		{PriceRuleID: 2, RuleName: "VIP", SeatType: "VIP", Price: uzs(1800)},
+		{PriceRuleID: 3, RuleName: "Matinee", StartTimeFrom: "10:00", StartTimeUntil: "14:00", Price: uzs(700)},
+		{PriceRuleID: 4, RuleName: "Friday late-night VIP", SeatType: "VIP", DayOfWeek: &friday, StartTimeFrom: "22:00", StartTimeUntil: "02:00", Price: uzs(2200)},
+		{PriceRuleID: 5, RuleName: "IMAX", HallType: "IMAX", Price: uzs(1500)},
+	}
+
+	// 2026-11-06 is a Friday, 2026-11-04 a Wednesday.
+	friDate := time.Date(2026, 11, 6, 0, 0, 0, 0, time.UTC)
+	wedDate := time.Date(2026, 11, 4, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time { return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		hallType  string
+		seatType  string
+		showDate  time.Time
+		startTime time.Time
+		wantRule  int
+	}{
+		{"catch_all", "Regular", "Standard", wedDate, at(18, 0), 1},
+		{"seat_type", "Regular", "vip", wedDate, at(18, 0), 2},
+		{"start_time_band", "Regular", "Standard", wedDate, at(10, 0), 3},
+		{"band_end_is_exclusive", "Regular", "Standard", wedDate, at(14, 0), 1},
+		{"most_specific_wins", "Regular", "VIP", friDate, at(23, 15), 4},
+		{"band_wraps_past_midnight", "Regular", "VIP", friDate, at(1, 30), 4},
+		{"other_weekday", "Regular", "VIP", wedDate, at(23, 15), 2},
+		{"hall_type", "IMAX", "Standard", wedDate, at(18, 0), 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priceRule, ok := services.MatchPriceRule(priceRules, tt.hallType, tt.seatType, tt.showDate, tt.startTime)
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantRule, priceRule.PriceRuleID)
+		})
+	}
+
+	t.Run("no_rule_matches", func(t *testing.T) {
+		_, ok := services.MatchPriceRule(priceRules[1:2], "Regular", "Standard", wedDate, at(18, 0))
+
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,103 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"cinemaGo/backend/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPromoCode(t *testing.T) {
+	now := time.Date(2026, 11, 2, 18, 0, 0, 0, time.UTC)
+	show := models.ShowMovieHall{ShowID: 3, MovieID: 9, HallType: "IMAX"}
+	showSeats := []models.ShowSeat{
+		{ShowSeatID: 1, SeatRow: "A", SeatNumber: 5, SeatType: "VIP", SeatPrice: uzs(2000)},
+		{ShowSeatID: 2, SeatRow: "A", SeatNumber: 6, SeatType: "Standard", SeatPrice: uzs(1000)},
+	}
+	quote := models.PriceQuote{
+		ShowID: 3,
+		Items: []models.PriceQuoteItem{
+			{ItemType: "Seat", ShowSeatID: 1, Amount: uzs(2000)},
+			{ItemType: "Seat", ShowSeatID: 2, Amount: uzs(1000)},
+			{ItemType: "Fee", Amount: uzs(200)},
+		},
+		Subtotal: uzs(3000),
+		Fees:     uzs(200),
+		Total:    uzs(3200),
+	}
+
+	activeCode := func(discountType string, value int) models.PromoCode {
+		promoCode := models.PromoCode{
+			PromoCodeID:  1,
+			Code:         "SUMMER25",
+			DiscountType: discountType,
+			ValidFrom:    now.Add(-time.Hour),
+			ValidUntil:   now.Add(time.Hour),
+		}
+		if discountType == "Percentage" {
+			promoCode.DiscountPercent = value
+		} else {
+			promoCode.DiscountAmount = uzs(int64(value))
+		}
+		return promoCode
+	}
+
+	t.Run("percentage_of_seats_only", func(t *testing.T) {
+		discounted, err := services.ApplyPromoCode(quote, showSeats, activeCode("Percentage", 25), show, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uzs(750), discounted.Discount)
+		assert.Equal(t, uzs(2450), discounted.Total)
+		assert.Equal(t, "SUMMER25", discounted.PromoCode)
+		assert.Equal(t, uzs(-750), discounted.Items[len(discounted.Items)-1].Amount)
+	})
+
+	t.Run("fixed_capped_at_seat_price", func(t *testing.T) {
+		discounted, err := services.ApplyPromoCode(quote, showSeats, activeCode("Fixed", 5000), show, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uzs(3000), discounted.Discount)
+		assert.Equal(t, uzs(200), discounted.Total)
+	})
+
+	t.Run("seat_type_restriction", func(t *testing.T) {
+		promoCode := activeCode("Percentage", 50)
+		promoCode.SeatType = "vip"
+
+		discounted, err := services.ApplyPromoCode(quote, showSeats, promoCode, show, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uzs(1000), discounted.Discount)
+	})
+
+	t.Run("outside_validity_window", func(t *testing.T) {
+		_, err := services.ApplyPromoCode(quote, showSeats, activeCode("Percentage", 25), show, now.Add(2*time.Hour))
+
+		assert.ErrorIs(t, err, services.ErrPromoCodeNotActive)
+	})
+
+	t.Run("usage_cap_reached", func(t *testing.T) {
+		promoCode := activeCode("Percentage", 25)
+		promoCode.MaxUses, promoCode.TimesUsed = 10, 10
+
+		_, err := services.ApplyPromoCode(quote, showSeats, promoCode, show, now)
+
+		assert.ErrorIs(t, err, services.ErrPromoCodeUsageLimitReached)
+	})
+
+	t.Run("other_movie_or_hall", func(t *testing.T) {
+		promoCode := activeCode("Percentage", 25)
+		promoCode.MovieID = 4
+
+		_, err := services.ApplyPromoCode(quote, showSeats, promoCode, show, now)
+		assert.ErrorIs(t, err, services.ErrPromoCodeNotApplicable)
+
+		promoCode = activeCode("Percentage", 25)
+		promoCode.HallType = "3D"
+
+		_, err = services.ApplyPromoCode(quote, showSeats, promoCode, show, now)
+		assert.ErrorIs(t, err, services.ErrPromoCodeNotApplicable)
+	})
+}
@@ -0,0 +1,78 @@
+package servicestests
+
+import (
+	"bytes"
+	"cinemaGo/backend/internal/models"
+	"cinemaGo/backend/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVATRate(t *testing.T) {
+	rate, err := services.ParseVATRate("12")
+	assert.NoError(t, err)
+	assert.Equal(t, 1200, rate)
+
+	rate, err = services.ParseVATRate(" 7.5 ")
+	assert.NoError(t, err)
+	assert.Equal(t, 750, rate)
+
+	for _, input := range []string{"", "twelve", "-1", "100.5"} {
+		_, err := services.ParseVATRate(input)
+
+		assert.ErrorIs(t, err, services.ErrInvalidVATRate, input)
+	}
+}
+
+func TestVATIncluded(t *testing.T) {
+	assert.Equal(t, uzs(120), services.VATIncluded(uzs(1120), 1200))
+	assert.Equal(t, uzs(1667), services.VATIncluded(uzs(10000), 2000))
+	assert.Equal(t, uzs(0), services.VATIncluded(uzs(1120), 0))
+	assert.Equal(t, uzs(0), services.VATIncluded(uzs(0), 1200))
+}
+
+func TestReceiptFormatting(t *testing.T) {
+	assert.Equal(t, "RCPT-000042", services.FormatReceiptNumber(42))
+	assert.Equal(t, "7.5%", services.FormatVATRate(750))
+}
+
+func TestBuildReceipt(t *testing.T) {
+	booking := models.BookingDetail{
+		BookingHistoryEntry: models.BookingHistoryEntry{
+			BookingID:        7,
+			BookingReference: "CG-7KQ2MX",
+			MovieTitle:       "Dune (Part Two)",
+			HallName:         "Hall 1",
+			ShowDate:         "2026-11-02",
+			ShowStartTime:    "18:30",
+			PaymentStatus:    "Captured",
+		},
+		Items: []models.PriceQuoteItem{
+			{ItemType: "Seat", Description: "Row A, seat 1", Amount: uzs(5000)},
+			{ItemType: "Seat", Description: "Row A, seat 2", Amount: uzs(5000)},
+			{ItemType: "Fee", Description: "Booking fee", Amount: uzs(400)},
+			{ItemType: "Discount", Description: "Promo SAVE10", Amount: uzs(-1000)},
+		},
+		Subtotal: uzs(10000),
+		Fees:     uzs(400),
+		Discount: uzs(1000),
+		Total:    uzs(9400),
+	}
+
+	receipt := services.BuildReceipt(booking, models.ReceiptRecord{ReceiptNumber: 3, BookingID: 7, VATRate: 1200, IssuedAt: time.Now()})
+
+	assert.Equal(t, "RCPT-000003", receipt.ReceiptNumber)
+	assert.Equal(t, uzs(9400), receipt.Total)
+	assert.Equal(t, uzs(1007), receipt.VATAmount)
+	assert.Equal(t, uzs(8393), receipt.NetAmount)
+	assert.Len(t, receipt.Items, 4)
+
+	pdf := services.RenderReceiptPDF(receipt)
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), `(Show: Dune \(Part Two\), 2026-11-02 18:30, Hall 1) Tj`)
+	assert.Contains(t, string(pdf), "(-10.00) Tj")
+}
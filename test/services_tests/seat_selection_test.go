@@ -0,0 +1,154 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"cinemaGo/backend/internal/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hallSeats builds a show with the given rows of seats numbered from 1. A seat marked 'x' is
+// booked, 'v' is an available VIP seat and any other character is an available standard seat.
+func hallSeats(rows map[string]string) []models.ShowSeat {
+	var showSeats []models.ShowSeat
+	id := 1
+	for row, layout := range rows {
+		for i, mark := range layout {
+			showSeat := models.ShowSeat{ShowSeatID: id, SeatRow: row, SeatNumber: i + 1, SeatType: "Standard", SeatStatus: "Available", SeatPrice: uzs(1000)}
+			switch mark {
+			case 'x':
+				showSeat.SeatStatus = "Booked"
+			case 'v':
+				showSeat.SeatType = "VIP"
+			}
+			showSeats = append(showSeats, showSeat)
+			id++
+		}
+	}
+	return showSeats
+}
+
+func seatLabels(showSeats []models.ShowSeat) []string {
+	var labels []string
+	for _, showSeat := range showSeats {
+		labels = append(labels, showSeat.SeatRow+string(rune('0'+showSeat.SeatNumber)))
+	}
+	return labels
+}
+
+func TestSuggestAdjacentSeats(t *testing.T) {
+	t.Run("centre_of_empty_hall", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": "........", "B": "........", "C": "........"})
+
+		best, err := services.SuggestAdjacentSeats(showSeats, 2, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"B4", "B5"}, seatLabels(best))
+	})
+
+	t.Run("skips_booked_seats", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": "........", "B": "..xxxx..", "C": "........"})
+
+		best, err := services.SuggestAdjacentSeats(showSeats, 3, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A3", "A4", "A5"}, seatLabels(best))
+	})
+
+	t.Run("gap_breaks_adjacency", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": "..x..x.."})
+
+		_, err := services.SuggestAdjacentSeats(showSeats, 3, "")
+
+		assert.ErrorIs(t, err, services.ErrNoAdjacentSeats)
+	})
+
+	t.Run("seat_type_filter", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": "........", "B": "........", "C": "vvv....."})
+
+		best, err := services.SuggestAdjacentSeats(showSeats, 2, "vip")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"C2", "C3"}, seatLabels(best))
+	})
+}
+
+func TestPickWaitlistSeats(t *testing.T) {
+	t.Run("adjacent_first", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": ".xxxxxx.", "B": "xxx..xxx"})
+
+		assert.Equal(t, []string{"B4", "B5"}, seatLabels(services.PickWaitlistSeats(showSeats, 2)))
+	})
+
+	t.Run("scattered_seats_from_the_front", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": ".xxxxxx.", "B": "xxx.xxxx"})
+
+		assert.Equal(t, []string{"A1", "A8"}, seatLabels(services.PickWaitlistSeats(showSeats, 2)))
+	})
+
+	t.Run("not_enough_seats", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": ".xxxxxxx", "B": "xxxxxxxx"})
+
+		assert.Nil(t, services.PickWaitlistSeats(showSeats, 2))
+	})
+}
+
+func seatIDs(showSeats []models.ShowSeat, labels ...string) []int {
+	var ids []int
+	for _, label := range labels {
+		for _, showSeat := range showSeats {
+			if showSeat.SeatRow+string(rune('0'+showSeat.SeatNumber)) == label {
+				ids = append(ids, showSeat.ShowSeatID)
+			}
+		}
+	}
+	return ids
+}
+
+func TestCheckSingleSeatGaps(t *testing.T) {
+	t.Run("no_gap", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": "x.....x"})
+
+		assert.NoError(t, services.CheckSingleSeatGaps(showSeats, seatIDs(showSeats, "A2", "A3")))
+		assert.NoError(t, services.CheckSingleSeatGaps(showSeats, seatIDs(showSeats, "A5", "A6")))
+	})
+
+	t.Run("gap_next_to_booked_seat", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": "x......"})
+
+		err := services.CheckSingleSeatGaps(showSeats, seatIDs(showSeats, "A3", "A4"))
+
+		assert.ErrorIs(t, err, services.ErrSingleSeatGap)
+		assert.Contains(t, err.Error(), "seat A2 would be left empty")
+		assert.Contains(t, err.Error(), "choose A2, A3 instead")
+		assert.Contains(t, err.Error(), "add seat A2 to your selection")
+	})
+
+	t.Run("gap_at_row_end", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": "......"})
+
+		err := services.CheckSingleSeatGaps(showSeats, seatIDs(showSeats, "A4", "A5"))
+
+		assert.ErrorIs(t, err, services.ErrSingleSeatGap)
+		assert.Contains(t, err.Error(), "seat A6 would be left empty")
+	})
+
+	t.Run("existing_gap_is_allowed", func(t *testing.T) {
+		showSeats := hallSeats(map[string]string{"A": "x.x...."})
+
+		assert.NoError(t, services.CheckSingleSeatGaps(showSeats, seatIDs(showSeats, "A4", "A5")))
+	})
+
+	t.Run("aisle_on_layout", func(t *testing.T) {
+		// A1 | aisle | A2 A3 A4: taking A2-A3 leaves A1 on its own, but across the aisle from them.
+		showSeats := []models.ShowSeat{
+			{ShowSeatID: 1, SeatRow: "A", SeatNumber: 1, SeatStatus: "Available", SeatPrice: uzs(1000), GridX: 1, GridY: 1},
+			{ShowSeatID: 2, SeatRow: "A", SeatNumber: 2, SeatStatus: "Available", SeatPrice: uzs(1000), GridX: 3, GridY: 1},
+			{ShowSeatID: 3, SeatRow: "A", SeatNumber: 3, SeatStatus: "Available", SeatPrice: uzs(1000), GridX: 4, GridY: 1},
+			{ShowSeatID: 4, SeatRow: "A", SeatNumber: 4, SeatStatus: "Booked", SeatPrice: uzs(1000), GridX: 5, GridY: 1},
+		}
+
+		assert.NoError(t, services.CheckSingleSeatGaps(showSeats, []int{2, 3}))
+	})
+}
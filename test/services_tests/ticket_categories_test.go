@@ -0,0 +1,62 @@
+package servicestests
+
+import (
+	"cinemaGo/backend/internal/models"
+	"cinemaGo/backend/internal/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinimumAge(t *testing.T) {
+	tests := map[string]int{
+		"":      0,
+		"U":     0,
+		"UA":    0,
+		"UA13+": 13,
+		"PG-13": 13,
+		"18+":   18,
+		"A":     18,
+	}
+
+	for ageLimit, want := range tests {
+		assert.Equal(t, want, services.MinimumAge(ageLimit), ageLimit)
+	}
+}
+
+func TestAssignTicketCategories(t *testing.T) {
+	ticketCategories := []models.TicketCategory{
+		{TicketCategoryID: 1, Name: "Adult", PricePercent: 100},
+		{TicketCategoryID: 2, Name: "Child", PricePercent: 60, MaxAge: 12},
+		{TicketCategoryID: 3, Name: "Senior", PricePercent: 75, MinAge: 65},
+	}
+
+	t.Run("defaults_to_adult", func(t *testing.T) {
+		tickets, err := services.AssignTicketCategories([]int{1, 2}, map[int]string{2: "child"}, ticketCategories, "UA")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Adult", tickets[1].Name)
+		assert.Equal(t, "Child", tickets[2].Name)
+	})
+
+	t.Run("child_ticket_for_adult_movie", func(t *testing.T) {
+		_, err := services.AssignTicketCategories([]int{1, 2}, map[int]string{2: "Child"}, ticketCategories, "18+")
+
+		assert.ErrorIs(t, err, services.ErrTicketCategoryAgeRestricted)
+	})
+
+	t.Run("senior_ticket_for_adult_movie", func(t *testing.T) {
+		tickets, err := services.AssignTicketCategories([]int{1}, map[int]string{1: "Senior"}, ticketCategories, "A")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 75, tickets[1].PricePercent)
+	})
+
+	t.Run("unknown_category_or_seat", func(t *testing.T) {
+		_, err := services.AssignTicketCategories([]int{1}, map[int]string{1: "Infant"}, ticketCategories, "U")
+		assert.ErrorIs(t, err, services.ErrInvalidTicketCategory)
+
+		_, err = services.AssignTicketCategories([]int{1}, map[int]string{9: "Child"}, ticketCategories, "U")
+		assert.ErrorIs(t, err, services.ErrInvalidTicketCategory)
+	})
+}
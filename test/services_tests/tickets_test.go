@@ -0,0 +1,67 @@
+package servicestests
+
+import (
+	"bytes"
+	"cinemaGo/backend/internal/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketSigner(t *testing.T) {
+	signer := services.NewTicketSigner("ticket-key")
+
+	t.Run("sign_and_verify", func(t *testing.T) {
+		token, err := signer.Sign(11, 3, 7)
+		assert.NoError(t, err)
+
+		claims, err := signer.Verify(token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 11, claims.BookingID)
+		assert.Equal(t, 3, claims.ShowID)
+		assert.Equal(t, 7, claims.UserID)
+	})
+
+	t.Run("tampered_token", func(t *testing.T) {
+		token, err := signer.Sign(11, 3, 7)
+		assert.NoError(t, err)
+
+		// Flip a character of the signature.
+		tampered := []byte(token)
+		if tampered[len(tampered)-2] == 'A' {
+			tampered[len(tampered)-2] = 'B'
+		} else {
+			tampered[len(tampered)-2] = 'A'
+		}
+
+		_, err = signer.Verify(string(tampered))
+
+		assert.ErrorIs(t, err, services.ErrInvalidTicket)
+	})
+
+	t.Run("other_key", func(t *testing.T) {
+		token, err := services.NewTicketSigner("other-key").Sign(11, 3, 7)
+		assert.NoError(t, err)
+
+		_, err = signer.Verify(token)
+
+		assert.ErrorIs(t, err, services.ErrInvalidTicket)
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		_, err := signer.Verify("not-a-ticket")
+
+		assert.ErrorIs(t, err, services.ErrInvalidTicket)
+	})
+
+	t.Run("qr_code", func(t *testing.T) {
+		token, err := signer.Sign(11, 3, 7)
+		assert.NoError(t, err)
+
+		png, err := services.TicketQRCode(token)
+
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(png, []byte("\x89PNG")))
+	})
+}